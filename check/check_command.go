@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/blang/semver"
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
@@ -15,27 +17,50 @@ import (
 //go:generate counterfeiter --fake-name FakeFilter . filter
 type filter interface {
 	ReleasesByReleaseType(releases []pivnet.Release, releaseType pivnet.ReleaseType) ([]pivnet.Release, error)
+	ReleasesByReleaseTypes(releases []pivnet.Release, releaseTypes []pivnet.ReleaseType) ([]pivnet.Release, error)
+	ReleasesExcludingReleaseTypes(releases []pivnet.Release, releaseTypes []pivnet.ReleaseType) ([]pivnet.Release, error)
 	ReleasesByVersion(releases []pivnet.Release, version string) ([]pivnet.Release, error)
+	ReleasesByVersionConstraint(releases []pivnet.Release, constraint string) ([]pivnet.Release, error)
+	ReleasesReleasedAfter(releases []pivnet.Release, date string) ([]pivnet.Release, error)
+	ReleasesByAvailability(releases []pivnet.Release, availability string) ([]pivnet.Release, error)
+	ReleasesSupportedAt(releases []pivnet.Release, at time.Time) ([]pivnet.Release, error)
+	ReleasesNotControlled(releases []pivnet.Release) ([]pivnet.Release, error)
 }
 
 //go:generate counterfeiter --fake-name FakeSorter . sorter
 type sorter interface {
 	SortBySemver([]pivnet.Release) ([]pivnet.Release, error)
+	SortByReleaseDate([]pivnet.Release) ([]pivnet.Release, error)
+}
+
+//go:generate counterfeiter --fake-name FakeSemverConverter . semverConverter
+type semverConverter interface {
+	ToValidSemver(string) (semver.Version, error)
+}
+
+//go:generate counterfeiter --fake-name FakeFingerprinter . fingerprinter
+type fingerprinter interface {
+	Fingerprint(productSlug string, release pivnet.Release) (string, error)
 }
 
 //go:generate counterfeiter --fake-name FakePivnetClient . pivnetClient
 type pivnetClient interface {
 	ReleaseTypes() ([]pivnet.ReleaseType, error)
 	ReleasesForProductSlug(string) ([]pivnet.Release, error)
+	GetRelease(productSlug string, version string) (pivnet.Release, error)
+	ReleaseDependencies(productSlug string, releaseID int) ([]pivnet.ReleaseDependency, error)
+	ReleaseUpgradePaths(productSlug string, releaseID int) ([]pivnet.ReleaseUpgradePath, error)
 }
 
 type CheckCommand struct {
-	logger        logger.Logger
-	binaryVersion string
-	filter        filter
-	pivnetClient  pivnetClient
-	semverSorter  sorter
-	logFilePath   string
+	logger          logger.Logger
+	binaryVersion   string
+	filter          filter
+	pivnetClient    pivnetClient
+	releaseSorter   sorter
+	semverConverter semverConverter
+	fingerprinter   fingerprinter
+	logFilePath     string
 }
 
 func NewCheckCommand(
@@ -43,16 +68,20 @@ func NewCheckCommand(
 	binaryVersion string,
 	filter filter,
 	pivnetClient pivnetClient,
-	semverSorter sorter,
+	releaseSorter sorter,
+	semverConverter semverConverter,
+	fingerprinter fingerprinter,
 	logFilePath string,
 ) *CheckCommand {
 	return &CheckCommand{
-		logger:        logger,
-		binaryVersion: binaryVersion,
-		filter:        filter,
-		pivnetClient:  pivnetClient,
-		semverSorter:  semverSorter,
-		logFilePath:   logFilePath,
+		logger:          logger,
+		binaryVersion:   binaryVersion,
+		filter:          filter,
+		pivnetClient:    pivnetClient,
+		releaseSorter:   releaseSorter,
+		semverConverter: semverConverter,
+		fingerprinter:   fingerprinter,
+		logFilePath:     logFilePath,
 	}
 }
 
@@ -65,16 +94,34 @@ func (c *CheckCommand) Run(input concourse.CheckRequest) (concourse.CheckRespons
 	}
 
 	releaseType := input.Source.ReleaseType
+	releaseTypes := input.Source.ReleaseTypes
+	excludeReleaseTypes := input.Source.ExcludeReleaseTypes
+
+	var allReleaseTypes []string
+	if releaseType != "" {
+		allReleaseTypes = append(allReleaseTypes, releaseType)
+	}
+	allReleaseTypes = append(allReleaseTypes, releaseTypes...)
+	allReleaseTypes = append(allReleaseTypes, excludeReleaseTypes...)
 
-	err = c.validateReleaseType(releaseType)
+	err = c.validateReleaseTypes(allReleaseTypes)
 	if err != nil {
 		return nil, err
 	}
 
 	productSlug := input.Source.ProductSlug
+	parentProductSlug := input.Source.ParentProductSlug
+
+	// When following a parent product, the source configuration selects the
+	// release of the parent product rather than of the product itself.
+	releasesSlug := productSlug
+	if parentProductSlug != "" {
+		c.logger.Info(fmt.Sprintf("Following dependencies of parent product: '%s'", parentProductSlug))
+		releasesSlug = parentProductSlug
+	}
 
 	c.logger.Info("Getting all releases")
-	releases, err := c.pivnetClient.ReleasesForProductSlug(productSlug)
+	releases, err := c.pivnetClient.ReleasesForProductSlug(releasesSlug)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +137,47 @@ func (c *CheckCommand) Run(input concourse.CheckRequest) (concourse.CheckRespons
 		}
 	}
 
+	if len(releaseTypes) > 0 {
+		c.logger.Info(fmt.Sprintf("Filtering all releases by release types: %v", releaseTypes))
+		releases, err = c.filter.ReleasesByReleaseTypes(releases, toReleaseTypes(releaseTypes))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(excludeReleaseTypes) > 0 {
+		c.logger.Info(fmt.Sprintf("Excluding all releases with release types: %v", excludeReleaseTypes))
+		releases, err = c.filter.ReleasesExcludingReleaseTypes(releases, toReleaseTypes(excludeReleaseTypes))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	availability := input.Source.Availability
+	if availability != "" {
+		c.logger.Info(fmt.Sprintf("Filtering all releases by availability: '%s'", availability))
+		releases, err = c.filter.ReleasesByAvailability(releases, availability)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Source.ExcludeEndOfSupport {
+		c.logger.Info("Filtering out releases past their end of support date")
+		releases, err = c.filter.ReleasesSupportedAt(releases, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Source.ExcludeControlled {
+		c.logger.Info("Filtering out controlled releases")
+		releases, err = c.filter.ReleasesNotControlled(releases)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	version := input.Source.ProductVersion
 	if version != "" {
 		c.logger.Info(fmt.Sprintf("Filtering all releases by product version: '%s'", version))
@@ -99,30 +187,89 @@ func (c *CheckCommand) Run(input concourse.CheckRequest) (concourse.CheckRespons
 		}
 	}
 
-	if input.Source.SortBy == concourse.SortBySemver {
+	constraint := input.Source.ProductVersionConstraint
+	if constraint != "" {
+		c.logger.Info(fmt.Sprintf("Filtering all releases by product version constraint: '%s'", constraint))
+		releases, err = c.filter.ReleasesByVersionConstraint(releases, constraint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	releasedAfter := input.Source.ReleasedAfter
+	if releasedAfter != "" {
+		c.logger.Info(fmt.Sprintf("Filtering all releases by released after: '%s'", releasedAfter))
+		releases, err = c.filter.ReleasesReleasedAfter(releases, releasedAfter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch input.Source.SortBy {
+	case concourse.SortBySemver:
 		c.logger.Info("Sorting all releases by semver")
-		releases, err = c.semverSorter.SortBySemver(releases)
+		releases, err = c.releaseSorter.SortBySemver(releases)
+		if err != nil {
+			return nil, err
+		}
+	case concourse.SortByReleaseDate:
+		c.logger.Info("Sorting all releases by release date")
+		releases, err = c.releaseSorter.SortByReleaseDate(releases)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	vs, err := releaseVersions(releases)
-	if err != nil {
-		// Untested because versions.CombineVersionAndFingerprint cannot be forced to return an error.
-		return concourse.CheckResponse{}, err
+	if parentProductSlug != "" {
+		return c.dependentVersion(productSlug, parentProductSlug, releases, input.Source.SortBy)
 	}
 
-	if len(vs) == 0 {
+	if len(releases) == 0 {
 		return concourse.CheckResponse{}, nil
 	}
 
 	c.logger.Info("Gathering new versions")
 
-	newVersions, err := versions.Since(vs, input.Version.ProductVersion)
+	deployedVersion := input.Source.DeployedVersion
+
+	// Only the releases which may be emitted are fingerprinted and have their
+	// upgrade paths fetched, as each requires requests to Pivotal Network.
+	var newReleases []pivnet.Release
+	if input.Version.ProductVersion == "" {
+		initialVersions := string(input.Source.InitialVersions)
+		if initialVersions != "" {
+			c.logger.Info(fmt.Sprintf("No previous version - using initial versions: '%s'", initialVersions))
+		}
+
+		initial, err := versions.Initial(plainVersions(releases), initialVersions)
+		if err != nil {
+			return nil, err
+		}
+
+		newReleases = releases[:len(initial)]
+		if deployedVersion != "" {
+			newReleases, err = c.releasesUpgradableFrom(productSlug, releases, deployedVersion, len(initial))
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		newReleases, err = c.releasesSince(productSlug, releases, input.Version.ProductVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		if deployedVersion != "" {
+			newReleases, err = c.releasesUpgradableFrom(productSlug, newReleases, deployedVersion, len(newReleases))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	newVersions, err := c.releaseVersions(productSlug, newReleases)
 	if err != nil {
-		// Untested because versions.Since cannot be forced to return an error.
-		return nil, err
+		return concourse.CheckResponse{}, err
 	}
 
 	reversedVersions, err := versions.Reverse(newVersions)
@@ -139,6 +286,23 @@ func (c *CheckCommand) Run(input concourse.CheckRequest) (concourse.CheckRespons
 	}
 
 	if len(out) == 0 {
+		newest := releases[:1]
+		if deployedVersion != "" {
+			newest, err = c.releasesUpgradableFrom(productSlug, releases, deployedVersion, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(newest) == 0 {
+				return concourse.CheckResponse{}, nil
+			}
+		}
+
+		vs, err := c.releaseVersions(productSlug, newest)
+		if err != nil {
+			return concourse.CheckResponse{}, err
+		}
+
 		out = append(out, concourse.Version{ProductVersion: vs[0]})
 	}
 
@@ -173,8 +337,8 @@ func (c *CheckCommand) removeExistingLogFiles() error {
 	return nil
 }
 
-func (c *CheckCommand) validateReleaseType(releaseType string) error {
-	c.logger.Info(fmt.Sprintf("Validating release type: '%s'", releaseType))
+func (c *CheckCommand) validateReleaseTypes(providedReleaseTypes []string) error {
+	c.logger.Info(fmt.Sprintf("Validating release types: %v", providedReleaseTypes))
 	releaseTypes, err := c.pivnetClient.ReleaseTypes()
 	if err != nil {
 		return err
@@ -185,18 +349,255 @@ func (c *CheckCommand) validateReleaseType(releaseType string) error {
 		releaseTypesAsStrings[i] = string(r)
 	}
 
-	if releaseType != "" && !containsString(releaseTypesAsStrings, releaseType) {
-		releaseTypesPrintable := fmt.Sprintf("['%s']", strings.Join(releaseTypesAsStrings, "', '"))
-		return fmt.Errorf(
-			"provided release type: '%s' must be one of: %s",
-			releaseType,
-			releaseTypesPrintable,
-		)
+	for _, releaseType := range providedReleaseTypes {
+		if !containsString(releaseTypesAsStrings, releaseType) {
+			releaseTypesPrintable := fmt.Sprintf("['%s']", strings.Join(releaseTypesAsStrings, "', '"))
+			return fmt.Errorf(
+				"provided release type: '%s' must be one of: %s",
+				releaseType,
+				releaseTypesPrintable,
+			)
+		}
 	}
 
 	return nil
 }
 
+func toReleaseTypes(releaseTypes []string) []pivnet.ReleaseType {
+	converted := make([]pivnet.ReleaseType, len(releaseTypes))
+	for i, r := range releaseTypes {
+		converted[i] = pivnet.ReleaseType(r)
+	}
+	return converted
+}
+
+// releasesUpgradableFrom returns, in order, up to limit of the releases which
+// declare an upgrade path from the deployed version.
+func (c *CheckCommand) releasesUpgradableFrom(
+	productSlug string,
+	releases []pivnet.Release,
+	deployedVersion string,
+	limit int,
+) ([]pivnet.Release, error) {
+	c.logger.Info(fmt.Sprintf("Filtering releases by upgrade path from: '%s'", deployedVersion))
+
+	var upgradable []pivnet.Release
+
+	for _, r := range releases {
+		if len(upgradable) >= limit {
+			break
+		}
+
+		upgradePaths, err := c.pivnetClient.ReleaseUpgradePaths(productSlug, r.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var supported bool
+		for _, u := range upgradePaths {
+			if u.Release.Version == deployedVersion {
+				supported = true
+				break
+			}
+		}
+
+		if !supported {
+			c.logger.Info(fmt.Sprintf(
+				"Ignoring release: '%s' as it has no upgrade path from: '%s'",
+				r.Version,
+				deployedVersion,
+			))
+			continue
+		}
+
+		upgradable = append(upgradable, r)
+	}
+
+	return upgradable, nil
+}
+
+// dependentVersion returns the newest release of the product which the newest
+// of the provided parent releases depends on. Dependencies are compared by
+// semantic version.
+func (c *CheckCommand) dependentVersion(
+	productSlug string,
+	parentProductSlug string,
+	parentReleases []pivnet.Release,
+	sortBy concourse.SortBy,
+) (concourse.CheckResponse, error) {
+	if len(parentReleases) == 0 {
+		return concourse.CheckResponse{}, nil
+	}
+
+	parent := c.newestRelease(parentReleases, sortBy)
+
+	c.logger.Info(fmt.Sprintf(
+		"Getting dependencies of parent release: '%s'",
+		parent.Version,
+	))
+
+	dependencies, err := c.pivnetClient.ReleaseDependencies(parentProductSlug, parent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var newest *pivnet.DependentRelease
+	var newestSemver semver.Version
+	for i, d := range dependencies {
+		if d.Release.Product.Slug != productSlug {
+			continue
+		}
+
+		v, err := c.semverConverter.ToValidSemver(d.Release.Version)
+		if err != nil {
+			c.logger.Info(fmt.Sprintf(
+				"Ignoring dependency: '%s' as it cannot be compared as semver",
+				d.Release.Version,
+			))
+			continue
+		}
+
+		if newest == nil || v.GT(newestSemver) {
+			newest = &dependencies[i].Release
+			newestSemver = v
+		}
+	}
+
+	if newest == nil {
+		c.logger.Info(fmt.Sprintf(
+			"Parent release: '%s' does not depend on product: '%s'",
+			parent.Version,
+			productSlug,
+		))
+		return concourse.CheckResponse{}, nil
+	}
+
+	c.logger.Info(fmt.Sprintf(
+		"Parent release: '%s' depends on release: '%s'",
+		parent.Version,
+		newest.Version,
+	))
+
+	release, err := c.pivnetClient.GetRelease(productSlug, newest.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	vs, err := c.releaseVersions(productSlug, []pivnet.Release{release})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Finishing check and returning ouput")
+
+	return concourse.CheckResponse{{ProductVersion: vs[0]}}, nil
+}
+
+// newestRelease returns the first of the releases if they have been sorted.
+// Otherwise the order from Pivotal Network is not relied upon and the newest
+// release is found by semantic version, falling back to the first release if
+// none can be compared.
+func (c *CheckCommand) newestRelease(releases []pivnet.Release, sortBy concourse.SortBy) pivnet.Release {
+	if sortBy == concourse.SortBySemver || sortBy == concourse.SortByReleaseDate {
+		return releases[0]
+	}
+
+	newest := releases[0]
+	var newestSemver *semver.Version
+	for _, r := range releases {
+		v, err := c.semverConverter.ToValidSemver(r.Version)
+		if err != nil {
+			continue
+		}
+
+		if newestSemver == nil || v.GT(*newestSemver) {
+			newest = r
+			newestSemver = &v
+		}
+	}
+
+	return newest
+}
+
+// releasesSince returns the releases newer than the previous version.
+// If the previous version no longer exists, either because its fingerprint
+// changed or because the release was deleted, the releases are instead
+// all releases which are newer by semver, whatever order they are in, so that
+// no intermediate versions are skipped.
+func (c *CheckCommand) releasesSince(productSlug string, releases []pivnet.Release, previous string) ([]pivnet.Release, error) {
+	previousVersion := versionWithoutFingerprint(previous)
+
+	for i, r := range releases {
+		if r.Version != previousVersion {
+			continue
+		}
+
+		vs, err := c.releaseVersions(productSlug, releases[i:i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		if vs[0] == previous {
+			return releases[:i], nil
+		}
+
+		c.logger.Info(fmt.Sprintf(
+			"Previous version: '%s' has changed fingerprint - returning versions from: '%s'",
+			previous,
+			vs[0],
+		))
+		return releases[:i+1], nil
+	}
+
+	c.logger.Info(fmt.Sprintf(
+		"Previous version: '%s' no longer exists - returning versions newer than it",
+		previous,
+	))
+
+	previousSemver, err := c.semverConverter.ToValidSemver(previousVersion)
+	if err != nil {
+		c.logger.Info(fmt.Sprintf(
+			"Cannot compare previous version: '%s' as semver - returning newest version only",
+			previous,
+		))
+		return releases[:1], nil
+	}
+
+	var newer []pivnet.Release
+	for _, r := range releases {
+		v, err := c.semverConverter.ToValidSemver(r.Version)
+		if err != nil {
+			c.logger.Info(fmt.Sprintf(
+				"Ignoring version: '%s' as it cannot be compared as semver",
+				r.Version,
+			))
+			continue
+		}
+
+		if v.GT(previousSemver) {
+			newer = append(newer, r)
+		}
+	}
+
+	return newer, nil
+}
+
+func plainVersions(releases []pivnet.Release) []string {
+	vs := make([]string, len(releases))
+	for i, r := range releases {
+		vs[i] = r.Version
+	}
+	return vs
+}
+
+func versionWithoutFingerprint(versionWithFingerprint string) string {
+	version, _, err := versions.SplitIntoVersionAndFingerprint(versionWithFingerprint)
+	if err != nil {
+		return versionWithFingerprint
+	}
+	return version
+}
+
 func containsString(strings []string, str string) bool {
 	for _, s := range strings {
 		if str == s {
@@ -206,13 +607,18 @@ func containsString(strings []string, str string) bool {
 	return false
 }
 
-func releaseVersions(releases []pivnet.Release) ([]string, error) {
+func (c *CheckCommand) releaseVersions(productSlug string, releases []pivnet.Release) ([]string, error) {
 	releaseVersions := make([]string, len(releases))
 
-	var err error
 	for i, r := range releases {
-		releaseVersions[i], err = versions.CombineVersionAndFingerprint(r.Version, r.UpdatedAt)
+		fingerprint, err := c.fingerprinter.Fingerprint(productSlug, r)
+		if err != nil {
+			return nil, err
+		}
+
+		releaseVersions[i], err = versions.CombineVersionAndFingerprint(r.Version, fingerprint)
 		if err != nil {
+			// Untested because versions.CombineVersionAndFingerprint cannot be forced to return an error.
 			return nil, err
 		}
 	}
@@ -7,7 +7,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/blang/semver"
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
@@ -27,6 +29,9 @@ var _ = Describe("Check", func() {
 		fakePivnetClient *checkfakes.FakePivnetClient
 		fakeSorter       *checkfakes.FakeSorter
 
+		fakeSemverConverter *checkfakes.FakeSemverConverter
+		fakeFingerprinter   *checkfakes.FakeFingerprinter
+
 		checkRequest concourse.CheckRequest
 		checkCommand *check.CheckCommand
 
@@ -40,7 +45,14 @@ var _ = Describe("Check", func() {
 		filteredReleases []pivnet.Release
 
 		releasesByReleaseTypeErr error
+		releasesByTypesErr       error
+		excludingTypesErr        error
+		availabilityErr          error
+		supportedAtErr           error
+		notControlledErr         error
 		releasesByVersionErr     error
+		releasesByConstraintErr  error
+		releasedAfterErr         error
 
 		tempDir     string
 		logFilePath string
@@ -50,12 +62,29 @@ var _ = Describe("Check", func() {
 		fakeFilter = &checkfakes.FakeFilter{}
 		fakePivnetClient = &checkfakes.FakePivnetClient{}
 		fakeSorter = &checkfakes.FakeSorter{}
+		fakeSemverConverter = &checkfakes.FakeSemverConverter{}
+		fakeFingerprinter = &checkfakes.FakeFingerprinter{}
+
+		fakeFingerprinter.FingerprintStub = func(productSlug string, release pivnet.Release) (string, error) {
+			return release.UpdatedAt, nil
+		}
+
+		fakeSemverConverter.ToValidSemverStub = func(input string) (semver.Version, error) {
+			return semver.ParseTolerant(input)
+		}
 
 		logger := log.New(GinkgoWriter, "", log.LstdFlags)
 		fakeLogger = logshim.NewLogShim(logger, logger, true)
 
 		releasesByReleaseTypeErr = nil
+		releasesByTypesErr = nil
+		excludingTypesErr = nil
+		availabilityErr = nil
+		supportedAtErr = nil
+		notControlledErr = nil
 		releasesByVersionErr = nil
+		releasesByConstraintErr = nil
+		releasedAfterErr = nil
 		releaseTypesErr = nil
 		releasesErr = nil
 
@@ -116,7 +145,14 @@ var _ = Describe("Check", func() {
 		fakePivnetClient.ReleasesForProductSlugReturns(allReleases, releasesErr)
 
 		fakeFilter.ReleasesByReleaseTypeReturns(filteredReleases, releasesByReleaseTypeErr)
+		fakeFilter.ReleasesByReleaseTypesReturns(filteredReleases, releasesByTypesErr)
+		fakeFilter.ReleasesExcludingReleaseTypesReturns(filteredReleases, excludingTypesErr)
+		fakeFilter.ReleasesByAvailabilityReturns(filteredReleases, availabilityErr)
+		fakeFilter.ReleasesSupportedAtReturns(filteredReleases, supportedAtErr)
+		fakeFilter.ReleasesNotControlledReturns(filteredReleases, notControlledErr)
 		fakeFilter.ReleasesByVersionReturns(filteredReleases, releasesByVersionErr)
+		fakeFilter.ReleasesByVersionConstraintReturns(filteredReleases, releasesByConstraintErr)
+		fakeFilter.ReleasesReleasedAfterReturns(filteredReleases, releasedAfterErr)
 
 		binaryVersion := "v0.1.2-unit-tests"
 
@@ -126,6 +162,8 @@ var _ = Describe("Check", func() {
 			fakeFilter,
 			fakePivnetClient,
 			fakeSorter,
+			fakeSemverConverter,
+			fakeFingerprinter,
 			logFilePath,
 		)
 	})
@@ -145,6 +183,242 @@ var _ = Describe("Check", func() {
 		Expect(response[0].ProductVersion).To(Equal(expectedVersionWithFingerprint))
 	})
 
+	It("only fingerprints the releases which are returned", func() {
+		_, err := checkCommand.Run(checkRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeFingerprinter.FingerprintCallCount()).To(Equal(1))
+	})
+
+	Context("when a previous version is provided", func() {
+		BeforeEach(func() {
+			checkRequest.Version = concourse.Version{
+				ProductVersion: versionsWithFingerprints[1],
+			}
+		})
+
+		It("does not fingerprint releases older than the previous version", func() {
+			_, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFingerprinter.FingerprintCallCount()).To(Equal(2))
+			for i := 0; i < fakeFingerprinter.FingerprintCallCount(); i++ {
+				_, release := fakeFingerprinter.FingerprintArgsForCall(i)
+				Expect(release).NotTo(Equal(allReleases[2]))
+			}
+		})
+	})
+
+	Context("when initial versions is all", func() {
+		BeforeEach(func() {
+			checkRequest.Source.InitialVersions = concourse.InitialVersionsAll
+		})
+
+		It("returns every version in ascending order", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(HaveLen(3))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[2]))
+			Expect(response[1].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+			Expect(response[2].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+		})
+
+		Context("when a previous version is provided", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: versionsWithFingerprints[1],
+				}
+			})
+
+			It("returns only the new versions", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(1))
+				Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+			})
+		})
+	})
+
+	Context("when initial versions is a number", func() {
+		BeforeEach(func() {
+			checkRequest.Source.InitialVersions = "2"
+		})
+
+		It("returns that many of the newest versions in ascending order", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(HaveLen(2))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+			Expect(response[1].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+		})
+	})
+
+	Context("when initial versions is invalid", func() {
+		BeforeEach(func() {
+			checkRequest.Source.InitialVersions = "some"
+		})
+
+		It("returns an error", func() {
+			_, err := checkCommand.Run(checkRequest)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the previous version is present", func() {
+		BeforeEach(func() {
+			checkRequest.Version = concourse.Version{
+				ProductVersion: versionsWithFingerprints[1],
+			}
+		})
+
+		It("returns the newer versions", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+		})
+	})
+
+	Context("when the previous version has a different fingerprint", func() {
+		BeforeEach(func() {
+			checkRequest.Version = concourse.Version{
+				ProductVersion: "2.3.4#old-time",
+			}
+		})
+
+		It("returns the updated version and newer versions", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(HaveLen(2))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+			Expect(response[1].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+		})
+	})
+
+	Context("when the previous version has been deleted", func() {
+		BeforeEach(func() {
+			allReleases = []pivnet.Release{
+				{ID: 4, Version: "2.0.0", UpdatedAt: "time4"},
+				{ID: 3, Version: "1.2.4", UpdatedAt: "time3"},
+				{ID: 2, Version: "1.2.2", UpdatedAt: "time2"},
+				{ID: 1, Version: "1.2.1", UpdatedAt: "time1"},
+			}
+			filteredReleases = allReleases
+
+			checkRequest.Version = concourse.Version{
+				ProductVersion: "1.2.3#time-deleted",
+			}
+		})
+
+		It("returns the versions newer than the previous version", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: "1.2.4#time3"},
+				{ProductVersion: "2.0.0#time4"},
+			}))
+		})
+
+		Context("when the releases are not in semver order", func() {
+			BeforeEach(func() {
+				allReleases = []pivnet.Release{
+					{ID: 3, Version: "1.2.4", UpdatedAt: "time3"},
+					{ID: 1, Version: "1.2.1", UpdatedAt: "time1"},
+					{ID: 4, Version: "2.0.0", UpdatedAt: "time4"},
+					{ID: 2, Version: "1.2.2", UpdatedAt: "time2"},
+				}
+				filteredReleases = allReleases
+			})
+
+			It("returns only the versions newer than the previous version", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(Equal(concourse.CheckResponse{
+					{ProductVersion: "2.0.0#time4"},
+					{ProductVersion: "1.2.4#time3"},
+				}))
+			})
+		})
+
+		Context("when there is no older version", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: "1.0.0#time-deleted",
+				}
+			})
+
+			It("returns all versions", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(4))
+				Expect(response[0].ProductVersion).To(Equal("1.2.1#time1"))
+			})
+		})
+
+		Context("when the previous version is not semver", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: "not-semver#time-deleted",
+				}
+			})
+
+			It("returns the newest version", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(Equal(concourse.CheckResponse{
+					{ProductVersion: "2.0.0#time4"},
+				}))
+			})
+		})
+	})
+
+	Context("when fingerprinting a release returns an error", func() {
+		var (
+			fingerprintErr error
+		)
+
+		BeforeEach(func() {
+			fingerprintErr = errors.New("fingerprint error")
+
+			fakeFingerprinter.FingerprintStub = nil
+			fakeFingerprinter.FingerprintReturns("", fingerprintErr)
+		})
+
+		It("returns the error", func() {
+			_, err := checkCommand.Run(checkRequest)
+			Expect(err).To(Equal(fingerprintErr))
+		})
+	})
+
+	Context("when the fingerprint is empty", func() {
+		BeforeEach(func() {
+			fakeFingerprinter.FingerprintStub = nil
+			fakeFingerprinter.FingerprintReturns("", nil)
+		})
+
+		It("returns the version without a fingerprint", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: allReleases[0].Version},
+			}))
+
+			productSlug, release := fakeFingerprinter.FingerprintArgsForCall(0)
+			Expect(productSlug).To(Equal(checkRequest.Source.ProductSlug))
+			Expect(release).To(Equal(allReleases[0]))
+		})
+	})
+
 	Context("when no releases are returned", func() {
 		BeforeEach(func() {
 			allReleases = []pivnet.Release{}
@@ -308,6 +582,431 @@ var _ = Describe("Check", func() {
 		})
 	})
 
+	Context("when multiple release types are specified", func() {
+		BeforeEach(func() {
+			checkRequest.Source.ReleaseTypes = []string{
+				string(releaseTypes[0]),
+				string(releaseTypes[2]),
+			}
+
+			filteredReleases = []pivnet.Release{allReleases[0], allReleases[2]}
+		})
+
+		It("returns the most recent version with any of those release types", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ReleasesByReleaseTypesCallCount()).To(Equal(1))
+			invokedReleases, invokedReleaseTypes := fakeFilter.ReleasesByReleaseTypesArgsForCall(0)
+			Expect(invokedReleases).To(Equal(allReleases))
+			Expect(invokedReleaseTypes).To(Equal([]pivnet.ReleaseType{releaseTypes[0], releaseTypes[2]}))
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+		})
+
+		Context("when one of the release types is invalid", func() {
+			BeforeEach(func() {
+				checkRequest.Source.ReleaseTypes = append(checkRequest.Source.ReleaseTypes, "not a valid release type")
+			})
+
+			It("returns an error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err.Error()).To(MatchRegexp(".*release type.*not a valid release type.*one of"))
+			})
+		})
+
+		Context("when filtering returns an error", func() {
+			BeforeEach(func() {
+				releasesByTypesErr = fmt.Errorf("some release types error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(releasesByTypesErr))
+			})
+		})
+	})
+
+	Context("when release types are excluded", func() {
+		BeforeEach(func() {
+			checkRequest.Source.ExcludeReleaseTypes = []string{string(releaseTypes[0])}
+
+			filteredReleases = []pivnet.Release{allReleases[1], allReleases[2]}
+		})
+
+		It("returns the most recent version without those release types", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ReleasesExcludingReleaseTypesCallCount()).To(Equal(1))
+			_, invokedReleaseTypes := fakeFilter.ReleasesExcludingReleaseTypesArgsForCall(0)
+			Expect(invokedReleaseTypes).To(Equal([]pivnet.ReleaseType{releaseTypes[0]}))
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+		})
+
+		Context("when an excluded release type is invalid", func() {
+			BeforeEach(func() {
+				checkRequest.Source.ExcludeReleaseTypes = []string{"not a valid release type"}
+			})
+
+			It("returns an error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err.Error()).To(MatchRegexp(".*release type.*not a valid release type.*one of"))
+			})
+		})
+
+		Context("when filtering returns an error", func() {
+			BeforeEach(func() {
+				excludingTypesErr = fmt.Errorf("some exclude error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(excludingTypesErr))
+			})
+		})
+	})
+
+	Context("when availability is specified", func() {
+		BeforeEach(func() {
+			checkRequest.Source.Availability = "All Users"
+
+			filteredReleases = []pivnet.Release{allReleases[1]}
+		})
+
+		It("returns the most recent version with that availability", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ReleasesByAvailabilityCallCount()).To(Equal(1))
+			invokedReleases, invokedAvailability := fakeFilter.ReleasesByAvailabilityArgsForCall(0)
+			Expect(invokedReleases).To(Equal(allReleases))
+			Expect(invokedAvailability).To(Equal("All Users"))
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+		})
+
+		Context("when filtering returns an error", func() {
+			BeforeEach(func() {
+				availabilityErr = fmt.Errorf("some availability error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(availabilityErr))
+			})
+		})
+	})
+
+	Context("when releases past end of support are excluded", func() {
+		BeforeEach(func() {
+			checkRequest.Source.ExcludeEndOfSupport = true
+
+			filteredReleases = []pivnet.Release{allReleases[2]}
+		})
+
+		It("returns the most recent supported version", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ReleasesSupportedAtCallCount()).To(Equal(1))
+			invokedReleases, invokedAt := fakeFilter.ReleasesSupportedAtArgsForCall(0)
+			Expect(invokedReleases).To(Equal(allReleases))
+			Expect(invokedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[2]))
+		})
+
+		Context("when filtering returns an error", func() {
+			BeforeEach(func() {
+				supportedAtErr = fmt.Errorf("some end of support error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(supportedAtErr))
+			})
+		})
+	})
+
+	Context("when controlled releases are excluded", func() {
+		BeforeEach(func() {
+			checkRequest.Source.ExcludeControlled = true
+
+			filteredReleases = []pivnet.Release{allReleases[2]}
+		})
+
+		It("returns the most recent uncontrolled version", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ReleasesNotControlledCallCount()).To(Equal(1))
+			Expect(fakeFilter.ReleasesNotControlledArgsForCall(0)).To(Equal(allReleases))
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[2]))
+		})
+
+		Context("when filtering returns an error", func() {
+			BeforeEach(func() {
+				notControlledErr = fmt.Errorf("some controlled error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(notControlledErr))
+			})
+		})
+	})
+
+	It("does not filter by availability, end of support or controlled by default", func() {
+		_, err := checkCommand.Run(checkRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeFilter.ReleasesByAvailabilityCallCount()).To(Equal(0))
+		Expect(fakeFilter.ReleasesSupportedAtCallCount()).To(Equal(0))
+		Expect(fakeFilter.ReleasesNotControlledCallCount()).To(Equal(0))
+	})
+
+	Context("when following a parent product", func() {
+		var (
+			parentReleases   []pivnet.Release
+			dependencies     []pivnet.ReleaseDependency
+			dependentRelease pivnet.Release
+
+			dependenciesErr error
+			getReleaseErr   error
+		)
+
+		BeforeEach(func() {
+			checkRequest.Source.ProductSlug = "stemcells"
+			checkRequest.Source.ParentProductSlug = "some-tile"
+
+			parentReleases = []pivnet.Release{
+				{ID: 20, Version: "1.9.0"},
+				{ID: 10, Version: "1.8.0"},
+			}
+			filteredReleases = parentReleases
+
+			dependencies = []pivnet.ReleaseDependency{
+				{Release: pivnet.DependentRelease{ID: 1, Version: "3263.7", Product: pivnet.Product{Slug: "stemcells"}}},
+				{Release: pivnet.DependentRelease{ID: 2, Version: "3263.10", Product: pivnet.Product{Slug: "stemcells"}}},
+				{Release: pivnet.DependentRelease{ID: 3, Version: "9.9.9", Product: pivnet.Product{Slug: "other-product"}}},
+				{Release: pivnet.DependentRelease{ID: 4, Version: "not-semver", Product: pivnet.Product{Slug: "stemcells"}}},
+			}
+
+			dependentRelease = pivnet.Release{ID: 2, Version: "3263.10", UpdatedAt: "some-time"}
+
+			dependenciesErr = nil
+			getReleaseErr = nil
+		})
+
+		JustBeforeEach(func() {
+			fakePivnetClient.ReleasesForProductSlugReturns(parentReleases, releasesErr)
+			fakePivnetClient.ReleaseDependenciesReturns(dependencies, dependenciesErr)
+			fakePivnetClient.GetReleaseReturns(dependentRelease, getReleaseErr)
+		})
+
+		It("returns the newest dependency of the newest parent release", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.ReleasesForProductSlugArgsForCall(0)).To(Equal("some-tile"))
+
+			invokedProductSlug, invokedReleaseID := fakePivnetClient.ReleaseDependenciesArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal("some-tile"))
+			Expect(invokedReleaseID).To(Equal(20))
+
+			invokedProductSlug, invokedVersion := fakePivnetClient.GetReleaseArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal("stemcells"))
+			Expect(invokedVersion).To(Equal("3263.10"))
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: "3263.10#some-time"},
+			}))
+		})
+
+		Context("when the parent releases are not newest first", func() {
+			BeforeEach(func() {
+				parentReleases = []pivnet.Release{
+					{ID: 10, Version: "1.8.0"},
+					{ID: 20, Version: "1.9.0"},
+				}
+			})
+
+			It("uses the newest parent release by semver", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, invokedReleaseID := fakePivnetClient.ReleaseDependenciesArgsForCall(0)
+				Expect(invokedReleaseID).To(Equal(20))
+			})
+
+			Context("when the releases are sorted", func() {
+				BeforeEach(func() {
+					checkRequest.Source.SortBy = concourse.SortByReleaseDate
+					fakeSorter.SortByReleaseDateReturns(parentReleases, nil)
+				})
+
+				It("uses the first parent release in the sorted order", func() {
+					_, err := checkCommand.Run(checkRequest)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, invokedReleaseID := fakePivnetClient.ReleaseDependenciesArgsForCall(0)
+					Expect(invokedReleaseID).To(Equal(10))
+				})
+			})
+		})
+
+		Context("when the parent release does not depend on the product", func() {
+			BeforeEach(func() {
+				dependencies = dependencies[2:3]
+			})
+
+			It("returns empty response without error", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(BeEmpty())
+				Expect(fakePivnetClient.GetReleaseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when there are no parent releases", func() {
+			BeforeEach(func() {
+				parentReleases = []pivnet.Release{}
+			})
+
+			It("returns empty response without error", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(BeEmpty())
+				Expect(fakePivnetClient.ReleaseDependenciesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when getting dependencies returns an error", func() {
+			BeforeEach(func() {
+				dependenciesErr = errors.New("dependencies error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(dependenciesErr))
+			})
+		})
+
+		Context("when getting the dependent release returns an error", func() {
+			BeforeEach(func() {
+				getReleaseErr = errors.New("get release error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(getReleaseErr))
+			})
+		})
+	})
+
+	Context("when the deployed version is specified", func() {
+		var (
+			upgradePathsErr error
+		)
+
+		BeforeEach(func() {
+			checkRequest.Source.DeployedVersion = "1.2.2"
+
+			upgradePathsErr = nil
+		})
+
+		JustBeforeEach(func() {
+			fakePivnetClient.ReleaseUpgradePathsStub = func(productSlug string, releaseID int) ([]pivnet.ReleaseUpgradePath, error) {
+				if upgradePathsErr != nil {
+					return nil, upgradePathsErr
+				}
+
+				switch releaseID {
+				case allReleases[1].ID:
+					return []pivnet.ReleaseUpgradePath{
+						{Release: pivnet.UpgradePathRelease{Version: "1.2.4"}},
+					}, nil
+				default:
+					return []pivnet.ReleaseUpgradePath{
+						{Release: pivnet.UpgradePathRelease{Version: "1.2.2"}},
+					}, nil
+				}
+			}
+		})
+
+		It("returns only versions with an upgrade path from the deployed version", func() {
+			checkRequest.Source.InitialVersions = concourse.InitialVersionsAll
+
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.ReleaseUpgradePathsCallCount()).To(Equal(3))
+			invokedProductSlug, _ := fakePivnetClient.ReleaseUpgradePathsArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal(productSlug))
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: versionsWithFingerprints[2]},
+				{ProductVersion: versionsWithFingerprints[0]},
+			}))
+		})
+
+		It("stops getting upgrade paths once the newest upgradable release is found", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.ReleaseUpgradePathsCallCount()).To(Equal(1))
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: versionsWithFingerprints[0]},
+			}))
+		})
+
+		Context("when a previous version is provided", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: versionsWithFingerprints[1],
+				}
+			})
+
+			It("only gets upgrade paths for releases newer than the previous version", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePivnetClient.ReleaseUpgradePathsCallCount()).To(Equal(1))
+				_, invokedReleaseID := fakePivnetClient.ReleaseUpgradePathsArgsForCall(0)
+				Expect(invokedReleaseID).To(Equal(allReleases[0].ID))
+
+				Expect(response).To(Equal(concourse.CheckResponse{
+					{ProductVersion: versionsWithFingerprints[0]},
+				}))
+			})
+		})
+
+		Context("when getting upgrade paths returns an error", func() {
+			BeforeEach(func() {
+				upgradePathsErr = errors.New("upgrade paths error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(upgradePathsErr))
+			})
+		})
+	})
+
 	Context("when the product version is specified", func() {
 		BeforeEach(func() {
 			checkRequest.Source.ReleaseType = string(releaseTypes[1])
@@ -343,6 +1042,127 @@ var _ = Describe("Check", func() {
 		})
 	})
 
+	Context("when the product version constraint is specified", func() {
+		BeforeEach(func() {
+			checkRequest.Source.ProductVersionConstraint = ">=1.2.4 <2.0.0"
+
+			filteredReleases = []pivnet.Release{allReleases[2]}
+		})
+
+		It("returns the newest release satisfying the constraint without error", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ReleasesByVersionConstraintCallCount()).To(Equal(1))
+			invokedReleases, invokedConstraint := fakeFilter.ReleasesByVersionConstraintArgsForCall(0)
+			Expect(invokedReleases).To(Equal(allReleases))
+			Expect(invokedConstraint).To(Equal(">=1.2.4 <2.0.0"))
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[2]))
+		})
+
+		Context("when filtering returns an error", func() {
+			BeforeEach(func() {
+				releasesByConstraintErr = fmt.Errorf("some constraint error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err).To(Equal(releasesByConstraintErr))
+			})
+		})
+	})
+
+	Context("when released after is specified", func() {
+		BeforeEach(func() {
+			checkRequest.Source.ReleasedAfter = "2016-10-04"
+
+			filteredReleases = []pivnet.Release{allReleases[1]}
+		})
+
+		It("returns releases released after the date without error", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ReleasesReleasedAfterCallCount()).To(Equal(1))
+			invokedReleases, invokedDate := fakeFilter.ReleasesReleasedAfterArgsForCall(0)
+			Expect(invokedReleases).To(Equal(allReleases))
+			Expect(invokedDate).To(Equal("2016-10-04"))
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+		})
+
+		Context("when filtering returns an error", func() {
+			BeforeEach(func() {
+				releasedAfterErr = fmt.Errorf("some released after error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err).To(Equal(releasedAfterErr))
+			})
+		})
+	})
+
+	Context("when sorting by release date", func() {
+		var (
+			dateOrderedReleases []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			checkRequest.Source.SortBy = concourse.SortByReleaseDate
+
+			dateOrderedReleases = []pivnet.Release{
+				allReleases[2],
+				allReleases[0],
+				allReleases[1],
+			}
+
+			checkRequest.Version = concourse.Version{
+				ProductVersion: versionsWithFingerprints[1],
+			}
+
+			fakeSorter.SortByReleaseDateReturns(dateOrderedReleases, nil)
+		})
+
+		It("returns in ascending release date order", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(HaveLen(2))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+			Expect(response[1].ProductVersion).To(Equal(versionsWithFingerprints[2]))
+
+			Expect(fakeSorter.SortByReleaseDateCallCount()).To(Equal(1))
+			Expect(fakeSorter.SortBySemverCallCount()).To(Equal(0))
+		})
+
+		Context("when sorting by release date returns an error", func() {
+			var (
+				sortErr error
+			)
+
+			BeforeEach(func() {
+				sortErr = errors.New("release date error")
+
+				fakeSorter.SortByReleaseDateReturns(nil, sortErr)
+			})
+
+			It("returns error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err).To(Equal(sortErr))
+			})
+		})
+	})
+
 	Context("when sorting by semver", func() {
 		var (
 			semverOrderedReleases []pivnet.Release
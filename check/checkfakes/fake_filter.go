@@ -3,6 +3,7 @@ package checkfakes
 
 import (
 	"sync"
+	"time"
 
 	go_pivnet "github.com/pivotal-cf/go-pivnet"
 )
@@ -18,6 +19,26 @@ type FakeFilter struct {
 		result1 []go_pivnet.Release
 		result2 error
 	}
+	ReleasesByReleaseTypesStub        func(releases []go_pivnet.Release, releaseTypes []go_pivnet.ReleaseType) ([]go_pivnet.Release, error)
+	releasesByReleaseTypesMutex       sync.RWMutex
+	releasesByReleaseTypesArgsForCall []struct {
+		releases     []go_pivnet.Release
+		releaseTypes []go_pivnet.ReleaseType
+	}
+	releasesByReleaseTypesReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	ReleasesExcludingReleaseTypesStub        func(releases []go_pivnet.Release, releaseTypes []go_pivnet.ReleaseType) ([]go_pivnet.Release, error)
+	releasesExcludingReleaseTypesMutex       sync.RWMutex
+	releasesExcludingReleaseTypesArgsForCall []struct {
+		releases     []go_pivnet.Release
+		releaseTypes []go_pivnet.ReleaseType
+	}
+	releasesExcludingReleaseTypesReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
 	ReleasesByVersionStub        func(releases []go_pivnet.Release, version string) ([]go_pivnet.Release, error)
 	releasesByVersionMutex       sync.RWMutex
 	releasesByVersionArgsForCall []struct {
@@ -28,6 +49,55 @@ type FakeFilter struct {
 		result1 []go_pivnet.Release
 		result2 error
 	}
+	ReleasesByVersionConstraintStub        func(releases []go_pivnet.Release, constraint string) ([]go_pivnet.Release, error)
+	releasesByVersionConstraintMutex       sync.RWMutex
+	releasesByVersionConstraintArgsForCall []struct {
+		releases   []go_pivnet.Release
+		constraint string
+	}
+	releasesByVersionConstraintReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	ReleasesReleasedAfterStub        func(releases []go_pivnet.Release, date string) ([]go_pivnet.Release, error)
+	releasesReleasedAfterMutex       sync.RWMutex
+	releasesReleasedAfterArgsForCall []struct {
+		releases []go_pivnet.Release
+		date     string
+	}
+	releasesReleasedAfterReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	ReleasesByAvailabilityStub        func(releases []go_pivnet.Release, availability string) ([]go_pivnet.Release, error)
+	releasesByAvailabilityMutex       sync.RWMutex
+	releasesByAvailabilityArgsForCall []struct {
+		releases     []go_pivnet.Release
+		availability string
+	}
+	releasesByAvailabilityReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	ReleasesSupportedAtStub        func(releases []go_pivnet.Release, at time.Time) ([]go_pivnet.Release, error)
+	releasesSupportedAtMutex       sync.RWMutex
+	releasesSupportedAtArgsForCall []struct {
+		releases []go_pivnet.Release
+		at       time.Time
+	}
+	releasesSupportedAtReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	ReleasesNotControlledStub        func(releases []go_pivnet.Release) ([]go_pivnet.Release, error)
+	releasesNotControlledMutex       sync.RWMutex
+	releasesNotControlledArgsForCall []struct {
+		releases []go_pivnet.Release
+	}
+	releasesNotControlledReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -72,6 +142,96 @@ func (fake *FakeFilter) ReleasesByReleaseTypeReturns(result1 []go_pivnet.Release
 	}{result1, result2}
 }
 
+func (fake *FakeFilter) ReleasesByReleaseTypes(releases []go_pivnet.Release, releaseTypes []go_pivnet.ReleaseType) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	var releaseTypesCopy []go_pivnet.ReleaseType
+	if releaseTypes != nil {
+		releaseTypesCopy = make([]go_pivnet.ReleaseType, len(releaseTypes))
+		copy(releaseTypesCopy, releaseTypes)
+	}
+	fake.releasesByReleaseTypesMutex.Lock()
+	fake.releasesByReleaseTypesArgsForCall = append(fake.releasesByReleaseTypesArgsForCall, struct {
+		releases     []go_pivnet.Release
+		releaseTypes []go_pivnet.ReleaseType
+	}{releasesCopy, releaseTypesCopy})
+	fake.recordInvocation("ReleasesByReleaseTypes", []interface{}{releasesCopy, releaseTypesCopy})
+	fake.releasesByReleaseTypesMutex.Unlock()
+	if fake.ReleasesByReleaseTypesStub != nil {
+		return fake.ReleasesByReleaseTypesStub(releases, releaseTypes)
+	} else {
+		return fake.releasesByReleaseTypesReturns.result1, fake.releasesByReleaseTypesReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesByReleaseTypesCallCount() int {
+	fake.releasesByReleaseTypesMutex.RLock()
+	defer fake.releasesByReleaseTypesMutex.RUnlock()
+	return len(fake.releasesByReleaseTypesArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesByReleaseTypesArgsForCall(i int) ([]go_pivnet.Release, []go_pivnet.ReleaseType) {
+	fake.releasesByReleaseTypesMutex.RLock()
+	defer fake.releasesByReleaseTypesMutex.RUnlock()
+	return fake.releasesByReleaseTypesArgsForCall[i].releases, fake.releasesByReleaseTypesArgsForCall[i].releaseTypes
+}
+
+func (fake *FakeFilter) ReleasesByReleaseTypesReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesByReleaseTypesStub = nil
+	fake.releasesByReleaseTypesReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilter) ReleasesExcludingReleaseTypes(releases []go_pivnet.Release, releaseTypes []go_pivnet.ReleaseType) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	var releaseTypesCopy []go_pivnet.ReleaseType
+	if releaseTypes != nil {
+		releaseTypesCopy = make([]go_pivnet.ReleaseType, len(releaseTypes))
+		copy(releaseTypesCopy, releaseTypes)
+	}
+	fake.releasesExcludingReleaseTypesMutex.Lock()
+	fake.releasesExcludingReleaseTypesArgsForCall = append(fake.releasesExcludingReleaseTypesArgsForCall, struct {
+		releases     []go_pivnet.Release
+		releaseTypes []go_pivnet.ReleaseType
+	}{releasesCopy, releaseTypesCopy})
+	fake.recordInvocation("ReleasesExcludingReleaseTypes", []interface{}{releasesCopy, releaseTypesCopy})
+	fake.releasesExcludingReleaseTypesMutex.Unlock()
+	if fake.ReleasesExcludingReleaseTypesStub != nil {
+		return fake.ReleasesExcludingReleaseTypesStub(releases, releaseTypes)
+	} else {
+		return fake.releasesExcludingReleaseTypesReturns.result1, fake.releasesExcludingReleaseTypesReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesExcludingReleaseTypesCallCount() int {
+	fake.releasesExcludingReleaseTypesMutex.RLock()
+	defer fake.releasesExcludingReleaseTypesMutex.RUnlock()
+	return len(fake.releasesExcludingReleaseTypesArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesExcludingReleaseTypesArgsForCall(i int) ([]go_pivnet.Release, []go_pivnet.ReleaseType) {
+	fake.releasesExcludingReleaseTypesMutex.RLock()
+	defer fake.releasesExcludingReleaseTypesMutex.RUnlock()
+	return fake.releasesExcludingReleaseTypesArgsForCall[i].releases, fake.releasesExcludingReleaseTypesArgsForCall[i].releaseTypes
+}
+
+func (fake *FakeFilter) ReleasesExcludingReleaseTypesReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesExcludingReleaseTypesStub = nil
+	fake.releasesExcludingReleaseTypesReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilter) ReleasesByVersion(releases []go_pivnet.Release, version string) ([]go_pivnet.Release, error) {
 	var releasesCopy []go_pivnet.Release
 	if releases != nil {
@@ -112,13 +272,226 @@ func (fake *FakeFilter) ReleasesByVersionReturns(result1 []go_pivnet.Release, re
 	}{result1, result2}
 }
 
+func (fake *FakeFilter) ReleasesByVersionConstraint(releases []go_pivnet.Release, constraint string) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	fake.releasesByVersionConstraintMutex.Lock()
+	fake.releasesByVersionConstraintArgsForCall = append(fake.releasesByVersionConstraintArgsForCall, struct {
+		releases   []go_pivnet.Release
+		constraint string
+	}{releasesCopy, constraint})
+	fake.recordInvocation("ReleasesByVersionConstraint", []interface{}{releasesCopy, constraint})
+	fake.releasesByVersionConstraintMutex.Unlock()
+	if fake.ReleasesByVersionConstraintStub != nil {
+		return fake.ReleasesByVersionConstraintStub(releases, constraint)
+	} else {
+		return fake.releasesByVersionConstraintReturns.result1, fake.releasesByVersionConstraintReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesByVersionConstraintCallCount() int {
+	fake.releasesByVersionConstraintMutex.RLock()
+	defer fake.releasesByVersionConstraintMutex.RUnlock()
+	return len(fake.releasesByVersionConstraintArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesByVersionConstraintArgsForCall(i int) ([]go_pivnet.Release, string) {
+	fake.releasesByVersionConstraintMutex.RLock()
+	defer fake.releasesByVersionConstraintMutex.RUnlock()
+	return fake.releasesByVersionConstraintArgsForCall[i].releases, fake.releasesByVersionConstraintArgsForCall[i].constraint
+}
+
+func (fake *FakeFilter) ReleasesByVersionConstraintReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesByVersionConstraintStub = nil
+	fake.releasesByVersionConstraintReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilter) ReleasesReleasedAfter(releases []go_pivnet.Release, date string) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	fake.releasesReleasedAfterMutex.Lock()
+	fake.releasesReleasedAfterArgsForCall = append(fake.releasesReleasedAfterArgsForCall, struct {
+		releases []go_pivnet.Release
+		date     string
+	}{releasesCopy, date})
+	fake.recordInvocation("ReleasesReleasedAfter", []interface{}{releasesCopy, date})
+	fake.releasesReleasedAfterMutex.Unlock()
+	if fake.ReleasesReleasedAfterStub != nil {
+		return fake.ReleasesReleasedAfterStub(releases, date)
+	} else {
+		return fake.releasesReleasedAfterReturns.result1, fake.releasesReleasedAfterReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesReleasedAfterCallCount() int {
+	fake.releasesReleasedAfterMutex.RLock()
+	defer fake.releasesReleasedAfterMutex.RUnlock()
+	return len(fake.releasesReleasedAfterArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesReleasedAfterArgsForCall(i int) ([]go_pivnet.Release, string) {
+	fake.releasesReleasedAfterMutex.RLock()
+	defer fake.releasesReleasedAfterMutex.RUnlock()
+	return fake.releasesReleasedAfterArgsForCall[i].releases, fake.releasesReleasedAfterArgsForCall[i].date
+}
+
+func (fake *FakeFilter) ReleasesReleasedAfterReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesReleasedAfterStub = nil
+	fake.releasesReleasedAfterReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilter) ReleasesByAvailability(releases []go_pivnet.Release, availability string) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	fake.releasesByAvailabilityMutex.Lock()
+	fake.releasesByAvailabilityArgsForCall = append(fake.releasesByAvailabilityArgsForCall, struct {
+		releases     []go_pivnet.Release
+		availability string
+	}{releasesCopy, availability})
+	fake.recordInvocation("ReleasesByAvailability", []interface{}{releasesCopy, availability})
+	fake.releasesByAvailabilityMutex.Unlock()
+	if fake.ReleasesByAvailabilityStub != nil {
+		return fake.ReleasesByAvailabilityStub(releases, availability)
+	} else {
+		return fake.releasesByAvailabilityReturns.result1, fake.releasesByAvailabilityReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesByAvailabilityCallCount() int {
+	fake.releasesByAvailabilityMutex.RLock()
+	defer fake.releasesByAvailabilityMutex.RUnlock()
+	return len(fake.releasesByAvailabilityArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesByAvailabilityArgsForCall(i int) ([]go_pivnet.Release, string) {
+	fake.releasesByAvailabilityMutex.RLock()
+	defer fake.releasesByAvailabilityMutex.RUnlock()
+	return fake.releasesByAvailabilityArgsForCall[i].releases, fake.releasesByAvailabilityArgsForCall[i].availability
+}
+
+func (fake *FakeFilter) ReleasesByAvailabilityReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesByAvailabilityStub = nil
+	fake.releasesByAvailabilityReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilter) ReleasesSupportedAt(releases []go_pivnet.Release, at time.Time) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	fake.releasesSupportedAtMutex.Lock()
+	fake.releasesSupportedAtArgsForCall = append(fake.releasesSupportedAtArgsForCall, struct {
+		releases []go_pivnet.Release
+		at       time.Time
+	}{releasesCopy, at})
+	fake.recordInvocation("ReleasesSupportedAt", []interface{}{releasesCopy, at})
+	fake.releasesSupportedAtMutex.Unlock()
+	if fake.ReleasesSupportedAtStub != nil {
+		return fake.ReleasesSupportedAtStub(releases, at)
+	} else {
+		return fake.releasesSupportedAtReturns.result1, fake.releasesSupportedAtReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesSupportedAtCallCount() int {
+	fake.releasesSupportedAtMutex.RLock()
+	defer fake.releasesSupportedAtMutex.RUnlock()
+	return len(fake.releasesSupportedAtArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesSupportedAtArgsForCall(i int) ([]go_pivnet.Release, time.Time) {
+	fake.releasesSupportedAtMutex.RLock()
+	defer fake.releasesSupportedAtMutex.RUnlock()
+	return fake.releasesSupportedAtArgsForCall[i].releases, fake.releasesSupportedAtArgsForCall[i].at
+}
+
+func (fake *FakeFilter) ReleasesSupportedAtReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesSupportedAtStub = nil
+	fake.releasesSupportedAtReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilter) ReleasesNotControlled(releases []go_pivnet.Release) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	fake.releasesNotControlledMutex.Lock()
+	fake.releasesNotControlledArgsForCall = append(fake.releasesNotControlledArgsForCall, struct {
+		releases []go_pivnet.Release
+	}{releasesCopy})
+	fake.recordInvocation("ReleasesNotControlled", []interface{}{releasesCopy})
+	fake.releasesNotControlledMutex.Unlock()
+	if fake.ReleasesNotControlledStub != nil {
+		return fake.ReleasesNotControlledStub(releases)
+	} else {
+		return fake.releasesNotControlledReturns.result1, fake.releasesNotControlledReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesNotControlledCallCount() int {
+	fake.releasesNotControlledMutex.RLock()
+	defer fake.releasesNotControlledMutex.RUnlock()
+	return len(fake.releasesNotControlledArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesNotControlledArgsForCall(i int) []go_pivnet.Release {
+	fake.releasesNotControlledMutex.RLock()
+	defer fake.releasesNotControlledMutex.RUnlock()
+	return fake.releasesNotControlledArgsForCall[i].releases
+}
+
+func (fake *FakeFilter) ReleasesNotControlledReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesNotControlledStub = nil
+	fake.releasesNotControlledReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.releasesByReleaseTypeMutex.RLock()
 	defer fake.releasesByReleaseTypeMutex.RUnlock()
+	fake.releasesByReleaseTypesMutex.RLock()
+	defer fake.releasesByReleaseTypesMutex.RUnlock()
+	fake.releasesExcludingReleaseTypesMutex.RLock()
+	defer fake.releasesExcludingReleaseTypesMutex.RUnlock()
 	fake.releasesByVersionMutex.RLock()
 	defer fake.releasesByVersionMutex.RUnlock()
+	fake.releasesByVersionConstraintMutex.RLock()
+	defer fake.releasesByVersionConstraintMutex.RUnlock()
+	fake.releasesReleasedAfterMutex.RLock()
+	defer fake.releasesReleasedAfterMutex.RUnlock()
+	fake.releasesByAvailabilityMutex.RLock()
+	defer fake.releasesByAvailabilityMutex.RUnlock()
+	fake.releasesSupportedAtMutex.RLock()
+	defer fake.releasesSupportedAtMutex.RUnlock()
+	fake.releasesNotControlledMutex.RLock()
+	defer fake.releasesNotControlledMutex.RUnlock()
 	return fake.invocations
 }
 
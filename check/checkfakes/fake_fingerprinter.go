@@ -0,0 +1,78 @@
+// This file was generated by counterfeiter
+package checkfakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+type FakeFingerprinter struct {
+	FingerprintStub        func(productSlug string, release go_pivnet.Release) (string, error)
+	fingerprintMutex       sync.RWMutex
+	fingerprintArgsForCall []struct {
+		productSlug string
+		release     go_pivnet.Release
+	}
+	fingerprintReturns struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeFingerprinter) Fingerprint(productSlug string, release go_pivnet.Release) (string, error) {
+	fake.fingerprintMutex.Lock()
+	fake.fingerprintArgsForCall = append(fake.fingerprintArgsForCall, struct {
+		productSlug string
+		release     go_pivnet.Release
+	}{productSlug, release})
+	fake.recordInvocation("Fingerprint", []interface{}{productSlug, release})
+	fake.fingerprintMutex.Unlock()
+	if fake.FingerprintStub != nil {
+		return fake.FingerprintStub(productSlug, release)
+	} else {
+		return fake.fingerprintReturns.result1, fake.fingerprintReturns.result2
+	}
+}
+
+func (fake *FakeFingerprinter) FingerprintCallCount() int {
+	fake.fingerprintMutex.RLock()
+	defer fake.fingerprintMutex.RUnlock()
+	return len(fake.fingerprintArgsForCall)
+}
+
+func (fake *FakeFingerprinter) FingerprintArgsForCall(i int) (string, go_pivnet.Release) {
+	fake.fingerprintMutex.RLock()
+	defer fake.fingerprintMutex.RUnlock()
+	return fake.fingerprintArgsForCall[i].productSlug, fake.fingerprintArgsForCall[i].release
+}
+
+func (fake *FakeFingerprinter) FingerprintReturns(result1 string, result2 error) {
+	fake.FingerprintStub = nil
+	fake.fingerprintReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFingerprinter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.fingerprintMutex.RLock()
+	defer fake.fingerprintMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeFingerprinter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -24,6 +24,36 @@ type FakePivnetClient struct {
 		result1 []go_pivnet.Release
 		result2 error
 	}
+	GetReleaseStub        func(productSlug string, version string) (go_pivnet.Release, error)
+	getReleaseMutex       sync.RWMutex
+	getReleaseArgsForCall []struct {
+		productSlug string
+		version     string
+	}
+	getReleaseReturns struct {
+		result1 go_pivnet.Release
+		result2 error
+	}
+	ReleaseDependenciesStub        func(productSlug string, releaseID int) ([]go_pivnet.ReleaseDependency, error)
+	releaseDependenciesMutex       sync.RWMutex
+	releaseDependenciesArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	releaseDependenciesReturns struct {
+		result1 []go_pivnet.ReleaseDependency
+		result2 error
+	}
+	ReleaseUpgradePathsStub        func(productSlug string, releaseID int) ([]go_pivnet.ReleaseUpgradePath, error)
+	releaseUpgradePathsMutex       sync.RWMutex
+	releaseUpgradePathsArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	releaseUpgradePathsReturns struct {
+		result1 []go_pivnet.ReleaseUpgradePath
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -88,6 +118,111 @@ func (fake *FakePivnetClient) ReleasesForProductSlugReturns(result1 []go_pivnet.
 	}{result1, result2}
 }
 
+func (fake *FakePivnetClient) GetRelease(productSlug string, version string) (go_pivnet.Release, error) {
+	fake.getReleaseMutex.Lock()
+	fake.getReleaseArgsForCall = append(fake.getReleaseArgsForCall, struct {
+		productSlug string
+		version     string
+	}{productSlug, version})
+	fake.recordInvocation("GetRelease", []interface{}{productSlug, version})
+	fake.getReleaseMutex.Unlock()
+	if fake.GetReleaseStub != nil {
+		return fake.GetReleaseStub(productSlug, version)
+	} else {
+		return fake.getReleaseReturns.result1, fake.getReleaseReturns.result2
+	}
+}
+
+func (fake *FakePivnetClient) GetReleaseCallCount() int {
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	return len(fake.getReleaseArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetReleaseArgsForCall(i int) (string, string) {
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	return fake.getReleaseArgsForCall[i].productSlug, fake.getReleaseArgsForCall[i].version
+}
+
+func (fake *FakePivnetClient) GetReleaseReturns(result1 go_pivnet.Release, result2 error) {
+	fake.GetReleaseStub = nil
+	fake.getReleaseReturns = struct {
+		result1 go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) ReleaseDependencies(productSlug string, releaseID int) ([]go_pivnet.ReleaseDependency, error) {
+	fake.releaseDependenciesMutex.Lock()
+	fake.releaseDependenciesArgsForCall = append(fake.releaseDependenciesArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ReleaseDependencies", []interface{}{productSlug, releaseID})
+	fake.releaseDependenciesMutex.Unlock()
+	if fake.ReleaseDependenciesStub != nil {
+		return fake.ReleaseDependenciesStub(productSlug, releaseID)
+	} else {
+		return fake.releaseDependenciesReturns.result1, fake.releaseDependenciesReturns.result2
+	}
+}
+
+func (fake *FakePivnetClient) ReleaseDependenciesCallCount() int {
+	fake.releaseDependenciesMutex.RLock()
+	defer fake.releaseDependenciesMutex.RUnlock()
+	return len(fake.releaseDependenciesArgsForCall)
+}
+
+func (fake *FakePivnetClient) ReleaseDependenciesArgsForCall(i int) (string, int) {
+	fake.releaseDependenciesMutex.RLock()
+	defer fake.releaseDependenciesMutex.RUnlock()
+	return fake.releaseDependenciesArgsForCall[i].productSlug, fake.releaseDependenciesArgsForCall[i].releaseID
+}
+
+func (fake *FakePivnetClient) ReleaseDependenciesReturns(result1 []go_pivnet.ReleaseDependency, result2 error) {
+	fake.ReleaseDependenciesStub = nil
+	fake.releaseDependenciesReturns = struct {
+		result1 []go_pivnet.ReleaseDependency
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) ReleaseUpgradePaths(productSlug string, releaseID int) ([]go_pivnet.ReleaseUpgradePath, error) {
+	fake.releaseUpgradePathsMutex.Lock()
+	fake.releaseUpgradePathsArgsForCall = append(fake.releaseUpgradePathsArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ReleaseUpgradePaths", []interface{}{productSlug, releaseID})
+	fake.releaseUpgradePathsMutex.Unlock()
+	if fake.ReleaseUpgradePathsStub != nil {
+		return fake.ReleaseUpgradePathsStub(productSlug, releaseID)
+	} else {
+		return fake.releaseUpgradePathsReturns.result1, fake.releaseUpgradePathsReturns.result2
+	}
+}
+
+func (fake *FakePivnetClient) ReleaseUpgradePathsCallCount() int {
+	fake.releaseUpgradePathsMutex.RLock()
+	defer fake.releaseUpgradePathsMutex.RUnlock()
+	return len(fake.releaseUpgradePathsArgsForCall)
+}
+
+func (fake *FakePivnetClient) ReleaseUpgradePathsArgsForCall(i int) (string, int) {
+	fake.releaseUpgradePathsMutex.RLock()
+	defer fake.releaseUpgradePathsMutex.RUnlock()
+	return fake.releaseUpgradePathsArgsForCall[i].productSlug, fake.releaseUpgradePathsArgsForCall[i].releaseID
+}
+
+func (fake *FakePivnetClient) ReleaseUpgradePathsReturns(result1 []go_pivnet.ReleaseUpgradePath, result2 error) {
+	fake.ReleaseUpgradePathsStub = nil
+	fake.releaseUpgradePathsReturns = struct {
+		result1 []go_pivnet.ReleaseUpgradePath
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -95,6 +230,12 @@ func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	defer fake.releaseTypesMutex.RUnlock()
 	fake.releasesForProductSlugMutex.RLock()
 	defer fake.releasesForProductSlugMutex.RUnlock()
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	fake.releaseDependenciesMutex.RLock()
+	defer fake.releaseDependenciesMutex.RUnlock()
+	fake.releaseUpgradePathsMutex.RLock()
+	defer fake.releaseUpgradePathsMutex.RUnlock()
 	return fake.invocations
 }
 
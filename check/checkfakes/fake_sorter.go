@@ -17,6 +17,15 @@ type FakeSorter struct {
 		result1 []go_pivnet.Release
 		result2 error
 	}
+	SortByReleaseDateStub        func([]go_pivnet.Release) ([]go_pivnet.Release, error)
+	sortByReleaseDateMutex       sync.RWMutex
+	sortByReleaseDateArgsForCall []struct {
+		arg1 []go_pivnet.Release
+	}
+	sortByReleaseDateReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -60,11 +69,52 @@ func (fake *FakeSorter) SortBySemverReturns(result1 []go_pivnet.Release, result2
 	}{result1, result2}
 }
 
+func (fake *FakeSorter) SortByReleaseDate(arg1 []go_pivnet.Release) ([]go_pivnet.Release, error) {
+	var arg1Copy []go_pivnet.Release
+	if arg1 != nil {
+		arg1Copy = make([]go_pivnet.Release, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.sortByReleaseDateMutex.Lock()
+	fake.sortByReleaseDateArgsForCall = append(fake.sortByReleaseDateArgsForCall, struct {
+		arg1 []go_pivnet.Release
+	}{arg1Copy})
+	fake.recordInvocation("SortByReleaseDate", []interface{}{arg1Copy})
+	fake.sortByReleaseDateMutex.Unlock()
+	if fake.SortByReleaseDateStub != nil {
+		return fake.SortByReleaseDateStub(arg1)
+	} else {
+		return fake.sortByReleaseDateReturns.result1, fake.sortByReleaseDateReturns.result2
+	}
+}
+
+func (fake *FakeSorter) SortByReleaseDateCallCount() int {
+	fake.sortByReleaseDateMutex.RLock()
+	defer fake.sortByReleaseDateMutex.RUnlock()
+	return len(fake.sortByReleaseDateArgsForCall)
+}
+
+func (fake *FakeSorter) SortByReleaseDateArgsForCall(i int) []go_pivnet.Release {
+	fake.sortByReleaseDateMutex.RLock()
+	defer fake.sortByReleaseDateMutex.RUnlock()
+	return fake.sortByReleaseDateArgsForCall[i].arg1
+}
+
+func (fake *FakeSorter) SortByReleaseDateReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.SortByReleaseDateStub = nil
+	fake.sortByReleaseDateReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSorter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.sortBySemverMutex.RLock()
 	defer fake.sortBySemverMutex.RUnlock()
+	fake.sortByReleaseDateMutex.RLock()
+	defer fake.sortByReleaseDateMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,112 @@
+package checksum
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmMD5    Algorithm = "md5"
+	AlgorithmSHA256 Algorithm = "sha256"
+	AlgorithmBoth   Algorithm = "both"
+)
+
+// Checksums holds the hex-encoded digests of a file. A digest is empty if
+// its algorithm was not selected.
+type Checksums struct {
+	MD5    string
+	SHA256 string
+}
+
+// Hasher computes the digests selected by an Algorithm over everything
+// written to it.
+type Hasher struct {
+	md5    hash.Hash
+	sha256 hash.Hash
+	writer io.Writer
+}
+
+// NewHasher returns a Hasher for the provided algorithm. An empty algorithm
+// is treated as MD5.
+func NewHasher(algorithm Algorithm) *Hasher {
+	h := &Hasher{}
+
+	var writers []io.Writer
+
+	if algorithm != AlgorithmSHA256 {
+		h.md5 = md5.New()
+		writers = append(writers, h.md5)
+	}
+
+	if algorithm == AlgorithmSHA256 || algorithm == AlgorithmBoth {
+		h.sha256 = sha256.New()
+		writers = append(writers, h.sha256)
+	}
+
+	h.writer = io.MultiWriter(writers...)
+
+	return h
+}
+
+func (h *Hasher) Write(p []byte) (int, error) {
+	return h.writer.Write(p)
+}
+
+// Reset discards everything written to the Hasher so far.
+func (h *Hasher) Reset() {
+	if h.md5 != nil {
+		h.md5.Reset()
+	}
+
+	if h.sha256 != nil {
+		h.sha256.Reset()
+	}
+}
+
+func (h *Hasher) Sums() Checksums {
+	var sums Checksums
+
+	if h.md5 != nil {
+		sums.MD5 = fmt.Sprintf("%x", h.md5.Sum(nil))
+	}
+
+	if h.sha256 != nil {
+		sums.SHA256 = fmt.Sprintf("%x", h.sha256.Sum(nil))
+	}
+
+	return sums
+}
+
+type FileSummer struct {
+	algorithm Algorithm
+}
+
+func NewFileSummer(algorithm Algorithm) *FileSummer {
+	return &FileSummer{
+		algorithm: algorithm,
+	}
+}
+
+// SumFile reads the file once, computing every digest selected by the
+// summer's algorithm.
+func (f FileSummer) SumFile(filepath string) (Checksums, error) {
+	fileToSum, err := os.Open(filepath)
+	if err != nil {
+		return Checksums{}, err
+	}
+	defer fileToSum.Close()
+
+	hasher := NewHasher(f.algorithm)
+	_, err = io.Copy(hasher, fileToSum)
+	if err != nil {
+		return Checksums{}, err
+	}
+
+	return hasher.Sums(), nil
+}
@@ -0,0 +1,162 @@
+package checksum_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
+)
+
+var _ = Describe("Checksum", func() {
+	const (
+		// Expected digests of 'foobar contents'
+		expectedMD5    = "fdd3d599138fd15d7673f3d3539531c1"
+		expectedSHA256 = "070a103eb906d53a5933d96f3301635d6c416491d6a0ebd0bf4d4e448af5762d"
+	)
+
+	var (
+		fileContents []byte
+	)
+
+	BeforeEach(func() {
+		fileContents = []byte("foobar contents")
+	})
+
+	Describe("Hasher", func() {
+		var (
+			algorithm checksum.Algorithm
+		)
+
+		BeforeEach(func() {
+			algorithm = checksum.AlgorithmBoth
+		})
+
+		It("returns the digests of everything written to it", func() {
+			hasher := checksum.NewHasher(algorithm)
+
+			_, err := hasher.Write(fileContents[:6])
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = hasher.Write(fileContents[6:])
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(hasher.Sums()).To(Equal(checksum.Checksums{
+				MD5:    expectedMD5,
+				SHA256: expectedSHA256,
+			}))
+		})
+
+		It("discards previous writes when reset", func() {
+			hasher := checksum.NewHasher(algorithm)
+
+			_, err := hasher.Write([]byte("some partial write"))
+			Expect(err).NotTo(HaveOccurred())
+
+			hasher.Reset()
+
+			_, err = hasher.Write(fileContents)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(hasher.Sums()).To(Equal(checksum.Checksums{
+				MD5:    expectedMD5,
+				SHA256: expectedSHA256,
+			}))
+		})
+	})
+
+	Describe("FileSummer", func() {
+		var (
+			tempFilePath string
+			tempDir      string
+
+			algorithm checksum.Algorithm
+
+			fileSummer *checksum.FileSummer
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			tempFilePath = filepath.Join(tempDir, "foobar")
+
+			ioutil.WriteFile(tempFilePath, fileContents, os.ModePerm)
+
+			algorithm = checksum.AlgorithmMD5
+		})
+
+		JustBeforeEach(func() {
+			fileSummer = checksum.NewFileSummer(algorithm)
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns only the MD5 of a file without error", func() {
+			sums, err := fileSummer.SumFile(tempFilePath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sums.MD5).To(Equal(expectedMD5))
+			Expect(sums.SHA256).To(BeEmpty())
+		})
+
+		Context("when the algorithm is not provided", func() {
+			BeforeEach(func() {
+				algorithm = ""
+			})
+
+			It("defaults to MD5", func() {
+				sums, err := fileSummer.SumFile(tempFilePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(sums.MD5).To(Equal(expectedMD5))
+				Expect(sums.SHA256).To(BeEmpty())
+			})
+		})
+
+		Context("when the algorithm is sha256", func() {
+			BeforeEach(func() {
+				algorithm = checksum.AlgorithmSHA256
+			})
+
+			It("returns only the SHA-256 of a file", func() {
+				sums, err := fileSummer.SumFile(tempFilePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(sums.MD5).To(BeEmpty())
+				Expect(sums.SHA256).To(Equal(expectedSHA256))
+			})
+		})
+
+		Context("when the algorithm is both", func() {
+			BeforeEach(func() {
+				algorithm = checksum.AlgorithmBoth
+			})
+
+			It("returns the MD5 and SHA-256 of a file", func() {
+				sums, err := fileSummer.SumFile(tempFilePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(sums.MD5).To(Equal(expectedMD5))
+				Expect(sums.SHA256).To(Equal(expectedSHA256))
+			})
+		})
+
+		Context("when there is an error reading the file", func() {
+			BeforeEach(func() {
+				tempFilePath = "/not/a/valid/file"
+			})
+
+			It("returns the error", func() {
+				_, err := fileSummer.SumFile(tempFilePath)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
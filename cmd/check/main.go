@@ -5,12 +5,15 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logshim"
 	"github.com/pivotal-cf/pivnet-resource/check"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
 	"github.com/pivotal-cf/pivnet-resource/filter"
+	"github.com/pivotal-cf/pivnet-resource/fingerprint"
 	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/semver"
 	"github.com/pivotal-cf/pivnet-resource/sorter"
@@ -68,15 +71,30 @@ func main() {
 		Token:     input.Source.APIToken,
 		UserAgent: useragent.UserAgent(version, "check", input.Source.ProductSlug),
 	}
-	client := gp.NewClient(
+	// The cache lives in the temporary directory so that it is shared by
+	// subsequent checks in the same container.
+	cache := gp.NewResponseCache(filepath.Join(os.TempDir(), "pivnet-resource-cache"))
+
+	// The retry backoff has already been validated, and an empty backoff
+	// uses the default.
+	retryBackoff, _ := time.ParseDuration(input.Source.RetryBackoff)
+
+	client := gp.NewClientWithOptions(
 		clientConfig,
+		gp.ClientOptions{
+			Cache: cache,
+			Retry: gp.RetryConfig{
+				Attempts:     input.Source.RetryAttempts,
+				InitialDelay: retryBackoff,
+			},
+		},
 		ls,
 	)
 
-	f := filter.NewFilter(ls)
-
 	semverConverter := semver.NewSemverConverter(ls)
+	f := filter.NewFilter(ls, semverConverter)
 	s := sorter.NewSorter(ls, semverConverter)
+	fp := fingerprint.NewFingerprinter(client, input.Source.FingerprintMode, ls)
 
 	response, err := check.NewCheckCommand(
 		ls,
@@ -84,6 +102,8 @@ func main() {
 		f,
 		client,
 		s,
+		semverConverter,
+		fp,
 		logFile.Name(),
 	).Run(input)
 	if err != nil {
@@ -4,21 +4,32 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"time"
 
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
 	"github.com/pivotal-cf/pivnet-resource/downloader"
 	"github.com/pivotal-cf/pivnet-resource/filter"
+	"github.com/pivotal-cf/pivnet-resource/fingerprint"
 	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/in"
 	"github.com/pivotal-cf/pivnet-resource/in/filesystem"
-	"github.com/pivotal-cf/pivnet-resource/md5sum"
+	"github.com/pivotal-cf/pivnet-resource/semver"
+	"github.com/pivotal-cf/pivnet-resource/unpacker"
 	"github.com/pivotal-cf/pivnet-resource/useragent"
 	"github.com/pivotal-cf/pivnet-resource/validator"
 	"github.com/robdimsdale/sanitizer"
 )
 
+const (
+	// Product files larger than chunkThreshold bytes are downloaded in
+	// chunkConnections byte ranges concurrently.
+	chunkThreshold   = 512 * 1024 * 1024
+	chunkConnections = 4
+)
+
 var (
 	// version is deliberately left uninitialized so it can be set at compile-time
 	version string
@@ -76,25 +87,55 @@ func main() {
 		UserAgent: useragent.UserAgent(version, "get", input.Source.ProductSlug),
 	}
 
-	client := gp.NewClient(
+	// The retry backoff has already been validated, and an empty backoff
+	// uses the default.
+	retryBackoff, _ := time.ParseDuration(input.Source.RetryBackoff)
+
+	client := gp.NewClientWithOptions(
 		clientConfig,
+		gp.ClientOptions{
+			Retry: gp.RetryConfig{
+				Attempts:     input.Source.RetryAttempts,
+				InitialDelay: retryBackoff,
+			},
+		},
+		ls,
+	)
+
+	// Not every product file has a SHA256 on Pivnet, so the MD5 is always
+	// calculated for files to fall back on.
+	checksumAlgorithm := checksum.Algorithm(input.Source.Checksum)
+	if checksumAlgorithm == checksum.AlgorithmSHA256 {
+		checksumAlgorithm = checksum.AlgorithmBoth
+	}
+
+	d := downloader.NewDownloader(
+		client,
+		checksum.NewFileSummer(checksumAlgorithm),
+		checksumAlgorithm,
+		downloadDir,
+		input.Params.ParallelDownloads,
+		chunkThreshold,
+		chunkConnections,
 		ls,
 	)
 
-	d := downloader.NewDownloader(client, downloadDir, ls)
-	fs := md5sum.NewFileSummer()
+	f := filter.NewFilter(ls, semver.NewSemverConverter(ls))
 
-	f := filter.NewFilter(ls)
+	u := unpacker.NewUnpacker(input.Params.DeleteArchives, ls)
 
 	fileWriter := filesystem.NewFileWriter(downloadDir, ls)
 
+	fp := fingerprint.NewFingerprinter(client, input.Source.FingerprintMode, ls)
+
 	response, err := in.NewInCommand(
 		ls,
 		client,
 		f,
 		d,
-		fs,
+		u,
 		fileWriter,
+		fp,
 	).Run(input)
 	if err != nil {
 		log.Fatalf("Exiting with error: %s", err)
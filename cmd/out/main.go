@@ -12,11 +12,12 @@ import (
 
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
 	"github.com/pivotal-cf/pivnet-resource/filter"
+	"github.com/pivotal-cf/pivnet-resource/fingerprint"
 	"github.com/pivotal-cf/pivnet-resource/globs"
 	"github.com/pivotal-cf/pivnet-resource/gp"
-	"github.com/pivotal-cf/pivnet-resource/md5sum"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 	"github.com/pivotal-cf/pivnet-resource/out"
 	"github.com/pivotal-cf/pivnet-resource/out/release"
@@ -84,8 +85,18 @@ func main() {
 		UserAgent: useragent.UserAgent(version, "put", input.Source.ProductSlug),
 	}
 
-	client := gp.NewClient(
+	// The retry backoff has already been validated, and an empty backoff
+	// uses the default.
+	retryBackoff, _ := time.ParseDuration(input.Source.RetryBackoff)
+
+	client := gp.NewClientWithOptions(
 		clientConfig,
+		gp.ClientOptions{
+			Retry: gp.RetryConfig{
+				Attempts:     input.Source.RetryAttempts,
+				InitialDelay: retryBackoff,
+			},
+		},
 		ls,
 	)
 
@@ -145,13 +156,23 @@ func main() {
 
 	validation := validator.NewOutValidator(input)
 	semverConverter := semver.NewSemverConverter(ls)
-	md5summer := md5sum.NewFileSummer()
 
-	f := filter.NewFilter(ls)
+	// Pivnet requires an MD5 for every product file, so it is always
+	// calculated alongside a SHA256.
+	checksumAlgorithm := checksum.Algorithm(input.Source.Checksum)
+	if checksumAlgorithm == checksum.AlgorithmSHA256 {
+		checksumAlgorithm = checksum.AlgorithmBoth
+	}
+	fileSummer := checksum.NewFileSummer(checksumAlgorithm)
+
+	f := filter.NewFilter(ls, semverConverter)
+
+	transaction := release.NewTransaction(ls)
 
 	releaseCreator := release.NewReleaseCreator(
 		client,
 		semverConverter,
+		transaction,
 		ls,
 		m,
 		input.Params,
@@ -165,18 +186,22 @@ func main() {
 	releaseUploader := release.NewReleaseUploader(
 		uploaderClient,
 		client,
+		transaction,
 		ls,
-		md5summer,
+		fileSummer,
 		m,
 		sourcesDir,
 		input.Source.ProductSlug,
 		asyncTimeout,
 		pollFrequency,
+		input.Params.ParallelUploads,
+		input.Params.RollbackS3Objects,
 	)
 
 	releaseUserGroupsUpdater := release.NewUserGroupsUpdater(
 		ls,
 		client,
+		transaction,
 		m,
 		input.Source.ProductSlug,
 	)
@@ -184,6 +209,7 @@ func main() {
 	releaseDependenciesAdder := release.NewReleaseDependenciesAdder(
 		ls,
 		client,
+		transaction,
 		m,
 		input.Source.ProductSlug,
 	)
@@ -191,13 +217,25 @@ func main() {
 	releaseUpgradePathsAdder := release.NewReleaseUpgradePathsAdder(
 		ls,
 		client,
+		transaction,
 		m,
 		input.Source.ProductSlug,
 		f,
 	)
 
+	releaseFileGroupsAdder := release.NewReleaseFileGroupsAdder(
+		ls,
+		client,
+		transaction,
+		m,
+		input.Source.ProductSlug,
+	)
+
+	fp := fingerprint.NewFingerprinter(client, input.Source.FingerprintMode, ls)
+
 	releaseFinalizer := release.NewFinalizer(
 		client,
+		fp,
 		ls,
 		input.Params,
 		m,
@@ -205,6 +243,16 @@ func main() {
 		input.Source.ProductSlug,
 	)
 
+	planner := release.NewPlanner(
+		releaseCreator,
+		releaseUploader,
+		releaseDependenciesAdder,
+		releaseUpgradePathsAdder,
+		releaseFileGroupsAdder,
+		releaseUserGroupsUpdater,
+		skipUpload,
+	)
+
 	outCmd := out.NewOutCommand(out.OutCommandConfig{
 		Logger:                   ls,
 		OutDir:                   outDir,
@@ -216,9 +264,14 @@ func main() {
 		UserGroupsUpdater:        releaseUserGroupsUpdater,
 		ReleaseDependenciesAdder: releaseDependenciesAdder,
 		ReleaseUpgradePathsAdder: releaseUpgradePathsAdder,
+		ReleaseFileGroupsAdder:   releaseFileGroupsAdder,
 		Finalizer:                releaseFinalizer,
+		Transaction:              transaction,
+		Planner:                  planner,
 		M:                        m,
 		SkipUpload:               skipUpload,
+		RollbackOnFailure:        input.Params.RollbackOnFailure,
+		DryRun:                   input.Params.DryRun,
 	})
 
 	response, err := outCmd.Run(input)
@@ -1,23 +1,96 @@
 package concourse
 
+import "encoding/json"
+
 type SortBy string
 
 const (
-	SortByNone   SortBy = "none"
-	SortBySemver SortBy = "semver"
+	SortByNone        SortBy = "none"
+	SortBySemver      SortBy = "semver"
+	SortByReleaseDate SortBy = "release_date"
+)
+
+// ReleaseDateFormat is the layout of release dates on Pivotal Network.
+const ReleaseDateFormat = "2006-01-02"
+
+type Checksum string
+
+const (
+	ChecksumMD5    Checksum = "md5"
+	ChecksumSHA256 Checksum = "sha256"
+	ChecksumBoth   Checksum = "both"
+)
+
+type FingerprintMode string
+
+const (
+	FingerprintModeUpdatedAt FingerprintMode = "updated_at"
+	FingerprintModeNone      FingerprintMode = "none"
+	FingerprintModeFiles     FingerprintMode = "files"
+)
+
+// OnExistingRelease determines what put does when a release with the same
+// version already exists. Only update reconciles against the existing
+// release; replace, the default, deletes it and creates it from scratch.
+type OnExistingRelease string
+
+const (
+	OnExistingReleaseFail    OnExistingRelease = "fail"
+	OnExistingReleaseReplace OnExistingRelease = "replace"
+	OnExistingReleaseUpdate  OnExistingRelease = "update"
 )
 
+// InitialVersions is either "all", "latest" or a positive number of versions.
+type InitialVersions string
+
+const (
+	InitialVersionsAll    InitialVersions = "all"
+	InitialVersionsLatest InitialVersions = "latest"
+)
+
+// UnmarshalJSON accepts both strings and numbers, so that a count can be
+// provided in pipeline configuration without quoting it.
+func (i *InitialVersions) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err == nil {
+		*i = InitialVersions(n.String())
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*i = InitialVersions(s)
+	return nil
+}
+
 type Source struct {
-	APIToken        string `json:"api_token"`
-	ProductSlug     string `json:"product_slug"`
-	AccessKeyID     string `json:"access_key_id"`
-	ProductVersion  string `json:"product_version"`
-	SecretAccessKey string `json:"secret_access_key"`
-	Bucket          string `json:"bucket"`
-	Endpoint        string `json:"endpoint"`
-	Region          string `json:"region"`
-	ReleaseType     string `json:"release_type"`
-	SortBy          SortBy `json:"sort_by"`
+	APIToken                 string          `json:"api_token"`
+	ProductSlug              string          `json:"product_slug"`
+	ParentProductSlug        string          `json:"parent_product_slug"`
+	DeployedVersion          string          `json:"deployed_version"`
+	AccessKeyID              string          `json:"access_key_id"`
+	ProductVersion           string          `json:"product_version"`
+	ProductVersionConstraint string          `json:"product_version_constraint"`
+	SecretAccessKey          string          `json:"secret_access_key"`
+	Bucket                   string          `json:"bucket"`
+	Endpoint                 string          `json:"endpoint"`
+	Region                   string          `json:"region"`
+	ReleaseType              string          `json:"release_type"`
+	ReleaseTypes             []string        `json:"release_types"`
+	ExcludeReleaseTypes      []string        `json:"exclude_release_types"`
+	Availability             string          `json:"availability"`
+	ExcludeEndOfSupport      bool            `json:"exclude_end_of_support"`
+	ExcludeControlled        bool            `json:"exclude_controlled"`
+	SortBy                   SortBy          `json:"sort_by"`
+	ReleasedAfter            string          `json:"released_after"`
+	InitialVersions          InitialVersions `json:"initial_versions"`
+	FingerprintMode          FingerprintMode `json:"fingerprint_mode"`
+	Checksum                 Checksum        `json:"checksum"`
+	RetryAttempts            int             `json:"retry_attempts"`
+	RetryBackoff             string          `json:"retry_backoff"`
 }
 
 type CheckRequest struct {
@@ -38,7 +111,13 @@ type InRequest struct {
 }
 
 type InParams struct {
-	Globs []string `json:"globs"`
+	Globs                 []string `json:"globs"`
+	ParallelDownloads     int      `json:"parallel_downloads"`
+	Unpack                bool     `json:"unpack"`
+	DeleteArchives        bool     `json:"delete_archives"`
+	SkipDownloads         bool     `json:"skip_downloads"`
+	AcceptEULA            *bool    `json:"accept_eula"`
+	AllowStaleFingerprint bool     `json:"allow_stale_fingerprint"`
 }
 
 type InResponse struct {
@@ -60,6 +139,15 @@ type OutParams struct {
 	FileGlob       string `json:"file_glob"`
 	FilepathPrefix string `json:"s3_filepath_prefix"`
 	MetadataFile   string `json:"metadata_file"`
+
+	OnExistingRelease OnExistingRelease `json:"on_existing_release"`
+
+	ParallelUploads int `json:"parallel_uploads"`
+
+	RollbackOnFailure bool `json:"rollback_on_failure"`
+	RollbackS3Objects bool `json:"rollback_s3_objects"`
+
+	DryRun bool `json:"dry_run"`
 }
 
 type OutResponse struct {
@@ -0,0 +1,249 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
+)
+
+type chunk struct {
+	start int64
+	end   int64
+}
+
+func (d Downloader) shouldChunk(pf pivnet.ProductFile) bool {
+	return d.chunkThreshold > 0 &&
+		d.chunkConnections > 1 &&
+		int64(pf.Size) > d.chunkThreshold
+}
+
+// downloadProductFileInChunks splits the product file into byte ranges that
+// are downloaded concurrently and written in place into file. If the server
+// does not honour range requests, the file is downloaded over a single
+// connection instead. As the chunks arrive out of order, the checksums of the
+// reassembled file are calculated by reading it back, and it is verified
+// against the MD5 of the product file.
+func (d Downloader) downloadProductFileInChunks(
+	file *os.File,
+	done <-chan struct{},
+	fileName string,
+	pf pivnet.ProductFile,
+	productSlug string,
+	releaseID int,
+	maxAttempts int,
+) (checksum.Checksums, error) {
+	size := int64(pf.Size)
+
+	err := file.Truncate(size)
+	if err != nil {
+		return checksum.Checksums{}, err
+	}
+
+	chunks := splitIntoChunks(size, d.chunkConnections)
+
+	d.logger.Info(fmt.Sprintf(
+		"[%s] Downloading %d bytes in %d chunks",
+		fileName,
+		size,
+		len(chunks),
+	))
+
+	chunksDone := make(chan struct{})
+	var cancelOnce sync.Once
+	cancelChunks := func() {
+		cancelOnce.Do(func() { close(chunksDone) })
+	}
+
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-done:
+			cancelChunks()
+		case <-finished:
+		}
+	}()
+
+	resumed := make([]bool, len(chunks))
+	errs := make([]error, len(chunks))
+
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			resumed[i], errs[i] = d.downloadChunkWithRetries(
+				file,
+				chunksDone,
+				fileName,
+				productSlug,
+				releaseID,
+				pf.ID,
+				chunks[i],
+				maxAttempts,
+			)
+			if errs[i] != nil || !resumed[i] {
+				cancelChunks()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && err != errDownloadCancelled {
+			return checksum.Checksums{}, err
+		}
+	}
+
+	select {
+	case <-done:
+		return checksum.Checksums{}, errDownloadCancelled
+	default:
+	}
+
+	for _, r := range resumed {
+		if !r {
+			d.logger.Info(fmt.Sprintf(
+				"[%s] Server does not support range requests - downloading over a single connection",
+				fileName,
+			))
+
+			err = file.Truncate(0)
+			if err != nil {
+				return checksum.Checksums{}, err
+			}
+
+			_, err = file.Seek(0, io.SeekStart)
+			if err != nil {
+				return checksum.Checksums{}, err
+			}
+
+			return d.downloadProductFileWithRetries(file, done, fileName, productSlug, releaseID, pf.ID, maxAttempts)
+		}
+	}
+
+	return d.verifyChunkedDownload(file.Name(), fileName, pf.MD5)
+}
+
+// downloadChunkWithRetries downloads a single chunk, resuming from the last
+// byte written if a retryable error occurs. It returns false if the server
+// did not honour the range request.
+func (d Downloader) downloadChunkWithRetries(
+	file *os.File,
+	done <-chan struct{},
+	fileName string,
+	productSlug string,
+	releaseID int,
+	productFileID int,
+	c chunk,
+	maxAttempts int,
+) (bool, error) {
+	writer := &offsetWriter{file: file, offset: c.start}
+	cw := cancellableWriter{writer: writer, done: done}
+
+	var err error
+
+	for i := 0; i < maxAttempts; i++ {
+		start := writer.offset
+
+		d.logger.Debug(fmt.Sprintf(
+			"[%s] Downloading bytes %d-%d",
+			fileName,
+			start,
+			c.end,
+		))
+
+		var resumed bool
+		resumed, err = d.client.DownloadProductFileRange(cw, productSlug, releaseID, productFileID, start, c.end)
+		if err == nil && !resumed {
+			return false, nil
+		}
+
+		if err == nil && writer.offset != c.end+1 {
+			err = io.ErrUnexpectedEOF
+		}
+
+		if err != nil {
+			retryable := d.errorRetryable(err, fileName)
+
+			if !retryable {
+				return true, err
+			}
+
+			d.logger.Info(fmt.Sprintf(
+				"[%s] Retrying download of bytes %d-%d due retryable error: %s",
+				fileName,
+				writer.offset,
+				c.end,
+				err.Error(),
+			))
+
+			continue
+		}
+
+		return true, nil
+	}
+
+	return true, err
+}
+
+func (d Downloader) verifyChunkedDownload(
+	downloadPath string,
+	fileName string,
+	expectedMD5 string,
+) (checksum.Checksums, error) {
+	d.logger.Info(fmt.Sprintf("[%s] Calculating checksums of reassembled file", fileName))
+
+	sums, err := d.fileSummer.SumFile(downloadPath)
+	if err != nil {
+		return checksum.Checksums{}, err
+	}
+
+	if expectedMD5 != "" && sums.MD5 != "" && sums.MD5 != expectedMD5 {
+		return checksum.Checksums{}, fmt.Errorf(
+			"MD5 comparison failed for chunked download of file: '%s'. Expected (from pivnet): '%s' - actual (from file): '%s'",
+			downloadPath,
+			expectedMD5,
+			sums.MD5,
+		)
+	}
+
+	return sums, nil
+}
+
+// splitIntoChunks divides size bytes into n contiguous, inclusive ranges.
+func splitIntoChunks(size int64, n int) []chunk {
+	chunkSize := size / int64(n)
+	if size%int64(n) != 0 {
+		chunkSize++
+	}
+
+	var chunks []chunk
+	for start := int64(0); start < size; start += chunkSize {
+		end := start + chunkSize - 1
+		if end > size-1 {
+			end = size - 1
+		}
+		chunks = append(chunks, chunk{start: start, end: end})
+	}
+
+	return chunks
+}
+
+// offsetWriter writes sequentially into file starting at offset, allowing
+// several writers to fill different regions of the same file concurrently.
+type offsetWriter struct {
+	file   *os.File
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.file.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
@@ -1,125 +1,323 @@
 package downloader
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 )
 
+var errDownloadCancelled = errors.New("download cancelled")
+
 //go:generate counterfeiter --fake-name FakeClient . client
 type client interface {
 	DownloadProductFile(writer io.Writer, productSlug string, releaseID int, productFileID int) error
+	DownloadProductFileFromOffset(writer io.Writer, productSlug string, releaseID int, productFileID int, offset int64) (bool, error)
+	DownloadProductFileRange(writer io.Writer, productSlug string, releaseID int, productFileID int, start int64, end int64) (bool, error)
+}
+
+//go:generate counterfeiter --fake-name FakeFileSummer . fileSummer
+type fileSummer interface {
+	SumFile(filepath string) (checksum.Checksums, error)
 }
 
 type Downloader struct {
-	client      client
-	downloadDir string
-	logger      logger.Logger
+	client            client
+	fileSummer        fileSummer
+	checksumAlgorithm checksum.Algorithm
+	downloadDir       string
+	parallelDownloads int
+	chunkThreshold    int64
+	chunkConnections  int
+	logger            logger.Logger
 }
 
 func NewDownloader(
 	client client,
+	fileSummer fileSummer,
+	checksumAlgorithm checksum.Algorithm,
 	downloadDir string,
+	parallelDownloads int,
+	chunkThreshold int64,
+	chunkConnections int,
 	logger logger.Logger,
 ) *Downloader {
 	return &Downloader{
-		client:      client,
-		downloadDir: downloadDir,
-		logger:      logger,
+		client:            client,
+		fileSummer:        fileSummer,
+		checksumAlgorithm: checksumAlgorithm,
+		downloadDir:       downloadDir,
+		parallelDownloads: parallelDownloads,
+		chunkThreshold:    chunkThreshold,
+		chunkConnections:  chunkConnections,
+		logger:            logger,
 	}
 }
 
+type downloadResult struct {
+	downloadPath string
+	checksums    checksum.Checksums
+	err          error
+}
+
+// Download fetches the provided product files using up to parallelDownloads
+// concurrent workers. The returned filepaths are in the same order as the
+// provided product files, and the checksums of each file are keyed by its
+// filepath. If any download fails, the remaining downloads are cancelled and
+// the first error is returned.
 func (d Downloader) Download(
 	pfs []pivnet.ProductFile,
 	productSlug string,
 	releaseID int,
-) ([]string, error) {
+) ([]string, map[string]checksum.Checksums, error) {
 	d.logger.Debug("Ensuring download directory exists")
 
 	err := os.MkdirAll(d.downloadDir, os.ModePerm)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var fileNames []string
-	for _, pf := range pfs {
-		parts := strings.Split(pf.AWSObjectKey, "/")
-		fileName := parts[len(parts)-1]
+	workers := d.parallelDownloads
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(pfs) {
+		workers = len(pfs)
+	}
 
-		downloadPath := filepath.Join(d.downloadDir, fileName)
+	results := make([]downloadResult, len(pfs))
+	indices := make(chan int, len(pfs))
+	for i := range pfs {
+		indices <- i
+	}
+	close(indices)
 
-		d.logger.Debug(fmt.Sprintf("Creating file: '%s'", downloadPath))
-		file, err := os.Create(downloadPath)
-		if err != nil {
-			return nil, err
+	done := make(chan struct{})
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() { close(done) })
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				select {
+				case <-done:
+					results[i].err = errDownloadCancelled
+					continue
+				default:
+				}
+
+				results[i].downloadPath, results[i].checksums, results[i].err = d.downloadProductFile(
+					pfs[i],
+					productSlug,
+					releaseID,
+					done,
+				)
+				if results[i].err != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Downloads are only cancelled as a result of another download failing,
+	// so return that failure rather than any of the cancellations.
+	for _, r := range results {
+		if r.err != nil && r.err != errDownloadCancelled {
+			return nil, nil, r.err
 		}
+	}
 
-		d.logger.Info(fmt.Sprintf(
-			"Downloading: '%s' to file: '%s'",
-			pf.Name,
-			downloadPath,
-		))
+	fileNames := make([]string, len(results))
+	fileChecksums := make(map[string]checksum.Checksums, len(results))
+	for i, r := range results {
+		fileNames[i] = r.downloadPath
+		fileChecksums[r.downloadPath] = r.checksums
+	}
 
-		maxAttempts := 3
-		err = d.downloadProductFileWithRetries(file, productSlug, releaseID, pf.ID, maxAttempts)
-		if err != nil {
-			d.logger.Info(fmt.Sprintf("Download failed after %d attempts: %s",
+	return fileNames, fileChecksums, nil
+}
+
+func (d Downloader) downloadProductFile(
+	pf pivnet.ProductFile,
+	productSlug string,
+	releaseID int,
+	done <-chan struct{},
+) (string, checksum.Checksums, error) {
+	parts := strings.Split(pf.AWSObjectKey, "/")
+	fileName := parts[len(parts)-1]
+
+	downloadPath := filepath.Join(d.downloadDir, fileName)
+
+	d.logger.Debug(fmt.Sprintf("Creating file: '%s'", downloadPath))
+	file, err := os.Create(downloadPath)
+	if err != nil {
+		return "", checksum.Checksums{}, err
+	}
+	defer file.Close()
+
+	d.logger.Info(fmt.Sprintf(
+		"[%s] Downloading: '%s' to file: '%s'",
+		fileName,
+		pf.Name,
+		downloadPath,
+	))
+
+	maxAttempts := 3
+
+	var sums checksum.Checksums
+	if d.shouldChunk(pf) {
+		sums, err = d.downloadProductFileInChunks(file, done, fileName, pf, productSlug, releaseID, maxAttempts)
+	} else {
+		sums, err = d.downloadProductFileWithRetries(file, done, fileName, productSlug, releaseID, pf.ID, maxAttempts)
+	}
+	if err != nil {
+		if err != errDownloadCancelled {
+			d.logger.Info(fmt.Sprintf("[%s] Download failed after %d attempts: %s",
+				fileName,
 				maxAttempts,
 				err.Error(),
 			))
-			return nil, err
 		}
-		fileNames = append(fileNames, downloadPath)
+		return "", checksum.Checksums{}, err
 	}
 
-	return fileNames, nil
+	d.logger.Info(fmt.Sprintf("[%s] Download complete", fileName))
+
+	return downloadPath, sums, nil
 }
 
+// downloadProductFileWithRetries downloads the product file into file,
+// calculating its checksums as it is written. If the download had to be
+// resumed part way through the file, the checksums are instead calculated
+// by reading the file back once the download completes.
 func (d Downloader) downloadProductFileWithRetries(
-	file io.Writer,
+	file *os.File,
+	done <-chan struct{},
+	fileName string,
 	productSlug string,
 	releaseID int,
 	productFileID int,
 	maxAttempts int,
-) error {
+) (checksum.Checksums, error) {
+	hasher := checksum.NewHasher(d.checksumAlgorithm)
+	writer := cancellableWriter{writer: io.MultiWriter(file, hasher), done: done}
+
 	var err error
+	var resumed bool
 
 	for i := 0; i < maxAttempts; i++ {
-		err = d.client.DownloadProductFile(file, productSlug, releaseID, productFileID)
+		if i == 0 {
+			err = d.client.DownloadProductFile(writer, productSlug, releaseID, productFileID)
+		} else {
+			var r bool
+			r, err = d.resumeProductFileDownload(file, writer, hasher, fileName, productSlug, releaseID, productFileID)
+			resumed = resumed || r
+		}
 
 		if err != nil {
-			retryable := d.errorRetryable(err)
+			retryable := d.errorRetryable(err, fileName)
 
 			if !retryable {
-				return err
+				return checksum.Checksums{}, err
 			}
 
 			d.logger.Info(fmt.Sprintf(
-				"Retrying download due retryable error: %s",
+				"[%s] Retrying download due retryable error: %s",
+				fileName,
 				err.Error(),
 			))
 
 			continue
 		}
 
-		return nil
+		if !resumed {
+			return hasher.Sums(), nil
+		}
+
+		d.logger.Info(fmt.Sprintf(
+			"[%s] Calculating checksums of resumed download",
+			fileName,
+		))
+
+		return d.fileSummer.SumFile(file.Name())
+	}
+
+	return checksum.Checksums{}, err
+}
+
+// resumeProductFileDownload continues a download from the end of the
+// partially-written file, returning true if it did so. If the server does
+// not honour range requests the file is truncated and the download restarts
+// from the beginning.
+func (d Downloader) resumeProductFileDownload(
+	file *os.File,
+	writer io.Writer,
+	hasher *checksum.Hasher,
+	fileName string,
+	productSlug string,
+	releaseID int,
+	productFileID int,
+) (bool, error) {
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+
+	if offset > 0 {
+		d.logger.Info(fmt.Sprintf(
+			"[%s] Resuming download from byte: %d",
+			fileName,
+			offset,
+		))
+
+		resumed, err := d.client.DownloadProductFileFromOffset(writer, productSlug, releaseID, productFileID, offset)
+		if resumed || err != nil {
+			return resumed, err
+		}
+
+		d.logger.Info(fmt.Sprintf(
+			"[%s] Server does not support resuming downloads - restarting download",
+			fileName,
+		))
+
+		err = file.Truncate(0)
+		if err != nil {
+			return false, err
+		}
+
+		_, err = file.Seek(0, io.SeekStart)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	return err
+	hasher.Reset()
+
+	return false, d.client.DownloadProductFile(writer, productSlug, releaseID, productFileID)
 }
 
 // errorRetryable returns true if error indicates download can be retried.
 // provided err must be non-nil
-func (d Downloader) errorRetryable(err error) bool {
+func (d Downloader) errorRetryable(err error, fileName string) bool {
 	if err == io.ErrUnexpectedEOF {
 		d.logger.Info(fmt.Sprintf(
-			"Received unexpected EOF error: %s",
+			"[%s] Received unexpected EOF error: %s",
+			fileName,
 			err.Error(),
 		))
 		return true
@@ -128,7 +326,8 @@ func (d Downloader) errorRetryable(err error) bool {
 	if netErr, ok := err.(net.Error); ok {
 		if netErr.Temporary() {
 			d.logger.Info(fmt.Sprintf(
-				"Received temporary network error: %s",
+				"[%s] Received temporary network error: %s",
+				fileName,
 				err.Error(),
 			))
 			return true
@@ -137,3 +336,20 @@ func (d Downloader) errorRetryable(err error) bool {
 
 	return false
 }
+
+// cancellableWriter aborts an in-flight download once done is closed by
+// failing the next write.
+type cancellableWriter struct {
+	writer io.Writer
+	done   <-chan struct{}
+}
+
+func (w cancellableWriter) Write(p []byte) (int, error) {
+	select {
+	case <-w.done:
+		return 0, errDownloadCancelled
+	default:
+	}
+
+	return w.writer.Write(p)
+}
@@ -7,33 +7,42 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 	"github.com/pivotal-cf/pivnet-resource/downloader"
 	"github.com/pivotal-cf/pivnet-resource/downloader/downloaderfakes"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/onsi/gomega/ghttp"
 )
 
 var _ = Describe("Downloader", func() {
 	var (
-		fakeClient *downloaderfakes.FakeClient
-		d          *downloader.Downloader
-		server     *ghttp.Server
-		apiAddress string
-		dir        string
-		fakeLogger logger.Logger
+		fakeClient     *downloaderfakes.FakeClient
+		fakeFileSummer *downloaderfakes.FakeFileSummer
+		d              *downloader.Downloader
+		dir            string
+		fakeLogger     logger.Logger
+
+		checksumAlgorithm checksum.Algorithm
+		parallelDownloads int
+		chunkThreshold    int64
+		chunkConnections  int
 	)
 
 	BeforeEach(func() {
 		fakeClient = &downloaderfakes.FakeClient{}
+		fakeFileSummer = &downloaderfakes.FakeFileSummer{}
 
-		server = ghttp.NewServer()
-		apiAddress = server.URL()
+		checksumAlgorithm = checksum.AlgorithmMD5
+		parallelDownloads = 1
+		chunkThreshold = 0
+		chunkConnections = 0
 		logger := log.New(GinkgoWriter, "", log.LstdFlags)
 		fakeLogger = logshim.NewLogShim(logger, logger, true)
 
@@ -43,7 +52,16 @@ var _ = Describe("Downloader", func() {
 	})
 
 	JustBeforeEach(func() {
-		d = downloader.NewDownloader(fakeClient, dir, fakeLogger)
+		d = downloader.NewDownloader(
+			fakeClient,
+			fakeFileSummer,
+			checksumAlgorithm,
+			dir,
+			parallelDownloads,
+			chunkThreshold,
+			chunkConnections,
+			fakeLogger,
+		)
 	})
 
 	AfterEach(func() {
@@ -79,7 +97,7 @@ var _ = Describe("Downloader", func() {
 		})
 
 		It("returns a list of (full) filepaths", func() {
-			filepaths, err := d.Download(productFiles, productSlug, releaseID)
+			filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(len(filepaths)).To(Equal(3))
@@ -89,6 +107,290 @@ var _ = Describe("Downloader", func() {
 			Expect(filepaths).Should(ContainElement(filepath.Join(dir, "file-2")))
 		})
 
+		Context("when the files are downloaded", func() {
+			BeforeEach(func() {
+				checksumAlgorithm = checksum.AlgorithmBoth
+
+				fakeClient.DownloadProductFileStub = func(w io.Writer, s string, r int, p int) error {
+					_, err := w.Write([]byte("some-contents"))
+					Expect(err).NotTo(HaveOccurred())
+
+					return nil
+				}
+			})
+
+			It("calculates checksums while downloading without reading the files back", func() {
+				filepaths, checksums, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(checksums).To(HaveLen(len(filepaths)))
+				for _, f := range filepaths {
+					Expect(checksums[f]).To(Equal(checksum.Checksums{
+						MD5:    "0b9791ad102b5f5f06ef68cef2aae26e",
+						SHA256: "6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800",
+					}))
+				}
+
+				Expect(fakeFileSummer.SumFileCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when parallel downloads is greater than one", func() {
+			BeforeEach(func() {
+				parallelDownloads = 3
+
+				productFiles[0].ID = 1
+				productFiles[1].ID = 2
+				productFiles[2].ID = 3
+			})
+
+			It("downloads the files concurrently", func() {
+				started := make(chan struct{}, len(productFiles))
+				allStarted := make(chan struct{})
+
+				var once sync.Once
+				fakeClient.DownloadProductFileStub = func(w io.Writer, s string, r int, p int) error {
+					started <- struct{}{}
+					if len(started) == cap(started) {
+						once.Do(func() { close(allStarted) })
+					}
+
+					select {
+					case <-allStarted:
+						return nil
+					case <-time.After(5 * time.Second):
+						return errors.New("downloads were not concurrent")
+					}
+				}
+
+				_, _, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(3))
+			})
+
+			It("returns the filepaths in the order of the product files", func() {
+				fakeClient.DownloadProductFileStub = func(w io.Writer, s string, r int, p int) error {
+					if p == 1 {
+						time.Sleep(50 * time.Millisecond)
+					}
+					return nil
+				}
+
+				filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepaths).To(Equal([]string{
+					filepath.Join(dir, "file-0"),
+					filepath.Join(dir, "file-1"),
+					filepath.Join(dir, "file-2"),
+				}))
+			})
+
+			Context("when one of the downloads fails", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = errors.New("download file error")
+				})
+
+				It("cancels the remaining downloads and returns the error", func() {
+					fakeClient.DownloadProductFileStub = func(w io.Writer, s string, r int, p int) error {
+						if p == 1 {
+							return expectedErr
+						}
+
+						timeout := time.After(5 * time.Second)
+						for {
+							select {
+							case <-timeout:
+								return errors.New("download was not cancelled")
+							default:
+							}
+
+							_, err := w.Write([]byte("some bytes"))
+							if err != nil {
+								return err
+							}
+							time.Sleep(10 * time.Millisecond)
+						}
+					}
+
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+		})
+
+		Context("when a product file is larger than the chunk threshold", func() {
+			var (
+				contents string
+			)
+
+			BeforeEach(func() {
+				chunkThreshold = 10
+				chunkConnections = 3
+
+				contents = "some-large-file-contents"
+
+				productFiles = []pivnet.ProductFile{
+					{
+						ID:           1,
+						Name:         "pf-0",
+						AWSObjectKey: "bucket/path/file-0",
+						Size:         len(contents),
+						MD5:          "some-md5",
+					},
+				}
+
+				fakeClient.DownloadProductFileRangeStub = func(w io.Writer, s string, r int, p int, start int64, end int64) (bool, error) {
+					_, err := w.Write([]byte(contents[start : end+1]))
+					Expect(err).NotTo(HaveOccurred())
+
+					return true, nil
+				}
+
+				fakeFileSummer.SumFileReturns(checksum.Checksums{MD5: "some-md5"}, nil)
+			})
+
+			It("downloads the file in chunks and reassembles it in place", func() {
+				filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(0))
+				Expect(fakeClient.DownloadProductFileRangeCallCount()).To(Equal(3))
+
+				var ranges [][]int64
+				for i := 0; i < 3; i++ {
+					_, _, _, _, start, end := fakeClient.DownloadProductFileRangeArgsForCall(i)
+					ranges = append(ranges, []int64{start, end})
+				}
+				Expect(ranges).To(ConsistOf(
+					[]int64{0, 7},
+					[]int64{8, 15},
+					[]int64{16, 23},
+				))
+
+				downloaded, err := ioutil.ReadFile(filepaths[0])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(downloaded)).To(Equal(contents))
+			})
+
+			It("verifies the MD5 of the reassembled file", func() {
+				filepaths, checksums, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeFileSummer.SumFileCallCount()).To(Equal(1))
+				Expect(fakeFileSummer.SumFileArgsForCall(0)).To(Equal(filepaths[0]))
+
+				Expect(checksums[filepaths[0]].MD5).To(Equal("some-md5"))
+			})
+
+			Context("when the MD5 does not match", func() {
+				BeforeEach(func() {
+					fakeFileSummer.SumFileReturns(checksum.Checksums{MD5: "some-other-md5"}, nil)
+				})
+
+				It("returns an error", func() {
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("MD5 comparison failed"))
+				})
+			})
+
+			Context("when a chunk fails part way through", func() {
+				BeforeEach(func() {
+					var mutex sync.Mutex
+					failed := false
+
+					fakeClient.DownloadProductFileRangeStub = func(w io.Writer, s string, r int, p int, start int64, end int64) (bool, error) {
+						mutex.Lock()
+						shouldFail := !failed && start == 8
+						if shouldFail {
+							failed = true
+						}
+						mutex.Unlock()
+
+						if shouldFail {
+							_, err := w.Write([]byte(contents[start : start+2]))
+							Expect(err).NotTo(HaveOccurred())
+
+							return true, io.ErrUnexpectedEOF
+						}
+
+						_, err := w.Write([]byte(contents[start : end+1]))
+						Expect(err).NotTo(HaveOccurred())
+
+						return true, nil
+					}
+				})
+
+				It("resumes the chunk from the last byte written", func() {
+					filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeClient.DownloadProductFileRangeCallCount()).To(Equal(4))
+
+					var starts []int64
+					for i := 0; i < 4; i++ {
+						_, _, _, _, start, _ := fakeClient.DownloadProductFileRangeArgsForCall(i)
+						starts = append(starts, start)
+					}
+					Expect(starts).To(ContainElement(int64(10)))
+
+					downloaded, err := ioutil.ReadFile(filepaths[0])
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(downloaded)).To(Equal(contents))
+				})
+			})
+
+			Context("when the server does not honour range requests", func() {
+				BeforeEach(func() {
+					fakeClient.DownloadProductFileRangeStub = nil
+					fakeClient.DownloadProductFileRangeReturns(false, nil)
+
+					fakeClient.DownloadProductFileStub = func(w io.Writer, s string, r int, p int) error {
+						_, err := w.Write([]byte(contents))
+						Expect(err).NotTo(HaveOccurred())
+
+						return nil
+					}
+				})
+
+				It("downloads the file over a single connection", func() {
+					filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(1))
+
+					downloaded, err := ioutil.ReadFile(filepaths[0])
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(downloaded)).To(Equal(contents))
+				})
+			})
+
+			Context("when a chunk returns a non-retryable error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = errors.New("some range error")
+
+					fakeClient.DownloadProductFileRangeStub = nil
+					fakeClient.DownloadProductFileRangeReturns(true, expectedErr)
+				})
+
+				It("returns the error", func() {
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+		})
+
 		Context("when the pivnet client returns an error", func() {
 			BeforeEach(func() {
 				productFiles = []pivnet.ProductFile{
@@ -115,7 +417,7 @@ var _ = Describe("Downloader", func() {
 					})
 
 					It("attempts three downloads", func() {
-						_, err := d.Download(productFiles, productSlug, releaseID)
+						_, _, err := d.Download(productFiles, productSlug, releaseID)
 
 						Expect(err).Should(HaveOccurred())
 						Expect(err).To(Equal(expectedErr))
@@ -137,7 +439,7 @@ var _ = Describe("Downloader", func() {
 						})
 
 						It("does not throw an error", func() {
-							_, err := d.Download(productFiles, productSlug, releaseID)
+							_, _, err := d.Download(productFiles, productSlug, releaseID)
 
 							Expect(err).ShouldNot(HaveOccurred())
 							Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(2))
@@ -147,7 +449,7 @@ var _ = Describe("Downloader", func() {
 
 				Context("when the network error is not temporary", func() {
 					It("raises an error", func() {
-						_, err := d.Download(productFiles, productSlug, releaseID)
+						_, _, err := d.Download(productFiles, productSlug, releaseID)
 
 						Expect(err).Should(HaveOccurred())
 						Expect(err).To(Equal(expectedErr))
@@ -162,7 +464,7 @@ var _ = Describe("Downloader", func() {
 				})
 
 				It("attempts three downloads", func() {
-					_, err := d.Download(productFiles, productSlug, releaseID)
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
 
 					Expect(err).Should(HaveOccurred())
 					Expect(err).To(Equal(io.ErrUnexpectedEOF))
@@ -184,7 +486,7 @@ var _ = Describe("Downloader", func() {
 					})
 
 					It("does not throw an error", func() {
-						_, err := d.Download(productFiles, productSlug, releaseID)
+						_, _, err := d.Download(productFiles, productSlug, releaseID)
 
 						Expect(err).ShouldNot(HaveOccurred())
 						Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(2))
@@ -192,6 +494,106 @@ var _ = Describe("Downloader", func() {
 				})
 			})
 
+			Context("when the download fails after writing part of the file", func() {
+				BeforeEach(func() {
+					downloadAttempts := 0
+
+					fakeClient.DownloadProductFileStub = func(w io.Writer, s string, r int, p int) error {
+						downloadAttempts++
+						if downloadAttempts == 1 {
+							_, err := w.Write([]byte("some-partial-"))
+							Expect(err).NotTo(HaveOccurred())
+
+							return io.ErrUnexpectedEOF
+						}
+
+						_, err := w.Write([]byte("some-partial-contents"))
+						Expect(err).NotTo(HaveOccurred())
+
+						return nil
+					}
+
+					fakeClient.DownloadProductFileFromOffsetStub = func(w io.Writer, s string, r int, p int, offset int64) (bool, error) {
+						_, err := w.Write([]byte("contents"))
+						Expect(err).NotTo(HaveOccurred())
+
+						return true, nil
+					}
+				})
+
+				It("resumes the download from the end of the partial file", func() {
+					filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(1))
+					Expect(fakeClient.DownloadProductFileFromOffsetCallCount()).To(Equal(1))
+
+					_, _, _, _, invokedOffset := fakeClient.DownloadProductFileFromOffsetArgsForCall(0)
+					Expect(invokedOffset).To(Equal(int64(len("some-partial-"))))
+
+					contents, err := ioutil.ReadFile(filepaths[0])
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal("some-partial-contents"))
+				})
+
+				It("calculates checksums by reading back the resumed file", func() {
+					fakeFileSummer.SumFileReturns(checksum.Checksums{MD5: "some-resumed-md5"}, nil)
+
+					filepaths, checksums, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeFileSummer.SumFileCallCount()).To(Equal(1))
+					Expect(fakeFileSummer.SumFileArgsForCall(0)).To(Equal(filepaths[0]))
+
+					Expect(checksums[filepaths[0]].MD5).To(Equal("some-resumed-md5"))
+				})
+
+				Context("when the server does not honour the range request", func() {
+					BeforeEach(func() {
+						fakeClient.DownloadProductFileFromOffsetStub = nil
+						fakeClient.DownloadProductFileFromOffsetReturns(false, nil)
+					})
+
+					It("truncates the file and restarts the download", func() {
+						filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(2))
+						Expect(fakeClient.DownloadProductFileFromOffsetCallCount()).To(Equal(1))
+
+						contents, err := ioutil.ReadFile(filepaths[0])
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(contents)).To(Equal("some-partial-contents"))
+					})
+
+					It("calculates checksums of the restarted download while downloading", func() {
+						filepaths, checksums, err := d.Download(productFiles, productSlug, releaseID)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeFileSummer.SumFileCallCount()).To(Equal(0))
+						Expect(checksums[filepaths[0]].MD5).To(Equal("7e9562e179823dafa37c083b18c3667b"))
+					})
+				})
+
+				Context("when resuming the download returns an error", func() {
+					var (
+						expectedErr error
+					)
+
+					BeforeEach(func() {
+						expectedErr = errors.New("resume error")
+
+						fakeClient.DownloadProductFileFromOffsetStub = nil
+						fakeClient.DownloadProductFileFromOffsetReturns(false, expectedErr)
+					})
+
+					It("returns the error", func() {
+						_, _, err := d.Download(productFiles, productSlug, releaseID)
+						Expect(err).To(Equal(expectedErr))
+					})
+				})
+			})
+
 			Context("when the pivnet client returns other errors", func() {
 				var (
 					expectedErr error
@@ -203,7 +605,7 @@ var _ = Describe("Downloader", func() {
 				})
 
 				It("raises an error", func() {
-					_, err := d.Download(productFiles, productSlug, releaseID)
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
 
 					Expect(err).Should(HaveOccurred())
 					Expect(err).To(Equal(expectedErr))
@@ -218,7 +620,7 @@ var _ = Describe("Downloader", func() {
 			})
 
 			It("creates the directory", func() {
-				_, err := d.Download(productFiles, productSlug, releaseID)
+				_, _, err := d.Download(productFiles, productSlug, releaseID)
 				Expect(err).NotTo(HaveOccurred())
 
 				_, err = os.Open(dir)
@@ -231,7 +633,7 @@ var _ = Describe("Downloader", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := d.Download(productFiles, productSlug, releaseID)
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -243,7 +645,7 @@ var _ = Describe("Downloader", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := d.Download(productFiles, productSlug, releaseID)
+				_, _, err := d.Download(productFiles, productSlug, releaseID)
 				Expect(err).To(HaveOccurred())
 			})
 		})
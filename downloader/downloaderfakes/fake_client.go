@@ -18,6 +18,33 @@ type FakeClient struct {
 	downloadProductFileReturns struct {
 		result1 error
 	}
+	DownloadProductFileFromOffsetStub        func(writer io.Writer, productSlug string, releaseID int, productFileID int, offset int64) (bool, error)
+	downloadProductFileFromOffsetMutex       sync.RWMutex
+	downloadProductFileFromOffsetArgsForCall []struct {
+		writer        io.Writer
+		productSlug   string
+		releaseID     int
+		productFileID int
+		offset        int64
+	}
+	downloadProductFileFromOffsetReturns struct {
+		result1 bool
+		result2 error
+	}
+	DownloadProductFileRangeStub        func(writer io.Writer, productSlug string, releaseID int, productFileID int, start int64, end int64) (bool, error)
+	downloadProductFileRangeMutex       sync.RWMutex
+	downloadProductFileRangeArgsForCall []struct {
+		writer        io.Writer
+		productSlug   string
+		releaseID     int
+		productFileID int
+		start         int64
+		end           int64
+	}
+	downloadProductFileRangeReturns struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -58,11 +85,92 @@ func (fake *FakeClient) DownloadProductFileReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) DownloadProductFileFromOffset(writer io.Writer, productSlug string, releaseID int, productFileID int, offset int64) (bool, error) {
+	fake.downloadProductFileFromOffsetMutex.Lock()
+	fake.downloadProductFileFromOffsetArgsForCall = append(fake.downloadProductFileFromOffsetArgsForCall, struct {
+		writer        io.Writer
+		productSlug   string
+		releaseID     int
+		productFileID int
+		offset        int64
+	}{writer, productSlug, releaseID, productFileID, offset})
+	fake.recordInvocation("DownloadProductFileFromOffset", []interface{}{writer, productSlug, releaseID, productFileID, offset})
+	fake.downloadProductFileFromOffsetMutex.Unlock()
+	if fake.DownloadProductFileFromOffsetStub != nil {
+		return fake.DownloadProductFileFromOffsetStub(writer, productSlug, releaseID, productFileID, offset)
+	} else {
+		return fake.downloadProductFileFromOffsetReturns.result1, fake.downloadProductFileFromOffsetReturns.result2
+	}
+}
+
+func (fake *FakeClient) DownloadProductFileFromOffsetCallCount() int {
+	fake.downloadProductFileFromOffsetMutex.RLock()
+	defer fake.downloadProductFileFromOffsetMutex.RUnlock()
+	return len(fake.downloadProductFileFromOffsetArgsForCall)
+}
+
+func (fake *FakeClient) DownloadProductFileFromOffsetArgsForCall(i int) (io.Writer, string, int, int, int64) {
+	fake.downloadProductFileFromOffsetMutex.RLock()
+	defer fake.downloadProductFileFromOffsetMutex.RUnlock()
+	return fake.downloadProductFileFromOffsetArgsForCall[i].writer, fake.downloadProductFileFromOffsetArgsForCall[i].productSlug, fake.downloadProductFileFromOffsetArgsForCall[i].releaseID, fake.downloadProductFileFromOffsetArgsForCall[i].productFileID, fake.downloadProductFileFromOffsetArgsForCall[i].offset
+}
+
+func (fake *FakeClient) DownloadProductFileFromOffsetReturns(result1 bool, result2 error) {
+	fake.DownloadProductFileFromOffsetStub = nil
+	fake.downloadProductFileFromOffsetReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DownloadProductFileRange(writer io.Writer, productSlug string, releaseID int, productFileID int, start int64, end int64) (bool, error) {
+	fake.downloadProductFileRangeMutex.Lock()
+	fake.downloadProductFileRangeArgsForCall = append(fake.downloadProductFileRangeArgsForCall, struct {
+		writer        io.Writer
+		productSlug   string
+		releaseID     int
+		productFileID int
+		start         int64
+		end           int64
+	}{writer, productSlug, releaseID, productFileID, start, end})
+	fake.recordInvocation("DownloadProductFileRange", []interface{}{writer, productSlug, releaseID, productFileID, start, end})
+	fake.downloadProductFileRangeMutex.Unlock()
+	if fake.DownloadProductFileRangeStub != nil {
+		return fake.DownloadProductFileRangeStub(writer, productSlug, releaseID, productFileID, start, end)
+	} else {
+		return fake.downloadProductFileRangeReturns.result1, fake.downloadProductFileRangeReturns.result2
+	}
+}
+
+func (fake *FakeClient) DownloadProductFileRangeCallCount() int {
+	fake.downloadProductFileRangeMutex.RLock()
+	defer fake.downloadProductFileRangeMutex.RUnlock()
+	return len(fake.downloadProductFileRangeArgsForCall)
+}
+
+func (fake *FakeClient) DownloadProductFileRangeArgsForCall(i int) (io.Writer, string, int, int, int64, int64) {
+	fake.downloadProductFileRangeMutex.RLock()
+	defer fake.downloadProductFileRangeMutex.RUnlock()
+	return fake.downloadProductFileRangeArgsForCall[i].writer, fake.downloadProductFileRangeArgsForCall[i].productSlug, fake.downloadProductFileRangeArgsForCall[i].releaseID, fake.downloadProductFileRangeArgsForCall[i].productFileID, fake.downloadProductFileRangeArgsForCall[i].start, fake.downloadProductFileRangeArgsForCall[i].end
+}
+
+func (fake *FakeClient) DownloadProductFileRangeReturns(result1 bool, result2 error) {
+	fake.DownloadProductFileRangeStub = nil
+	fake.downloadProductFileRangeReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.downloadProductFileMutex.RLock()
 	defer fake.downloadProductFileMutex.RUnlock()
+	fake.downloadProductFileFromOffsetMutex.RLock()
+	defer fake.downloadProductFileFromOffsetMutex.RUnlock()
+	fake.downloadProductFileRangeMutex.RLock()
+	defer fake.downloadProductFileRangeMutex.RUnlock()
 	return fake.invocations
 }
 
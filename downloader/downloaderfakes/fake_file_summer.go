@@ -1,23 +1,27 @@
 // This file was generated by counterfeiter
-package infakes
+package downloaderfakes
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/pivotal-cf/pivnet-resource/checksum"
+)
 
 type FakeFileSummer struct {
-	SumFileStub        func(filepath string) (string, error)
+	SumFileStub        func(filepath string) (checksum.Checksums, error)
 	sumFileMutex       sync.RWMutex
 	sumFileArgsForCall []struct {
 		filepath string
 	}
 	sumFileReturns struct {
-		result1 string
+		result1 checksum.Checksums
 		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeFileSummer) SumFile(filepath string) (string, error) {
+func (fake *FakeFileSummer) SumFile(filepath string) (checksum.Checksums, error) {
 	fake.sumFileMutex.Lock()
 	fake.sumFileArgsForCall = append(fake.sumFileArgsForCall, struct {
 		filepath string
@@ -43,10 +47,10 @@ func (fake *FakeFileSummer) SumFileArgsForCall(i int) string {
 	return fake.sumFileArgsForCall[i].filepath
 }
 
-func (fake *FakeFileSummer) SumFileReturns(result1 string, result2 error) {
+func (fake *FakeFileSummer) SumFileReturns(result1 checksum.Checksums, result2 error) {
 	fake.SumFileStub = nil
 	fake.sumFileReturns = struct {
-		result1 string
+		result1 checksum.Checksums
 		result2 error
 	}{result1, result2}
 }
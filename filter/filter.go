@@ -5,18 +5,29 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	blangsemver "github.com/blang/semver"
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/semver"
 )
 
+//go:generate counterfeiter --fake-name FakeSemverConverter . semverConverter
+type semverConverter interface {
+	ToValidSemver(string) (blangsemver.Version, error)
+}
+
 type Filter struct {
-	l logger.Logger
+	l               logger.Logger
+	semverConverter semverConverter
 }
 
-func NewFilter(l logger.Logger) *Filter {
+func NewFilter(l logger.Logger, semverConverter semverConverter) *Filter {
 	return &Filter{
-		l: l,
+		l:               l,
+		semverConverter: semverConverter,
 	}
 }
 
@@ -32,6 +43,102 @@ func (f Filter) ReleasesByReleaseType(releases []pivnet.Release, releaseType piv
 	return filteredReleases, nil
 }
 
+// ReleasesByReleaseTypes returns all releases with any of the provided
+// release types.
+func (f Filter) ReleasesByReleaseTypes(releases []pivnet.Release, releaseTypes []pivnet.ReleaseType) ([]pivnet.Release, error) {
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		if containsReleaseType(releaseTypes, release.ReleaseType) {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
+// ReleasesExcludingReleaseTypes returns all releases with none of the
+// provided release types.
+func (f Filter) ReleasesExcludingReleaseTypes(releases []pivnet.Release, releaseTypes []pivnet.ReleaseType) ([]pivnet.Release, error) {
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		if !containsReleaseType(releaseTypes, release.ReleaseType) {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
+func containsReleaseType(releaseTypes []pivnet.ReleaseType, releaseType pivnet.ReleaseType) bool {
+	for _, r := range releaseTypes {
+		if r == releaseType {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleasesByAvailability returns all releases with the provided availability
+// e.g. "All Users".
+func (f Filter) ReleasesByAvailability(releases []pivnet.Release, availability string) ([]pivnet.Release, error) {
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		if release.Availability == availability {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
+// ReleasesSupportedAt returns all releases which have not reached their end of
+// support date at the provided time. Releases without an end of support date
+// are returned. Releases whose end of support date cannot be parsed are
+// logged and not returned.
+func (f Filter) ReleasesSupportedAt(releases []pivnet.Release, at time.Time) ([]pivnet.Release, error) {
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		if release.EndOfSupportDate == "" {
+			filteredReleases = append(filteredReleases, release)
+			continue
+		}
+
+		endOfSupport, err := time.Parse(concourse.ReleaseDateFormat, release.EndOfSupportDate)
+		if err != nil {
+			f.l.Info(fmt.Sprintf(
+				"failed to parse end of support date: '%s' for release: '%s'",
+				release.EndOfSupportDate,
+				release.Version,
+			))
+			continue
+		}
+
+		// Releases are supported until the end of their end of support date.
+		if at.Before(endOfSupport.AddDate(0, 0, 1)) {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
+// ReleasesNotControlled returns all releases which are not export controlled.
+func (f Filter) ReleasesNotControlled(releases []pivnet.Release) ([]pivnet.Release, error) {
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		if !release.Controlled {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
 // ReleasesByVersion returns all releases that match the provided version regex
 func (f Filter) ReleasesByVersion(releases []pivnet.Release, version string) ([]pivnet.Release, error) {
 	filteredReleases := make([]pivnet.Release, 0)
@@ -50,6 +157,61 @@ func (f Filter) ReleasesByVersion(releases []pivnet.Release, version string) ([]
 	return filteredReleases, nil
 }
 
+// ReleasesByVersionConstraint returns all releases whose version satisfies the
+// provided semver constraint e.g. ">=1.9.3 <2.0.0".
+// Releases whose version cannot be parsed as semver are not returned.
+func (f Filter) ReleasesByVersionConstraint(releases []pivnet.Release, constraint string) ([]pivnet.Release, error) {
+	r, err := semver.ParseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		v, err := f.semverConverter.ToValidSemver(release.Version)
+		if err != nil {
+			continue
+		}
+
+		if r(v) {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
+// ReleasesReleasedAfter returns all releases with a release date strictly
+// after the provided date, which must be in the format YYYY-MM-DD.
+// Releases whose release date cannot be parsed are logged and not returned.
+func (f Filter) ReleasesReleasedAfter(releases []pivnet.Release, date string) ([]pivnet.Release, error) {
+	after, err := time.Parse(concourse.ReleaseDateFormat, date)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		releaseDate, err := time.Parse(concourse.ReleaseDateFormat, release.ReleaseDate)
+		if err != nil {
+			f.l.Info(fmt.Sprintf(
+				"failed to parse release date: '%s' for release: '%s'",
+				release.ReleaseDate,
+				release.Version,
+			))
+			continue
+		}
+
+		if releaseDate.After(after) {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
 func (f Filter) ProductFileKeysByGlobs(
 	productFiles []pivnet.ProductFile,
 	globs []string,
@@ -1,12 +1,17 @@
 package filter_test
 
 import (
+	"errors"
 	"log"
+	"time"
 
+	blangsemver "github.com/blang/semver"
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
 	"github.com/pivotal-cf/pivnet-resource/filter"
+	"github.com/pivotal-cf/pivnet-resource/filter/filterfakes"
+	"github.com/pivotal-cf/pivnet-resource/semver"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -17,33 +22,13 @@ var _ = Describe("Filter", func() {
 		fakeLogger logger.Logger
 
 		f *filter.Filter
-
-		releases []pivnet.Release
 	)
 
 	BeforeEach(func() {
 		logger := log.New(GinkgoWriter, "", log.LstdFlags)
 		fakeLogger = logshim.NewLogShim(logger, logger, true)
 
-		f = filter.NewFilter(fakeLogger)
-
-		releases = []pivnet.Release{
-			{
-				ID:          1,
-				Version:     "version1",
-				ReleaseType: pivnet.ReleaseType("foo"),
-			},
-			{
-				ID:          2,
-				Version:     "version2",
-				ReleaseType: pivnet.ReleaseType("bar"),
-			},
-			{
-				ID:          3,
-				Version:     "version3",
-				ReleaseType: pivnet.ReleaseType("foo"),
-			},
-		}
+		f = filter.NewFilter(fakeLogger, semver.NewSemverConverter(fakeLogger))
 	})
 
 	Describe("ReleasesByReleaseType", func() {
@@ -100,6 +85,123 @@ var _ = Describe("Filter", func() {
 		})
 	})
 
+	Describe("ReleasesByReleaseTypes", func() {
+		var (
+			releases []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			releases = []pivnet.Release{
+				{ID: 1, ReleaseType: pivnet.ReleaseType("Major Release")},
+				{ID: 2, ReleaseType: pivnet.ReleaseType("Minor Release")},
+				{ID: 3, ReleaseType: pivnet.ReleaseType("Alpha Release")},
+			}
+		})
+
+		It("returns releases with any of the release types", func() {
+			filteredReleases, err := f.ReleasesByReleaseTypes(releases, []pivnet.ReleaseType{
+				"Major Release",
+				"Minor Release",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{releases[0], releases[1]}))
+		})
+
+		Context("when the input releases are nil", func() {
+			It("returns empty slice without error", func() {
+				filteredReleases, err := f.ReleasesByReleaseTypes(nil, []pivnet.ReleaseType{"Major Release"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filteredReleases).NotTo(BeNil())
+				Expect(filteredReleases).To(HaveLen(0))
+			})
+		})
+	})
+
+	Describe("ReleasesExcludingReleaseTypes", func() {
+		var (
+			releases []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			releases = []pivnet.Release{
+				{ID: 1, ReleaseType: pivnet.ReleaseType("Major Release")},
+				{ID: 2, ReleaseType: pivnet.ReleaseType("Developer Release")},
+				{ID: 3, ReleaseType: pivnet.ReleaseType("Alpha Release")},
+			}
+		})
+
+		It("returns releases with none of the release types", func() {
+			filteredReleases, err := f.ReleasesExcludingReleaseTypes(releases, []pivnet.ReleaseType{
+				"Alpha Release",
+				"Developer Release",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{releases[0]}))
+		})
+	})
+
+	Describe("ReleasesByAvailability", func() {
+		It("returns releases with the availability", func() {
+			releases := []pivnet.Release{
+				{ID: 1, Availability: "All Users"},
+				{ID: 2, Availability: "Admins Only"},
+				{ID: 3, Availability: "Selected User Groups Only"},
+			}
+
+			filteredReleases, err := f.ReleasesByAvailability(releases, "All Users")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{releases[0]}))
+		})
+	})
+
+	Describe("ReleasesSupportedAt", func() {
+		var (
+			at       time.Time
+			releases []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			at = time.Date(2016, 10, 4, 12, 0, 0, 0, time.UTC)
+
+			releases = []pivnet.Release{
+				{ID: 1, EndOfSupportDate: "2016-10-03"},
+				{ID: 2, EndOfSupportDate: "2016-10-04"},
+				{ID: 3, EndOfSupportDate: "2017-01-01"},
+				{ID: 4},
+				{ID: 5, EndOfSupportDate: "not-a-date"},
+			}
+		})
+
+		It("returns releases which have not reached end of support", func() {
+			filteredReleases, err := f.ReleasesSupportedAt(releases, at)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{
+				releases[1],
+				releases[2],
+				releases[3],
+			}))
+		})
+	})
+
+	Describe("ReleasesNotControlled", func() {
+		It("returns releases which are not controlled", func() {
+			releases := []pivnet.Release{
+				{ID: 1, Controlled: true},
+				{ID: 2},
+			}
+
+			filteredReleases, err := f.ReleasesNotControlled(releases)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{releases[1]}))
+		})
+	})
+
 	Describe("ReleasesByVersion", func() {
 		var (
 			version  string
@@ -208,6 +310,156 @@ var _ = Describe("Filter", func() {
 		})
 	})
 
+	Describe("ReleasesByVersionConstraint", func() {
+		var (
+			constraint string
+			releases   []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			constraint = ">=1.9.3 <2.0.0"
+
+			releases = []pivnet.Release{
+				{
+					ID:      1,
+					Version: "1.9.2",
+				},
+				{
+					ID:      2,
+					Version: "1.9.3",
+				},
+				{
+					ID:      3,
+					Version: "1.9.10",
+				},
+				{
+					ID:      4,
+					Version: "2.0.0",
+				},
+				{
+					ID:      5,
+					Version: "Build 2016-10-04",
+				},
+			}
+		})
+
+		It("returns releases whose version satisfies the constraint", func() {
+			filteredReleases, err := f.ReleasesByVersionConstraint(releases, constraint)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{
+				releases[1],
+				releases[2],
+			}))
+		})
+
+		Context("when the input releases are nil", func() {
+			BeforeEach(func() {
+				releases = nil
+			})
+
+			It("returns empty slice without error", func() {
+				filteredReleases, err := f.ReleasesByVersionConstraint(releases, constraint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filteredReleases).NotTo(BeNil())
+				Expect(filteredReleases).To(HaveLen(0))
+			})
+		})
+
+		Context("when the constraint is invalid", func() {
+			BeforeEach(func() {
+				constraint = "not-a-constraint"
+			})
+
+			It("returns an error", func() {
+				_, err := f.ReleasesByVersionConstraint(releases, constraint)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("when a version cannot be converted to semver", func() {
+			var (
+				fakeSemverConverter *filterfakes.FakeSemverConverter
+			)
+
+			BeforeEach(func() {
+				fakeSemverConverter = &filterfakes.FakeSemverConverter{}
+				fakeSemverConverter.ToValidSemverStub = func(input string) (blangsemver.Version, error) {
+					if input == "1.9.3" {
+						return blangsemver.Version{}, errors.New("not semver")
+					}
+					return blangsemver.Parse(input)
+				}
+
+				releases = releases[1:4]
+
+				f = filter.NewFilter(fakeLogger, fakeSemverConverter)
+			})
+
+			It("does not return the release", func() {
+				filteredReleases, err := f.ReleasesByVersionConstraint(releases, constraint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filteredReleases).To(Equal([]pivnet.Release{
+					releases[1],
+				}))
+
+				Expect(fakeSemverConverter.ToValidSemverCallCount()).To(Equal(len(releases)))
+			})
+		})
+	})
+
+	Describe("ReleasesReleasedAfter", func() {
+		var (
+			date     string
+			releases []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			date = "2016-10-04"
+
+			releases = []pivnet.Release{
+				{
+					ID:          1,
+					ReleaseDate: "2016-10-03",
+				},
+				{
+					ID:          2,
+					ReleaseDate: "2016-10-04",
+				},
+				{
+					ID:          3,
+					ReleaseDate: "2016-10-05",
+				},
+				{
+					ID:          4,
+					ReleaseDate: "not-a-date",
+				},
+			}
+		})
+
+		It("returns releases released after the provided date", func() {
+			filteredReleases, err := f.ReleasesReleasedAfter(releases, date)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{
+				releases[2],
+			}))
+		})
+
+		Context("when the date is invalid", func() {
+			BeforeEach(func() {
+				date = "04/10/2016"
+			})
+
+			It("returns an error", func() {
+				_, err := f.ReleasesReleasedAfter(releases, date)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
 	Describe("ProductFileKeysByGlobs", func() {
 		var (
 			productFiles []pivnet.ProductFile
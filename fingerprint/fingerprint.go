@@ -0,0 +1,89 @@
+package fingerprint
+
+import (
+	"crypto/md5"
+	"fmt"
+	"sort"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+//go:generate counterfeiter --fake-name FakeClient . client
+type client interface {
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+	ProductFileForRelease(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, error)
+}
+
+type Fingerprinter struct {
+	client client
+	mode   concourse.FingerprintMode
+	logger logger.Logger
+}
+
+func NewFingerprinter(client client, mode concourse.FingerprintMode, logger logger.Logger) *Fingerprinter {
+	return &Fingerprinter{
+		client: client,
+		mode:   mode,
+		logger: logger,
+	}
+}
+
+// Fingerprint returns the fingerprint of the release for the configured mode.
+// An empty fingerprint means the release version alone identifies it.
+//
+// The default mode, updated_at, changes whenever the release is edited on
+// Pivotal Network. The files mode only changes when the MD5s of the product
+// files of the release change, regardless of order.
+func (f Fingerprinter) Fingerprint(productSlug string, release pivnet.Release) (string, error) {
+	switch f.mode {
+	case "", concourse.FingerprintModeUpdatedAt:
+		return release.UpdatedAt, nil
+	case concourse.FingerprintModeNone:
+		return "", nil
+	case concourse.FingerprintModeFiles:
+		return f.productFilesFingerprint(productSlug, release)
+	}
+
+	return "", fmt.Errorf(
+		"provided fingerprint mode: '%s' must be one of: ['%s', '%s', '%s']",
+		f.mode,
+		concourse.FingerprintModeUpdatedAt,
+		concourse.FingerprintModeNone,
+		concourse.FingerprintModeFiles,
+	)
+}
+
+func (f Fingerprinter) productFilesFingerprint(productSlug string, release pivnet.Release) (string, error) {
+	f.logger.Info(fmt.Sprintf(
+		"Fingerprinting product files for release: '%s'",
+		release.Version,
+	))
+
+	productFiles, err := f.client.ProductFilesForRelease(productSlug, release.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(productFiles) == 0 {
+		return "", nil
+	}
+
+	// The MD5 of a product file is not included when listing the product
+	// files of a release, so each product file must be fetched individually.
+	md5s := make([]string, len(productFiles))
+	for i, p := range productFiles {
+		productFile, err := f.client.ProductFileForRelease(productSlug, release.ID, p.ID)
+		if err != nil {
+			return "", err
+		}
+
+		md5s[i] = productFile.MD5
+	}
+
+	sort.Strings(md5s)
+
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(md5s, ",")))), nil
+}
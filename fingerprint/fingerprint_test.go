@@ -0,0 +1,167 @@
+package fingerprint_test
+
+import (
+	"errors"
+	"log"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/fingerprint"
+	"github.com/pivotal-cf/pivnet-resource/fingerprint/fingerprintfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fingerprinter", func() {
+	var (
+		fakeLogger logger.Logger
+		fakeClient *fingerprintfakes.FakeClient
+
+		mode    concourse.FingerprintMode
+		release pivnet.Release
+
+		productFiles map[int]pivnet.ProductFile
+
+		fingerprinter *fingerprint.Fingerprinter
+	)
+
+	BeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		fakeClient = &fingerprintfakes.FakeClient{}
+
+		mode = ""
+		release = pivnet.Release{
+			ID:        1234,
+			Version:   "1.2.3",
+			UpdatedAt: "some-updated-at",
+		}
+
+		productFiles = map[int]pivnet.ProductFile{
+			1: {ID: 1, MD5: "some-md5"},
+			2: {ID: 2, MD5: "some-other-md5"},
+		}
+
+		fakeClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{{ID: 1}, {ID: 2}}, nil)
+		fakeClient.ProductFileForReleaseStub = func(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, error) {
+			return productFiles[productFileID], nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		fingerprinter = fingerprint.NewFingerprinter(fakeClient, mode, fakeLogger)
+	})
+
+	It("uses the updated at time by default", func() {
+		f, err := fingerprinter.Fingerprint("some-product-slug", release)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(f).To(Equal("some-updated-at"))
+		Expect(fakeClient.ProductFilesForReleaseCallCount()).To(Equal(0))
+	})
+
+	Context("when the mode is none", func() {
+		BeforeEach(func() {
+			mode = concourse.FingerprintModeNone
+		})
+
+		It("returns an empty fingerprint", func() {
+			f, err := fingerprinter.Fingerprint("some-product-slug", release)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(f).To(BeEmpty())
+		})
+	})
+
+	Context("when the mode is files", func() {
+		var (
+			original string
+		)
+
+		BeforeEach(func() {
+			mode = concourse.FingerprintModeFiles
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			original, err = fingerprinter.Fingerprint("some-product-slug", release)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("fingerprints the product file MD5s", func() {
+			Expect(original).NotTo(BeEmpty())
+			Expect(original).NotTo(Equal(release.UpdatedAt))
+
+			productSlug, releaseID := fakeClient.ProductFilesForReleaseArgsForCall(0)
+			Expect(productSlug).To(Equal("some-product-slug"))
+			Expect(releaseID).To(Equal(release.ID))
+
+			Expect(fakeClient.ProductFileForReleaseCallCount()).To(Equal(2))
+		})
+
+		It("does not change when only the release is updated", func() {
+			release.UpdatedAt = "some-later-updated-at"
+
+			f, err := fingerprinter.Fingerprint("some-product-slug", release)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(f).To(Equal(original))
+		})
+
+		It("does not change when the product files are reordered", func() {
+			fakeClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{{ID: 2}, {ID: 1}}, nil)
+
+			f, err := fingerprinter.Fingerprint("some-product-slug", release)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(f).To(Equal(original))
+		})
+
+		It("changes when a product file MD5 changes", func() {
+			productFiles[2] = pivnet.ProductFile{ID: 2, MD5: "some-new-md5"}
+
+			f, err := fingerprinter.Fingerprint("some-product-slug", release)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(f).NotTo(Equal(original))
+		})
+
+		Context("when getting product files returns an error", func() {
+			It("forwards the error", func() {
+				expectedErr := errors.New("product files error")
+				fakeClient.ProductFilesForReleaseReturns(nil, expectedErr)
+
+				_, err := fingerprinter.Fingerprint("some-product-slug", release)
+				Expect(err).To(Equal(expectedErr))
+			})
+		})
+
+		Context("when getting a product file returns an error", func() {
+			It("forwards the error", func() {
+				expectedErr := errors.New("product file error")
+				fakeClient.ProductFileForReleaseStub = nil
+				fakeClient.ProductFileForReleaseReturns(pivnet.ProductFile{}, expectedErr)
+
+				_, err := fingerprinter.Fingerprint("some-product-slug", release)
+				Expect(err).To(Equal(expectedErr))
+			})
+		})
+	})
+
+	Context("when the mode is not supported", func() {
+		BeforeEach(func() {
+			mode = "some-mode"
+		})
+
+		It("returns an error", func() {
+			_, err := fingerprinter.Fingerprint("some-product-slug", release)
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(ContainSubstring("some-mode"))
+		})
+	})
+})
@@ -0,0 +1,127 @@
+// This file was generated by counterfeiter
+package fingerprintfakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+type FakeClient struct {
+	ProductFilesForReleaseStub        func(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error)
+	productFilesForReleaseMutex       sync.RWMutex
+	productFilesForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	productFilesForReleaseReturns struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}
+	ProductFileForReleaseStub        func(productSlug string, releaseID int, productFileID int) (go_pivnet.ProductFile, error)
+	productFileForReleaseMutex       sync.RWMutex
+	productFileForReleaseArgsForCall []struct {
+		productSlug   string
+		releaseID     int
+		productFileID int
+	}
+	productFileForReleaseReturns struct {
+		result1 go_pivnet.ProductFile
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeClient) ProductFilesForRelease(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error) {
+	fake.productFilesForReleaseMutex.Lock()
+	fake.productFilesForReleaseArgsForCall = append(fake.productFilesForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ProductFilesForRelease", []interface{}{productSlug, releaseID})
+	fake.productFilesForReleaseMutex.Unlock()
+	if fake.ProductFilesForReleaseStub != nil {
+		return fake.ProductFilesForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.productFilesForReleaseReturns.result1, fake.productFilesForReleaseReturns.result2
+	}
+}
+
+func (fake *FakeClient) ProductFilesForReleaseCallCount() int {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return len(fake.productFilesForReleaseArgsForCall)
+}
+
+func (fake *FakeClient) ProductFilesForReleaseArgsForCall(i int) (string, int) {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return fake.productFilesForReleaseArgsForCall[i].productSlug, fake.productFilesForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *FakeClient) ProductFilesForReleaseReturns(result1 []go_pivnet.ProductFile, result2 error) {
+	fake.ProductFilesForReleaseStub = nil
+	fake.productFilesForReleaseReturns = struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ProductFileForRelease(productSlug string, releaseID int, productFileID int) (go_pivnet.ProductFile, error) {
+	fake.productFileForReleaseMutex.Lock()
+	fake.productFileForReleaseArgsForCall = append(fake.productFileForReleaseArgsForCall, struct {
+		productSlug   string
+		releaseID     int
+		productFileID int
+	}{productSlug, releaseID, productFileID})
+	fake.recordInvocation("ProductFileForRelease", []interface{}{productSlug, releaseID, productFileID})
+	fake.productFileForReleaseMutex.Unlock()
+	if fake.ProductFileForReleaseStub != nil {
+		return fake.ProductFileForReleaseStub(productSlug, releaseID, productFileID)
+	} else {
+		return fake.productFileForReleaseReturns.result1, fake.productFileForReleaseReturns.result2
+	}
+}
+
+func (fake *FakeClient) ProductFileForReleaseCallCount() int {
+	fake.productFileForReleaseMutex.RLock()
+	defer fake.productFileForReleaseMutex.RUnlock()
+	return len(fake.productFileForReleaseArgsForCall)
+}
+
+func (fake *FakeClient) ProductFileForReleaseArgsForCall(i int) (string, int, int) {
+	fake.productFileForReleaseMutex.RLock()
+	defer fake.productFileForReleaseMutex.RUnlock()
+	return fake.productFileForReleaseArgsForCall[i].productSlug, fake.productFileForReleaseArgsForCall[i].releaseID, fake.productFileForReleaseArgsForCall[i].productFileID
+}
+
+func (fake *FakeClient) ProductFileForReleaseReturns(result1 go_pivnet.ProductFile, result2 error) {
+	fake.ProductFileForReleaseStub = nil
+	fake.productFileForReleaseReturns = struct {
+		result1 go_pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	fake.productFileForReleaseMutex.RLock()
+	defer fake.productFileForReleaseMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,80 @@
+package gp
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ResponseCache stores API responses on disk along with the validators
+// (ETag and Last-Modified) needed to make conditional requests for them.
+// Entries are keyed by the host, a hash of the API token and the endpoint, so
+// responses are never shared between accounts.
+type ResponseCache struct {
+	dir string
+}
+
+type cacheEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+func NewResponseCache(dir string) *ResponseCache {
+	return &ResponseCache{
+		dir: dir,
+	}
+}
+
+func (c ResponseCache) get(key string) (cacheEntry, bool) {
+	b, err := ioutil.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	err = json.Unmarshal(b, &entry)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (c ResponseCache) put(key string, entry cacheEntry) error {
+	err := os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it so that concurrent checks never
+	// read a partially-written entry.
+	f, err := ioutil.TempFile(c.dir, key+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), filepath.Join(c.dir, key))
+}
+
+func cacheKey(host string, token string, endpoint string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s\n%x\n%s", host, tokenHash, endpoint))))
+}
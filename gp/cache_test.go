@@ -0,0 +1,186 @@
+package gp_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Client with a response cache", func() {
+	var (
+		server     *ghttp.Server
+		fakeLogger logger.Logger
+
+		cacheDir string
+		token    string
+
+		releasesResponse pivnet.ReleasesResponse
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		var err error
+		cacheDir, err = ioutil.TempDir("", "pivnet-resource-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		token = "some-token"
+
+		releasesResponse = pivnet.ReleasesResponse{
+			Releases: []pivnet.Release{
+				{ID: 1234, Version: "1.2.3"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+
+		err := os.RemoveAll(cacheDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	newClient := func() *gp.Client {
+		return gp.NewClientWithOptions(
+			pivnet.ClientConfig{Host: server.URL(), Token: token},
+			gp.ClientOptions{Cache: gp.NewResponseCache(cacheDir)},
+			fakeLogger,
+		)
+	}
+
+	It("revalidates cached responses with conditional requests", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/some-product/releases"),
+				ghttp.VerifyHeaderKV("Authorization", "Token some-token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse, http.Header{
+					"ETag":          []string{`"some-etag"`},
+					"Last-Modified": []string{"Tue, 04 Oct 2016 17:30:00 GMT"},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/some-product/releases"),
+				ghttp.VerifyHeaderKV("If-None-Match", `"some-etag"`),
+				ghttp.VerifyHeaderKV("If-Modified-Since", "Tue, 04 Oct 2016 17:30:00 GMT"),
+				ghttp.RespondWith(http.StatusNotModified, nil),
+			),
+		)
+
+		releases, err := newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releases).To(Equal(releasesResponse.Releases))
+
+		releases, err = newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releases).To(Equal(releasesResponse.Releases))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("replaces cached responses which have changed", func() {
+		updatedResponse := pivnet.ReleasesResponse{
+			Releases: []pivnet.Release{
+				{ID: 2345, Version: "2.3.4"},
+			},
+		}
+
+		server.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse, http.Header{
+				"ETag": []string{`"some-etag"`},
+			}),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, updatedResponse, http.Header{
+				"ETag": []string{`"some-new-etag"`},
+			}),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("If-None-Match", `"some-new-etag"`),
+				ghttp.RespondWith(http.StatusNotModified, nil),
+			),
+		)
+
+		_, err := newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+
+		releases, err := newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releases).To(Equal(updatedResponse.Releases))
+
+		releases, err = newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releases).To(Equal(updatedResponse.Releases))
+	})
+
+	It("does not share cached responses between tokens", func() {
+		server.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse, http.Header{
+				"ETag": []string{`"some-etag"`},
+			}),
+			ghttp.CombineHandlers(
+				func(w http.ResponseWriter, req *http.Request) {
+					Expect(req.Header.Get("If-None-Match")).To(BeEmpty())
+				},
+				ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse),
+			),
+		)
+
+		_, err := newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+
+		token = "some-other-token"
+
+		_, err = newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("caches release types", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/releases/release_types"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleaseTypesResponse{
+					ReleaseTypes: []pivnet.ReleaseType{"Major Release"},
+				}, http.Header{
+					"ETag": []string{`"some-etag"`},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("If-None-Match", `"some-etag"`),
+				ghttp.RespondWith(http.StatusNotModified, nil),
+			),
+		)
+
+		_, err := newClient().ReleaseTypes()
+		Expect(err).NotTo(HaveOccurred())
+
+		releaseTypes, err := newClient().ReleaseTypes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releaseTypes).To(Equal([]pivnet.ReleaseType{"Major Release"}))
+	})
+
+	Context("when the response is an error", func() {
+		It("returns the pivnet error", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusUnauthorized, map[string]string{
+					"message": "some auth error",
+				}),
+			)
+
+			_, err := newClient().ReleasesForProductSlug("some-product")
+			Expect(err).To(Equal(pivnet.ErrUnauthorized{
+				ResponseCode: http.StatusUnauthorized,
+				Message:      "some auth error",
+			}))
+		})
+	})
+})
@@ -1,34 +1,282 @@
 package gp
 
 import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 )
 
 type Client struct {
-	client pivnet.Client
+	client     pivnet.Client
+	httpClient *http.Client
+	config     pivnet.ClientConfig
+	cache      *ResponseCache
+	retry      retryer
+	logger     logger.Logger
+}
+
+// ClientOptions configures the optional behaviour of the client.
+type ClientOptions struct {
+	// Cache stores the responses for release types and releases, which are
+	// then revalidated with conditional requests. A nil cache disables
+	// caching.
+	Cache *ResponseCache
+
+	// Retry controls how requests which fail with transient errors are
+	// retried.
+	Retry RetryConfig
 }
 
 func NewClient(config pivnet.ClientConfig, logger logger.Logger) *Client {
+	return NewClientWithOptions(config, ClientOptions{}, logger)
+}
+
+func NewClientWithOptions(config pivnet.ClientConfig, options ClientOptions, logger logger.Logger) *Client {
+	// Requests are created by the pivnet client, for its authentication and
+	// base URL, but sent through a transport of our own so that responses
+	// which should be retried are recognised regardless of how their body
+	// would be decoded.
+	httpClient := &http.Client{
+		Transport: retryableStatusTransport{
+			next: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: config.SkipSSLValidation},
+			},
+		},
+	}
+
 	return &Client{
-		client: pivnet.NewClient(config, logger),
+		client:     pivnet.NewClient(config, logger),
+		httpClient: httpClient,
+		config:     config,
+		cache:      options.Cache,
+		retry: retryer{
+			config: options.Retry.withDefaults(),
+			logger: logger,
+		},
+		logger: logger,
 	}
 }
 
 func (c Client) ReleaseTypes() ([]pivnet.ReleaseType, error) {
-	return c.client.ReleaseTypes.Get()
+	var releaseTypes []pivnet.ReleaseType
+	err := c.retry.do("getting release types", func() (err error) {
+		releaseTypes, err = c.releaseTypes()
+		return err
+	})
+	return releaseTypes, err
+}
+
+func (c Client) releaseTypes() ([]pivnet.ReleaseType, error) {
+	var response pivnet.ReleaseTypesResponse
+	err := c.get("/releases/release_types", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.ReleaseTypes, nil
 }
 
 func (c Client) ReleasesForProductSlug(productSlug string) ([]pivnet.Release, error) {
-	return c.client.Releases.List(productSlug)
+	var releases []pivnet.Release
+	err := c.retry.do("listing releases", func() (err error) {
+		releases, err = c.releasesForProductSlug(productSlug)
+		return err
+	})
+	return releases, err
+}
+
+func (c Client) releasesForProductSlug(productSlug string) ([]pivnet.Release, error) {
+	var response pivnet.ReleasesResponse
+	err := c.get(fmt.Sprintf("/products/%s/releases", productSlug), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Releases, nil
+}
+
+func (c Client) getRelease(productSlug string, releaseID int) (pivnet.Release, error) {
+	var release pivnet.Release
+	err := c.retry.do("getting release", func() (err error) {
+		release, err = c.getReleaseOnce(productSlug, releaseID)
+		return err
+	})
+	return release, err
+}
+
+func (c Client) getReleaseOnce(productSlug string, releaseID int) (pivnet.Release, error) {
+	var release pivnet.Release
+	err := c.get(fmt.Sprintf("/products/%s/releases/%d", productSlug, releaseID), &release)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	return release, nil
+}
+
+// get decodes the response for the endpoint into v, using the cache if there
+// is one.
+func (c Client) get(endpoint string, v interface{}) error {
+	if c.cache == nil {
+		return c.request("GET", endpoint, http.StatusOK, nil, v)
+	}
+
+	return c.cachedGet(endpoint, v)
+}
+
+// request sends a request to the endpoint, with the body encoded as JSON if
+// there is one, and decodes the response into v if it is provided. Responses
+// other than the expected status code are returned as the same errors the
+// pivnet client returns.
+func (c Client) request(
+	method string,
+	endpoint string,
+	expectedStatusCode int,
+	body interface{},
+	v interface{},
+) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := c.client.CreateRequest(method, endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		return responseError(resp)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// cachedGet decodes the response for the endpoint into v. If a response is
+// cached, it is revalidated with If-None-Match and If-Modified-Since and only
+// fetched again if it has changed. Failing to write to the cache is logged
+// rather than returned, as the response itself is still valid.
+func (c Client) cachedGet(endpoint string, v interface{}) error {
+	key := cacheKey(c.config.Host, c.config.Token, endpoint)
+
+	req, err := c.client.CreateRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	entry, cached := c.cache.get(key)
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		c.logger.Debug(fmt.Sprintf("Using cached response for: '%s'", endpoint))
+		return json.Unmarshal(entry.Body, v)
+	case resp.StatusCode != http.StatusOK:
+		return responseError(resp)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return err
+	}
+
+	entry = cacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Body:         body,
+	}
+
+	if entry.ETag != "" || entry.LastModified != "" {
+		err = c.cache.put(key, entry)
+		if err != nil {
+			c.logger.Info(fmt.Sprintf("Failed to cache response for: '%s': %s", endpoint, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// responseError converts an unexpected response into the same errors the
+// pivnet client returns.
+func responseError(resp *http.Response) error {
+	var pErr struct {
+		Message string   `json:"message"`
+		Errors  []string `json:"errors"`
+
+		// Internal server errors have an error rather than a message.
+		Error string `json:"error"`
+	}
+
+	// The body is only used to improve the error message, so a body that
+	// cannot be decoded is ignored.
+	_ = json.NewDecoder(resp.Body).Decode(&pErr)
+
+	if pErr.Message == "" {
+		pErr.Message = pErr.Error
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return pivnet.ErrUnauthorized{ResponseCode: resp.StatusCode, Message: pErr.Message}
+	case http.StatusNotFound:
+		return pivnet.ErrNotFound{ResponseCode: resp.StatusCode, Message: pErr.Message}
+	case http.StatusUnavailableForLegalReasons:
+		return pivnet.ErrUnavailableForLegalReasons{
+			ResponseCode: resp.StatusCode,
+			Message:      "The EULA has not been accepted.",
+		}
+	default:
+		return pivnet.ErrPivnetOther{
+			ResponseCode: resp.StatusCode,
+			Message:      pErr.Message,
+			Errors:       pErr.Errors,
+		}
+	}
 }
 
 func (c Client) GetRelease(productSlug string, version string) (pivnet.Release, error) {
-	releases, err := c.client.Releases.List(productSlug)
+	releases, err := c.ReleasesForProductSlug(productSlug)
 	if err != nil {
 		return pivnet.Release{}, err
 	}
@@ -45,7 +293,7 @@ func (c Client) GetRelease(productSlug string, version string) (pivnet.Release,
 		return pivnet.Release{}, fmt.Errorf("release not found")
 	}
 
-	release, err := c.client.Releases.Get(productSlug, foundRelease.ID)
+	release, err := c.getRelease(productSlug, foundRelease.ID)
 	if err != nil {
 		return pivnet.Release{}, err
 	}
@@ -53,87 +301,781 @@ func (c Client) GetRelease(productSlug string, version string) (pivnet.Release,
 }
 
 func (c Client) UpdateRelease(productSlug string, release pivnet.Release) (pivnet.Release, error) {
-	return c.client.Releases.Update(productSlug, release)
+	var updated pivnet.Release
+	err := c.retry.do("updating release", func() (err error) {
+		updated, err = c.updateRelease(productSlug, release)
+		return err
+	})
+	return updated, err
+}
+
+func (c Client) updateRelease(productSlug string, release pivnet.Release) (pivnet.Release, error) {
+	release.OSSCompliant = "confirm"
+
+	var response pivnet.CreateReleaseResponse
+	err := c.request(
+		"PATCH",
+		fmt.Sprintf("/products/%s/releases/%d", productSlug, release.ID),
+		http.StatusOK,
+		releaseBody{Release: release},
+		&response,
+	)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	return response.Release, nil
+}
+
+type releaseBody struct {
+	Release pivnet.Release `json:"release"`
 }
 
+// UpdateReleaseMetadata updates the release like UpdateRelease, but always
+// sends the attributes which may be cleared, so that empty values in the
+// release clear them rather than being omitted.
+func (c Client) UpdateReleaseMetadata(productSlug string, release pivnet.Release) (pivnet.Release, error) {
+	var updated pivnet.Release
+	err := c.retry.do("updating release", func() (err error) {
+		updated, err = c.updateReleaseMetadata(productSlug, release)
+		return err
+	})
+	return updated, err
+}
+
+func (c Client) updateReleaseMetadata(productSlug string, release pivnet.Release) (pivnet.Release, error) {
+	release.OSSCompliant = "confirm"
+
+	var response pivnet.CreateReleaseResponse
+	err := c.request(
+		"PATCH",
+		fmt.Sprintf("/products/%s/releases/%d", productSlug, release.ID),
+		http.StatusOK,
+		releaseMetadataBody{Release: releaseMetadata{
+			Release:               release,
+			Description:           release.Description,
+			ReleaseNotesURL:       release.ReleaseNotesURL,
+			Controlled:            release.Controlled,
+			ECCN:                  release.ECCN,
+			LicenseException:      release.LicenseException,
+			EndOfSupportDate:      release.EndOfSupportDate,
+			EndOfGuidanceDate:     release.EndOfGuidanceDate,
+			EndOfAvailabilityDate: release.EndOfAvailabilityDate,
+		}},
+		&response,
+	)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	return response.Release, nil
+}
+
+type releaseMetadataBody struct {
+	Release releaseMetadata `json:"release"`
+}
+
+// releaseMetadata shadows the attributes of the release which may be cleared,
+// as pivnet.Release omits them when empty.
+type releaseMetadata struct {
+	pivnet.Release
+	Description           string `json:"description"`
+	ReleaseNotesURL       string `json:"release_notes_url"`
+	Controlled            bool   `json:"controlled"`
+	ECCN                  string `json:"eccn"`
+	LicenseException      string `json:"license_exception"`
+	EndOfSupportDate      string `json:"end_of_support_date"`
+	EndOfGuidanceDate     string `json:"end_of_guidance_date"`
+	EndOfAvailabilityDate string `json:"end_of_availability_date"`
+}
+
+// CreateRelease is only retried once it has been verified that the failed
+// attempt did not create the release, as versions are unique for a product.
 func (c Client) CreateRelease(config pivnet.CreateReleaseConfig) (pivnet.Release, error) {
-	return c.client.Releases.Create(config)
+	var release pivnet.Release
+	err := c.retry.doVerified("creating release", func() (err error) {
+		release, err = c.createRelease(config)
+		return err
+	}, func() (bool, error) {
+		releases, err := c.releasesForProductSlug(config.ProductSlug)
+		if err != nil {
+			return false, err
+		}
+
+		for _, r := range releases {
+			if r.Version == config.Version {
+				release = r
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return release, err
+}
+
+func (c Client) createRelease(config pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+	release := pivnet.Release{
+		Availability: "Admins Only",
+		EULA: &pivnet.EULA{
+			Slug: config.EULASlug,
+		},
+		OSSCompliant:          "confirm",
+		ReleaseDate:           config.ReleaseDate,
+		ReleaseType:           pivnet.ReleaseType(config.ReleaseType),
+		Version:               config.Version,
+		Description:           config.Description,
+		ReleaseNotesURL:       config.ReleaseNotesURL,
+		Controlled:            config.Controlled,
+		ECCN:                  config.ECCN,
+		LicenseException:      config.LicenseException,
+		EndOfSupportDate:      config.EndOfSupportDate,
+		EndOfGuidanceDate:     config.EndOfGuidanceDate,
+		EndOfAvailabilityDate: config.EndOfAvailabilityDate,
+	}
+
+	if release.ReleaseDate == "" {
+		release.ReleaseDate = time.Now().Format("2006-01-02")
+		c.logger.Info(fmt.Sprintf(
+			"No release date found - using default release date: '%s'",
+			release.ReleaseDate,
+		))
+	}
+
+	var response pivnet.CreateReleaseResponse
+	err := c.request(
+		"POST",
+		fmt.Sprintf("/products/%s/releases", config.ProductSlug),
+		http.StatusCreated,
+		releaseBody{Release: release},
+		&response,
+	)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	return response.Release, nil
 }
 
+// DeleteRelease treats the release not being found on a retry as success, as
+// the failed attempt must have deleted it.
 func (c Client) DeleteRelease(productSlug string, release pivnet.Release) error {
-	return c.client.Releases.Delete(productSlug, release)
+	attempt := 0
+	return c.retry.do("deleting release", func() error {
+		attempt++
+		err := c.request(
+			"DELETE",
+			fmt.Sprintf("/products/%s/releases/%d", productSlug, release.ID),
+			http.StatusNoContent,
+			nil,
+			nil,
+		)
+		if _, ok := err.(pivnet.ErrNotFound); ok && attempt > 1 {
+			return nil
+		}
+		return err
+	})
 }
 
 func (c Client) AddUserGroup(productSlug string, releaseID int, userGroupID int) error {
-	return c.client.UserGroups.AddToRelease(productSlug, releaseID, userGroupID)
+	return c.retry.do("adding user group", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/add_user_group", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("user_group", "id", userGroupID),
+			nil,
+		)
+	})
+}
+
+func (c Client) RemoveUserGroup(productSlug string, releaseID int, userGroupID int) error {
+	return c.retry.do("removing user group", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/remove_user_group", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("user_group", "id", userGroupID),
+			nil,
+		)
+	})
 }
 
 func (c Client) UserGroups(productSlug string, releaseID int) ([]pivnet.UserGroup, error) {
-	return c.client.UserGroups.ListForRelease(productSlug, releaseID)
+	var response pivnet.UserGroupsResponse
+	err := c.retry.do("listing user groups", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/releases/%d/user_groups", productSlug, releaseID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.UserGroups, err
 }
 
 func (c Client) AcceptEULA(productSlug string, releaseID int) error {
-	return c.client.EULA.Accept(productSlug, releaseID)
+	return c.retry.do("accepting EULA", func() error {
+		return c.request(
+			"POST",
+			fmt.Sprintf("/products/%s/releases/%d/eula_acceptance", productSlug, releaseID),
+			http.StatusOK,
+			struct{}{},
+			nil,
+		)
+	})
 }
 
 func (c Client) EULAs() ([]pivnet.EULA, error) {
-	return c.client.EULA.List()
+	var response pivnet.EULAsResponse
+	err := c.retry.do("listing EULAs", func() error {
+		return c.request("GET", "/eulas", http.StatusOK, nil, &response)
+	})
+	return response.EULAs, err
 }
 
 func (c Client) FindProductForSlug(slug string) (pivnet.Product, error) {
-	return c.client.Products.Get(slug)
+	var product pivnet.Product
+	err := c.retry.do("getting product", func() error {
+		return c.request("GET", fmt.Sprintf("/products/%s", slug), http.StatusOK, nil, &product)
+	})
+	return product, err
 }
 
 func (c Client) ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error) {
-	return c.client.ProductFiles.ListForRelease(productSlug, releaseID)
+	var response pivnet.ProductFilesResponse
+	err := c.retry.do("listing product files", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/releases/%d/product_files", productSlug, releaseID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.ProductFiles, err
 }
 
 func (c Client) ProductFiles(productSlug string) ([]pivnet.ProductFile, error) {
-	return c.client.ProductFiles.List(productSlug)
+	var productFiles []pivnet.ProductFile
+	err := c.retry.do("listing product files", func() (err error) {
+		productFiles, err = c.productFiles(productSlug)
+		return err
+	})
+	return productFiles, err
+}
+
+func (c Client) productFiles(productSlug string) ([]pivnet.ProductFile, error) {
+	var response pivnet.ProductFilesResponse
+	err := c.request(
+		"GET",
+		fmt.Sprintf("/products/%s/product_files", productSlug),
+		http.StatusOK,
+		nil,
+		&response,
+	)
+	return response.ProductFiles, err
 }
 
 func (c Client) ProductFile(productSlug string, productFileID int) (pivnet.ProductFile, error) {
-	return c.client.ProductFiles.Get(productSlug, productFileID)
+	var response pivnet.ProductFileResponse
+	err := c.retry.do("getting product file", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/product_files/%d", productSlug, productFileID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.ProductFile, err
 }
 
 func (c Client) ProductFileForRelease(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, error) {
-	return c.client.ProductFiles.GetForRelease(productSlug, releaseID, productFileID)
+	var response pivnet.ProductFileResponse
+	err := c.retry.do("getting product file", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/releases/%d/product_files/%d", productSlug, releaseID, productFileID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.ProductFile, err
 }
 
-func (c Client) DeleteProductFile(productSlug string, releaseID int) (pivnet.ProductFile, error) {
-	return c.client.ProductFiles.Delete(productSlug, releaseID)
+// ProductFileForReleaseWithSHA256 returns the product file along with the
+// SHA-256 checksum pivnet holds for it, which is an empty string if pivnet
+// does not supply one.
+func (c Client) ProductFileForReleaseWithSHA256(
+	productSlug string,
+	releaseID int,
+	productFileID int,
+) (pivnet.ProductFile, string, error) {
+	var response struct {
+		ProductFile struct {
+			pivnet.ProductFile
+			SHA256 string `json:"sha256"`
+		} `json:"product_file"`
+	}
+
+	err := c.retry.do("getting product file", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/releases/%d/product_files/%d", productSlug, releaseID, productFileID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	if err != nil {
+		return pivnet.ProductFile{}, "", err
+	}
+
+	return response.ProductFile.ProductFile, response.ProductFile.SHA256, nil
 }
 
+// DeleteProductFile treats the product file not being found on a retry as
+// success, as the failed attempt must have deleted it.
+func (c Client) DeleteProductFile(productSlug string, productFileID int) (pivnet.ProductFile, error) {
+	var response pivnet.ProductFileResponse
+	attempt := 0
+	err := c.retry.do("deleting product file", func() error {
+		attempt++
+		err := c.request(
+			"DELETE",
+			fmt.Sprintf("/products/%s/product_files/%d", productSlug, productFileID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+		if _, ok := err.(pivnet.ErrNotFound); ok && attempt > 1 {
+			return nil
+		}
+		return err
+	})
+	return response.ProductFile, err
+}
+
+// CreateProductFile is only retried once it has been verified that the failed
+// attempt did not create a product file for the same AWS object key.
 func (c Client) CreateProductFile(config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
-	return c.client.ProductFiles.Create(config)
+	var productFile pivnet.ProductFile
+	err := c.retry.doVerified("creating product file", func() (err error) {
+		productFile, err = c.createProductFile(config)
+		return err
+	}, func() (bool, error) {
+		productFiles, err := c.productFiles(config.ProductSlug)
+		if err != nil {
+			return false, err
+		}
+
+		for _, pf := range productFiles {
+			if pf.AWSObjectKey == config.AWSObjectKey {
+				productFile = pf
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return productFile, err
+}
+
+func (c Client) createProductFile(config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+	if config.AWSObjectKey == "" {
+		return pivnet.ProductFile{}, fmt.Errorf("AWS object key must not be empty")
+	}
+
+	body := productFileBody{
+		ProductFile: pivnet.ProductFile{
+			AWSObjectKey:       config.AWSObjectKey,
+			Description:        config.Description,
+			DocsURL:            config.DocsURL,
+			FileType:           config.FileType,
+			FileVersion:        config.FileVersion,
+			IncludedFiles:      config.IncludedFiles,
+			MD5:                config.MD5,
+			Name:               config.Name,
+			Platforms:          config.Platforms,
+			ReleasedAt:         config.ReleasedAt,
+			SystemRequirements: config.SystemRequirements,
+		},
+	}
+
+	var response pivnet.ProductFileResponse
+	err := c.request(
+		"POST",
+		fmt.Sprintf("/products/%s/product_files", config.ProductSlug),
+		http.StatusCreated,
+		body,
+		&response,
+	)
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+
+	return response.ProductFile, nil
+}
+
+type productFileBody struct {
+	ProductFile pivnet.ProductFile `json:"product_file"`
+}
+
+func (c Client) RemoveProductFile(productSlug string, releaseID int, productFileID int) error {
+	return c.retry.do("removing product file", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/remove_product_file", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("product_file", "id", productFileID),
+			nil,
+		)
+	})
 }
 
 func (c Client) AddProductFile(productSlug string, releaseID int, productFileID int) error {
-	return c.client.ProductFiles.AddToRelease(productSlug, releaseID, productFileID)
+	return c.retry.do("adding product file", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/add_product_file", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("product_file", "id", productFileID),
+			nil,
+		)
+	})
 }
 
 func (c Client) DownloadProductFile(writer io.Writer, productSlug string, releaseID int, productFileID int) error {
-	return c.client.ProductFiles.DownloadForRelease(writer, productSlug, releaseID, productFileID)
+	pf, err := c.ProductFileForRelease(productSlug, releaseID, productFileID)
+	if err != nil {
+		return err
+	}
+
+	downloadLink, err := pf.DownloadLink()
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.download(downloadLink, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	_, err = io.Copy(writer, resp.Body)
+	return err
+}
+
+// DownloadProductFileFromOffset downloads the product file starting at the
+// provided byte offset using an HTTP Range request. If the server does not
+// honour the range, nothing is written to the writer and false is returned.
+func (c Client) DownloadProductFileFromOffset(
+	writer io.Writer,
+	productSlug string,
+	releaseID int,
+	productFileID int,
+	offset int64,
+) (bool, error) {
+	return c.downloadProductFileRange(
+		writer,
+		productSlug,
+		releaseID,
+		productFileID,
+		fmt.Sprintf("%d-", offset),
+	)
+}
+
+// DownloadProductFileRange downloads the inclusive byte range from start to
+// end of the product file. If the server does not honour the range, nothing
+// is written to the writer and false is returned.
+func (c Client) DownloadProductFileRange(
+	writer io.Writer,
+	productSlug string,
+	releaseID int,
+	productFileID int,
+	start int64,
+	end int64,
+) (bool, error) {
+	return c.downloadProductFileRange(
+		writer,
+		productSlug,
+		releaseID,
+		productFileID,
+		fmt.Sprintf("%d-%d", start, end),
+	)
+}
+
+func (c Client) downloadProductFileRange(
+	writer io.Writer,
+	productSlug string,
+	releaseID int,
+	productFileID int,
+	byteRange string,
+) (bool, error) {
+	pf, err := c.ProductFileForRelease(productSlug, releaseID, productFileID)
+	if err != nil {
+		return false, err
+	}
+
+	downloadLink, err := pf.DownloadLink()
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.download(downloadLink, fmt.Sprintf("bytes=%s", byteRange))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// Servers are permitted to return a different range to the one
+		// requested, so ensure we received exactly what was asked for.
+		expectedContentRange := fmt.Sprintf("bytes %s", byteRange)
+		if !strings.HasSuffix(byteRange, "-") {
+			expectedContentRange = expectedContentRange + "/"
+		}
+
+		contentRange := resp.Header.Get("Content-Range")
+		if !strings.HasPrefix(contentRange, expectedContentRange) {
+			return false, nil
+		}
+	case http.StatusOK:
+		return false, nil
+	case http.StatusUnavailableForLegalReasons:
+		return false, pivnet.ErrUnavailableForLegalReasons{
+			ResponseCode: resp.StatusCode,
+			Message:      "The EULA has not been accepted.",
+		}
+	default:
+		return false, fmt.Errorf(
+			"unexpected status code downloading product file: %d",
+			resp.StatusCode,
+		)
+	}
+
+	_, err = io.Copy(writer, resp.Body)
+	return true, err
+}
+
+// download requests the download link, with the Range header if it is not
+// empty. Only the request is retried, as nothing has been written until the
+// response arrives. Failures while reading the body are left to the caller,
+// which can resume the download.
+func (c Client) download(downloadLink string, byteRange string) (*http.Response, error) {
+	var resp *http.Response
+	err := c.retry.do("downloading product file", func() error {
+		req, err := c.client.CreateRequest("POST", downloadLink, nil)
+		if err != nil {
+			return err
+		}
+
+		if byteRange != "" {
+			req.Header.Set("Range", byteRange)
+		}
+
+		resp, err = c.httpClient.Do(req)
+		return err
+	})
+	return resp, err
 }
 
 func (c Client) FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error) {
-	return c.client.FileGroups.ListForRelease(productSlug, releaseID)
+	var response pivnet.FileGroupsResponse
+	err := c.retry.do("listing file groups", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/releases/%d/file_groups", productSlug, releaseID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.FileGroups, err
+}
+
+func (c Client) FileGroups(productSlug string) ([]pivnet.FileGroup, error) {
+	var response pivnet.FileGroupsResponse
+	err := c.retry.do("listing file groups", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/file_groups", productSlug),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.FileGroups, err
+}
+
+// CreateFileGroup is never retried, as file group names are not unique so
+// there is no way to tell whether a failed attempt created the file group.
+func (c Client) CreateFileGroup(productSlug string, name string) (pivnet.FileGroup, error) {
+	var fileGroup pivnet.FileGroup
+	err := c.request(
+		"POST",
+		fmt.Sprintf("/products/%s/file_groups", productSlug),
+		http.StatusCreated,
+		map[string]map[string]string{"file_group": {"name": name}},
+		&fileGroup,
+	)
+	return fileGroup, unwrapRetryAfter(err)
+}
+
+// DeleteFileGroup treats the file group not being found on a retry as
+// success, as the failed attempt must have deleted it.
+func (c Client) DeleteFileGroup(productSlug string, fileGroupID int) error {
+	attempt := 0
+	return c.retry.do("deleting file group", func() error {
+		attempt++
+		err := c.request(
+			"DELETE",
+			fmt.Sprintf("/products/%s/file_groups/%d", productSlug, fileGroupID),
+			http.StatusOK,
+			nil,
+			nil,
+		)
+		if _, ok := err.(pivnet.ErrNotFound); ok && attempt > 1 {
+			return nil
+		}
+		return err
+	})
+}
+
+func (c Client) AddFileGroup(productSlug string, releaseID int, fileGroupID int) error {
+	return c.retry.do("adding file group", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/add_file_group", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("file_group", "id", fileGroupID),
+			nil,
+		)
+	})
+}
+
+func (c Client) RemoveFileGroup(productSlug string, releaseID int, fileGroupID int) error {
+	return c.retry.do("removing file group", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/remove_file_group", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("file_group", "id", fileGroupID),
+			nil,
+		)
+	})
+}
+
+func (c Client) AddToFileGroup(productSlug string, fileGroupID int, productFileID int) error {
+	return c.retry.do("adding product file to file group", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/file_groups/%d/add_product_file", productSlug, fileGroupID),
+			http.StatusNoContent,
+			idBody("product_file", "id", productFileID),
+			nil,
+		)
+	})
+}
+
+func (c Client) RemoveFromFileGroup(productSlug string, fileGroupID int, productFileID int) error {
+	return c.retry.do("removing product file from file group", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/file_groups/%d/remove_product_file", productSlug, fileGroupID),
+			http.StatusNoContent,
+			idBody("product_file", "id", productFileID),
+			nil,
+		)
+	})
 }
 
 func (c Client) ReleaseDependencies(productSlug string, releaseID int) ([]pivnet.ReleaseDependency, error) {
-	return c.client.ReleaseDependencies.List(productSlug, releaseID)
+	var response pivnet.ReleaseDependenciesResponse
+	err := c.retry.do("listing release dependencies", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/releases/%d/dependencies", productSlug, releaseID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.ReleaseDependencies, err
 }
 
 func (c Client) AddReleaseDependency(productSlug string, releaseID int, dependentReleaseID int) error {
-	return c.client.ReleaseDependencies.Add(productSlug, releaseID, dependentReleaseID)
+	return c.retry.do("adding release dependency", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/add_dependency", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("dependency", "release_id", dependentReleaseID),
+			nil,
+		)
+	})
+}
+
+func (c Client) RemoveReleaseDependency(productSlug string, releaseID int, dependentReleaseID int) error {
+	return c.retry.do("removing release dependency", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/remove_dependency", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("dependency", "release_id", dependentReleaseID),
+			nil,
+		)
+	})
 }
 
 func (c Client) ReleaseUpgradePaths(productSlug string, releaseID int) ([]pivnet.ReleaseUpgradePath, error) {
-	return c.client.ReleaseUpgradePaths.Get(productSlug, releaseID)
+	var response pivnet.ReleaseUpgradePathsResponse
+	err := c.retry.do("listing release upgrade paths", func() error {
+		return c.request(
+			"GET",
+			fmt.Sprintf("/products/%s/releases/%d/upgrade_paths", productSlug, releaseID),
+			http.StatusOK,
+			nil,
+			&response,
+		)
+	})
+	return response.ReleaseUpgradePaths, err
 }
 
 func (c Client) AddReleaseUpgradePath(productSlug string, releaseID int, previousReleaseID int) error {
-	return c.client.ReleaseUpgradePaths.Add(productSlug, releaseID, previousReleaseID)
+	return c.retry.do("adding release upgrade path", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/add_upgrade_path", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("upgrade_path", "release_id", previousReleaseID),
+			nil,
+		)
+	})
+}
+
+func (c Client) RemoveReleaseUpgradePath(productSlug string, releaseID int, previousReleaseID int) error {
+	return c.retry.do("removing release upgrade path", func() error {
+		return c.request(
+			"PATCH",
+			fmt.Sprintf("/products/%s/releases/%d/remove_upgrade_path", productSlug, releaseID),
+			http.StatusNoContent,
+			idBody("upgrade_path", "release_id", previousReleaseID),
+			nil,
+		)
+	})
+}
+
+// idBody is the body of requests which add or remove a resource by its ID, such
+// as {"product_file": {"id": 1234}}.
+func idBody(resource string, field string, id int) map[string]map[string]int {
+	return map[string]map[string]int{resource: {field: id}}
 }
 
 func (c Client) CreateRequest(method string, url string, body io.Reader) (*http.Request, error) {
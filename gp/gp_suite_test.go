@@ -1,4 +1,4 @@
-package md5sum_test
+package gp_test
 
 import (
 	. "github.com/onsi/ginkgo"
@@ -7,7 +7,7 @@ import (
 	"testing"
 )
 
-func TestMD5(t *testing.T) {
+func TestGP(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "MD5 Suite")
+	RunSpecs(t, "GP Suite")
 }
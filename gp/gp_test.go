@@ -0,0 +1,117 @@
+package gp_test
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server     *ghttp.Server
+		fakeLogger logger.Logger
+
+		client *gp.Client
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		client = gp.NewClientWithOptions(
+			pivnet.ClientConfig{Host: server.URL(), Token: "some-token"},
+			gp.ClientOptions{Retry: gp.RetryConfig{Attempts: 1}},
+			fakeLogger,
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends authenticated requests with JSON bodies", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PATCH", "/api/v2/products/some-product/releases/1234/add_product_file"),
+				ghttp.VerifyHeaderKV("Authorization", "Token some-token"),
+				ghttp.VerifyJSON(`{"product_file":{"id":5678}}`),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		)
+
+		err := client.AddProductFile("some-product", 1234, 5678)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("sends the attributes which may be cleared when updating release metadata", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PATCH", "/api/v2/products/some-product/releases/1234"),
+				ghttp.VerifyJSON(`{"release":{
+					"id":1234,
+					"version":"1.2.3",
+					"oss_compliant":"confirm",
+					"description":"",
+					"release_notes_url":"",
+					"controlled":false,
+					"eccn":"",
+					"license_exception":"",
+					"end_of_support_date":"",
+					"end_of_guidance_date":"",
+					"end_of_availability_date":""
+				}}`),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.CreateReleaseResponse{
+					Release: pivnet.Release{ID: 1234, Version: "1.2.3"},
+				}),
+			),
+		)
+
+		release, err := client.UpdateReleaseMetadata("some-product", pivnet.Release{ID: 1234, Version: "1.2.3"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(release).To(Equal(pivnet.Release{ID: 1234, Version: "1.2.3"}))
+	})
+
+	It("decodes the response", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/some-product/releases/1234/product_files"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFilesResponse{
+					ProductFiles: []pivnet.ProductFile{{ID: 5678, Name: "some-file"}},
+				}),
+			),
+		)
+
+		productFiles, err := client.ProductFilesForRelease("some-product", 1234)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(productFiles).To(Equal([]pivnet.ProductFile{{ID: 5678, Name: "some-file"}}))
+	})
+
+	Context("when the response is not the expected status code", func() {
+		It("returns the same errors as the pivnet client", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusNotFound, map[string]string{"message": "not found"}),
+				ghttp.RespondWith(http.StatusUnavailableForLegalReasons, nil),
+				ghttp.RespondWithJSONEncoded(http.StatusInternalServerError, map[string]string{"error": "broken"}),
+			)
+
+			_, err := client.ProductFilesForRelease("some-product", 1234)
+			Expect(err).To(Equal(pivnet.ErrNotFound{ResponseCode: http.StatusNotFound, Message: "not found"}))
+
+			err = client.AcceptEULA("some-product", 1234)
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrUnavailableForLegalReasons{}))
+
+			_, err = client.ProductFilesForRelease("some-product", 1234)
+			Expect(err).To(Equal(pivnet.ErrPivnetOther{ResponseCode: http.StatusInternalServerError, Message: "broken"}))
+		})
+	})
+})
@@ -0,0 +1,240 @@
+package gp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+const (
+	DefaultRetryAttempts     = 5
+	DefaultRetryInitialDelay = 1 * time.Second
+	DefaultRetryMaxDelay     = 30 * time.Second
+)
+
+// RetryConfig controls how requests which fail with a transient error are
+// retried. Zero values are replaced with the defaults.
+type RetryConfig struct {
+	// Attempts is the maximum number of attempts for each request, including
+	// the first. One disables retries.
+	Attempts int
+
+	// InitialDelay is the delay before the first retry. It doubles for each
+	// subsequent retry, up to MaxDelay.
+	InitialDelay time.Duration
+
+	// MaxDelay caps both the exponential backoff and any Retry-After
+	// requested by the server.
+	MaxDelay time.Duration
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.Attempts < 1 {
+		c.Attempts = DefaultRetryAttempts
+	}
+	if c.InitialDelay <= 0 {
+		c.InitialDelay = DefaultRetryInitialDelay
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = DefaultRetryMaxDelay
+	}
+	if c.MaxDelay < c.InitialDelay {
+		c.MaxDelay = c.InitialDelay
+	}
+	return c
+}
+
+// retryAfterError is returned by the transport for responses which should be
+// retried, along with any delay the server requested. It is never returned to
+// callers of retried requests.
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+func (e retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+type retryer struct {
+	config RetryConfig
+	logger logger.Logger
+}
+
+// do calls fn until it succeeds, returns an error which is not transient, or
+// the attempts are exhausted. It must only be used for requests which are
+// safe to repeat.
+func (r retryer) do(operation string, fn func() error) error {
+	return r.doVerified(operation, fn, nil)
+}
+
+// doVerified behaves like do, except that before each retry verify is called
+// to determine whether the failed request took effect regardless, in which
+// case no further attempts are made and nil is returned. This allows requests
+// which would create duplicates to be retried safely. If verify returns an
+// error, the state cannot be known and the original error is returned.
+func (r retryer) doVerified(operation string, fn func() error, verify func() (bool, error)) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= r.config.Attempts || !retryable(err) {
+			return unwrapRetryAfter(err)
+		}
+
+		if verify != nil {
+			done, verifyErr := verify()
+			if verifyErr != nil {
+				r.logger.Info(fmt.Sprintf(
+					"Not retrying %s as its state could not be verified: %s",
+					operation,
+					verifyErr.Error(),
+				))
+				return unwrapRetryAfter(err)
+			}
+
+			if done {
+				r.logger.Info(fmt.Sprintf(
+					"%s succeeded despite error: %s",
+					operation,
+					err.Error(),
+				))
+				return nil
+			}
+		}
+
+		delay := r.delay(attempt, err)
+
+		r.logger.Info(fmt.Sprintf(
+			"Retrying %s in %s after error: %s (attempt %d of %d)",
+			operation,
+			delay,
+			err.Error(),
+			attempt+1,
+			r.config.Attempts,
+		))
+
+		time.Sleep(delay)
+	}
+}
+
+// delay returns the Retry-After requested by the server if there is one,
+// otherwise an exponential backoff with jitter so that concurrent clients do
+// not retry in lockstep.
+func (r retryer) delay(attempt int, err error) time.Duration {
+	var e retryAfterError
+	if errors.As(err, &e) && e.after > 0 {
+		if e.after > r.config.MaxDelay {
+			return r.config.MaxDelay
+		}
+		return e.after
+	}
+
+	backoff := r.config.InitialDelay
+	for i := 1; i < attempt && backoff < r.config.MaxDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > r.config.MaxDelay {
+		backoff = r.config.MaxDelay
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func retryable(err error) bool {
+	if errors.As(err, &retryAfterError{}) {
+		return true
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+func retryableStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func unwrapRetryAfter(err error) error {
+	var e retryAfterError
+	if errors.As(err, &e) {
+		return e.err
+	}
+	return err
+}
+
+// retryableStatusTransport returns an error for responses with a status code
+// which should be retried, rather than the response itself. Callers decode
+// error responses in different ways, and a response from a load balancer may
+// have a body which cannot be decoded at all, so the status code is only
+// reliably available here.
+type retryableStatusTransport struct {
+	next http.RoundTripper
+}
+
+func (t retryableStatusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !retryableStatusCode(resp.StatusCode) {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	return nil, retryAfterError{
+		err:   responseError(resp),
+		after: retryAfter(resp),
+	}
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date. It returns zero if the header is absent or invalid.
+func retryAfter(resp *http.Response) time.Duration {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,369 @@
+package gp_test
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Client retries", func() {
+	var (
+		server     *ghttp.Server
+		fakeLogger logger.Logger
+
+		options gp.ClientOptions
+
+		releasesResponse    pivnet.ReleasesResponse
+		unavailableResponse http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		options = gp.ClientOptions{
+			Retry: gp.RetryConfig{
+				Attempts:     3,
+				InitialDelay: time.Millisecond,
+				MaxDelay:     10 * time.Millisecond,
+			},
+		}
+
+		releasesResponse = pivnet.ReleasesResponse{
+			Releases: []pivnet.Release{
+				{ID: 1234, Version: "1.2.3"},
+			},
+		}
+
+		unavailableResponse = ghttp.RespondWithJSONEncoded(
+			http.StatusServiceUnavailable,
+			map[string]string{"message": "unavailable"},
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newClient := func() *gp.Client {
+		return gp.NewClientWithOptions(
+			pivnet.ClientConfig{Host: server.URL(), Token: "some-token"},
+			options,
+			fakeLogger,
+		)
+	}
+
+	It("retries requests which fail with a transient error", func() {
+		server.AppendHandlers(
+			unavailableResponse,
+			unavailableResponse,
+			ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse),
+		)
+
+		releases, err := newClient().ReleasesForProductSlug("some-product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releases).To(Equal(releasesResponse.Releases))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("returns the last error once the attempts are exhausted", func() {
+		server.AppendHandlers(
+			unavailableResponse,
+			unavailableResponse,
+			unavailableResponse,
+		)
+
+		_, err := newClient().ReleasesForProductSlug("some-product")
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(pivnet.ErrPivnetOther{}))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("does not retry requests which fail with other errors", func() {
+		server.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(http.StatusNotFound, map[string]string{"message": "not found"}),
+		)
+
+		_, err := newClient().ReleasesForProductSlug("some-product")
+		Expect(err).To(BeAssignableToTypeOf(pivnet.ErrNotFound{}))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	Context("when retries are disabled", func() {
+		BeforeEach(func() {
+			options.Retry.Attempts = 1
+		})
+
+		It("makes a single attempt", func() {
+			server.AppendHandlers(
+				unavailableResponse,
+			)
+
+			_, err := newClient().ReleasesForProductSlug("some-product")
+			Expect(err).To(HaveOccurred())
+
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Context("when the response body is not JSON", func() {
+		var (
+			htmlResponse http.HandlerFunc
+		)
+
+		BeforeEach(func() {
+			htmlResponse = ghttp.RespondWith(
+				http.StatusServiceUnavailable,
+				"<html><body>503 Service Unavailable</body></html>",
+				http.Header{"Content-Type": []string{"text/html"}},
+			)
+		})
+
+		It("retries based on the status code", func() {
+			server.AppendHandlers(
+				htmlResponse,
+				ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse),
+			)
+
+			releases, err := newClient().ReleasesForProductSlug("some-product")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(releases).To(Equal(releasesResponse.Releases))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("returns the status code once the attempts are exhausted", func() {
+			server.AppendHandlers(
+				htmlResponse,
+				htmlResponse,
+				htmlResponse,
+			)
+
+			_, err := newClient().ReleasesForProductSlug("some-product")
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrPivnetOther{}))
+			Expect(err.(pivnet.ErrPivnetOther).ResponseCode).To(Equal(http.StatusServiceUnavailable))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+	})
+
+	Context("when the response specifies Retry-After", func() {
+		var (
+			slowDownResponse http.HandlerFunc
+		)
+
+		BeforeEach(func() {
+			options.Retry.MaxDelay = 2 * time.Second
+
+			slowDownResponse = ghttp.RespondWithJSONEncoded(
+				http.StatusTooManyRequests,
+				map[string]string{"message": "slow down"},
+				http.Header{"Retry-After": []string{"1"}},
+			)
+		})
+
+		It("waits for the requested delay before retrying", func() {
+			server.AppendHandlers(
+				slowDownResponse,
+				ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse),
+			)
+
+			start := time.Now()
+
+			releases, err := newClient().ReleasesForProductSlug("some-product")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(releases).To(Equal(releasesResponse.Releases))
+
+			Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("waits for the requested delay before retrying requests which are not GETs", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", "/api/v2/products/some-product/releases/1234/add_user_group"),
+					slowDownResponse,
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", "/api/v2/products/some-product/releases/1234/add_user_group"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+
+			start := time.Now()
+
+			err := newClient().AddUserGroup("some-product", 1234, 5678)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
+
+	Describe("downloading a product file", func() {
+		var (
+			productFileResponse pivnet.ProductFileResponse
+		)
+
+		BeforeEach(func() {
+			productFileResponse = pivnet.ProductFileResponse{
+				ProductFile: pivnet.ProductFile{
+					ID: 5678,
+					Links: &pivnet.Links{
+						Download: map[string]string{
+							"href": server.URL() + "/api/v2/products/some-product/releases/1234/product_files/5678/download",
+						},
+					},
+				},
+			}
+		})
+
+		It("retries the download request", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, productFileResponse),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/products/some-product/releases/1234/product_files/5678/download"),
+					ghttp.RespondWith(http.StatusServiceUnavailable, "<html>Service Unavailable</html>"),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/products/some-product/releases/1234/product_files/5678/download"),
+					ghttp.RespondWith(http.StatusOK, "some-contents"),
+				),
+			)
+
+			var contents bytes.Buffer
+			err := newClient().DownloadProductFile(&contents, "some-product", 1234, 5678)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contents.String()).To(Equal("some-contents"))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("retries ranged download requests", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, productFileResponse),
+				ghttp.RespondWith(http.StatusTooManyRequests, nil),
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Range", "bytes=5-"),
+					ghttp.RespondWith(http.StatusPartialContent, "contents", http.Header{
+						"Content-Range": []string{"bytes 5-12/13"},
+					}),
+				),
+			)
+
+			var contents bytes.Buffer
+			resumed, err := newClient().DownloadProductFileFromOffset(&contents, "some-product", 1234, 5678, 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resumed).To(BeTrue())
+			Expect(contents.String()).To(Equal("contents"))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+	})
+
+	Describe("creating a release", func() {
+		var (
+			config pivnet.CreateReleaseConfig
+		)
+
+		BeforeEach(func() {
+			config = pivnet.CreateReleaseConfig{
+				ProductSlug: "some-product",
+				Version:     "1.2.3",
+			}
+		})
+
+		It("does not create the release again if the failed attempt created it", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/products/some-product/releases"),
+					unavailableResponse,
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/some-product/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, releasesResponse),
+				),
+			)
+
+			release, err := newClient().CreateRelease(config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(release).To(Equal(releasesResponse.Releases[0]))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("retries if the failed attempt did not create the release", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/products/some-product/releases"),
+					unavailableResponse,
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/some-product/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/products/some-product/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, pivnet.CreateReleaseResponse{
+						Release: releasesResponse.Releases[0],
+					}),
+				),
+			)
+
+			release, err := newClient().CreateRelease(config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(release).To(Equal(releasesResponse.Releases[0]))
+		})
+
+		It("does not retry if the state cannot be verified", func() {
+			server.AppendHandlers(
+				unavailableResponse,
+				unavailableResponse,
+			)
+
+			_, err := newClient().CreateRelease(config)
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrPivnetOther{}))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
+
+	Describe("deleting a release", func() {
+		It("succeeds if a retry finds the release already deleted", func() {
+			server.AppendHandlers(
+				unavailableResponse,
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/api/v2/products/some-product/releases/1234"),
+					ghttp.RespondWithJSONEncoded(http.StatusNotFound, map[string]string{"message": "not found"}),
+				),
+			)
+
+			err := newClient().DeleteRelease("some-product", releasesResponse.Releases[0])
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns not found on the first attempt", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusNotFound, map[string]string{"message": "not found"}),
+			)
+
+			err := newClient().DeleteRelease("some-product", releasesResponse.Releases[0])
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrNotFound{}))
+		})
+	})
+})
@@ -2,11 +2,14 @@ package in
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 	"github.com/pivotal-cf/pivnet-resource/versions"
@@ -22,12 +25,12 @@ type filterer interface {
 
 //go:generate counterfeiter --fake-name FakeDownloader . downloader
 type downloader interface {
-	Download(productFiles []pivnet.ProductFile, productSlug string, releaseID int) ([]string, error)
+	Download(productFiles []pivnet.ProductFile, productSlug string, releaseID int) ([]string, map[string]checksum.Checksums, error)
 }
 
-//go:generate counterfeiter --fake-name FakeFileSummer . fileSummer
-type fileSummer interface {
-	SumFile(filepath string) (string, error)
+//go:generate counterfeiter --fake-name FakeUnpacker . unpacker
+type unpacker interface {
+	Unpack(archivePath string) (string, error)
 }
 
 //go:generate counterfeiter --fake-name FakeFileWriter . fileWriter
@@ -37,25 +40,31 @@ type fileWriter interface {
 	WriteVersionFile(versionWithFingerprint string) error
 }
 
+//go:generate counterfeiter --fake-name FakeFingerprinter . fingerprinter
+type fingerprinter interface {
+	Fingerprint(productSlug string, release pivnet.Release) (string, error)
+}
+
 //go:generate counterfeiter --fake-name FakePivnetClient . pivnetClient
 type pivnetClient interface {
 	GetRelease(productSlug string, version string) (pivnet.Release, error)
 	AcceptEULA(productSlug string, releaseID int) error
 	FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error)
 	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
-	ProductFileForRelease(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, error)
+	ProductFileForReleaseWithSHA256(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, string, error)
 	ReleaseDependencies(productSlug string, releaseID int) ([]pivnet.ReleaseDependency, error)
 	ReleaseUpgradePaths(productSlug string, releaseID int) ([]pivnet.ReleaseUpgradePath, error)
 }
 
 type InCommand struct {
-	logger       logger.Logger
-	downloadDir  string
-	pivnetClient pivnetClient
-	filter       filterer
-	downloader   downloader
-	fileSummer   fileSummer
-	fileWriter   fileWriter
+	logger        logger.Logger
+	downloadDir   string
+	pivnetClient  pivnetClient
+	filter        filterer
+	downloader    downloader
+	unpacker      unpacker
+	fileWriter    fileWriter
+	fingerprinter fingerprinter
 }
 
 func NewInCommand(
@@ -63,16 +72,18 @@ func NewInCommand(
 	pivnetClient pivnetClient,
 	filter filterer,
 	downloader downloader,
-	fileSummer fileSummer,
+	unpacker unpacker,
 	fileWriter fileWriter,
+	fingerprinter fingerprinter,
 ) *InCommand {
 	return &InCommand{
-		logger:       logger,
-		pivnetClient: pivnetClient,
-		filter:       filter,
-		downloader:   downloader,
-		fileSummer:   fileSummer,
-		fileWriter:   fileWriter,
+		logger:        logger,
+		pivnetClient:  pivnetClient,
+		filter:        filter,
+		downloader:    downloader,
+		unpacker:      unpacker,
+		fileWriter:    fileWriter,
+		fingerprinter: fingerprinter,
 	}
 }
 
@@ -98,22 +109,46 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 	}
 
 	if fingerprint != "" {
-		actualFingerprint := release.UpdatedAt
-		if actualFingerprint != fingerprint {
-			return concourse.InResponse{}, fmt.Errorf(
-				"provided fingerprint: '%s' does not match actual fingerprint (from pivnet): '%s' - %s",
+		actualFingerprint, err := c.fingerprinter.Fingerprint(productSlug, release)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+
+		// An empty actual fingerprint means fingerprints are not in use, so
+		// there is nothing to compare against.
+		if actualFingerprint != "" && actualFingerprint != fingerprint {
+			if !input.Params.AllowStaleFingerprint {
+				return concourse.InResponse{}, fmt.Errorf(
+					"provided fingerprint: '%s' does not match actual fingerprint (from pivnet): '%s' - %s",
+					fingerprint,
+					actualFingerprint,
+					"pivnet does not support downloading old versions of a release",
+				)
+			}
+
+			c.logger.Info(fmt.Sprintf(
+				"WARNING: provided fingerprint: '%s' does not match actual fingerprint (from pivnet): '%s' - continuing with the latest version of the release",
 				fingerprint,
 				actualFingerprint,
-				"pivnet does not support downloading old versions of a release",
-			)
+			))
 		}
 	}
 
-	c.logger.Info(fmt.Sprintf("Accepting EULA for release with ID: %d", release.ID))
+	// EULAs are accepted unless explicitly disabled.
+	acceptEULA := input.Params.AcceptEULA == nil || *input.Params.AcceptEULA
 
-	err = c.pivnetClient.AcceptEULA(productSlug, release.ID)
-	if err != nil {
-		return concourse.InResponse{}, err
+	var eulaAcceptedAt string
+	if acceptEULA {
+		c.logger.Info(fmt.Sprintf("Accepting EULA for release with ID: %d", release.ID))
+
+		err = c.pivnetClient.AcceptEULA(productSlug, release.ID)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+
+		eulaAcceptedAt = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		c.logger.Info(fmt.Sprintf("Not accepting EULA for release with ID: %d", release.ID))
 	}
 
 	c.logger.Info("Getting product files")
@@ -136,9 +171,10 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 	}
 
 	// Get individual product files to obtain metadata that isn't found
-	// in the endpoint for all product files.
+	// in the endpoint for all product files, including the SHA256.
+	pivnetSHA256s := map[int]string{}
 	for i, p := range allProductFiles {
-		allProductFiles[i], err = c.pivnetClient.ProductFileForRelease(
+		allProductFiles[i], pivnetSHA256s[p.ID], err = c.pivnetClient.ProductFileForReleaseWithSHA256(
 			productSlug,
 			release.ID,
 			p.ID,
@@ -162,11 +198,31 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 		return concourse.InResponse{}, err
 	}
 
-	c.logger.Info("Downloading files")
-
-	err = c.downloadFiles(input.Params.Globs, allProductFiles, productSlug, release.ID)
-	if err != nil {
-		return concourse.InResponse{}, err
+	var fileSHA256s map[string]string
+	if input.Params.SkipDownloads {
+		c.logger.Info("Skipping downloads")
+	} else {
+		c.logger.Info("Downloading files")
+
+		fileSHA256s, err = c.downloadFiles(
+			input.Params.Globs,
+			input.Params.Unpack,
+			allProductFiles,
+			pivnetSHA256s,
+			productSlug,
+			release.ID,
+		)
+		if _, ok := err.(pivnet.ErrUnavailableForLegalReasons); ok && !acceptEULA {
+			return concourse.InResponse{}, fmt.Errorf(
+				"%s EULA for release: '%s' must be accepted before downloading files - set %s to true or accept the EULA on Pivotal Network",
+				err.Error(),
+				release.Version,
+				"accept_eula",
+			)
+		}
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
 	}
 
 	c.logger.Info("Creating metadata")
@@ -193,6 +249,7 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 
 	if release.EULA != nil {
 		mdata.Release.EULASlug = release.EULA.Slug
+		mdata.Release.EULAAcceptedAt = eulaAcceptedAt
 	}
 
 	for _, pf := range releaseProductFiles {
@@ -210,6 +267,7 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 			FileType:     pf.FileType,
 			FileVersion:  pf.FileVersion,
 			MD5:          pf.MD5,
+			SHA256:       sha256ForProductFile(fileSHA256s, pf),
 		})
 	}
 
@@ -267,6 +325,22 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 
 	concourseMetadata := c.addReleaseMetadata([]concourse.Metadata{}, release)
 
+	deployedVersion := input.Source.DeployedVersion
+	if deployedVersion != "" {
+		if containsUpgradePathFrom(releaseUpgradePaths, deployedVersion) {
+			concourseMetadata = append(concourseMetadata, concourse.Metadata{
+				Name:  "upgrade_path",
+				Value: fmt.Sprintf("%s -> %s", deployedVersion, release.Version),
+			})
+		} else {
+			c.logger.Info(fmt.Sprintf(
+				"Release: '%s' has no upgrade path from deployed version: '%s'",
+				release.Version,
+				deployedVersion,
+			))
+		}
+	}
+
 	out := concourse.InResponse{
 		Version: concourse.Version{
 			ProductVersion: versionWithFingerprint,
@@ -277,12 +351,17 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 	return out, nil
 }
 
+// downloadFiles returns the SHA-256 of each downloaded file, keyed by file
+// name, if SHA-256 checksums were calculated. If unpack is true, archives are
+// extracted once their checksums have been verified.
 func (c InCommand) downloadFiles(
 	globs []string,
+	unpack bool,
 	productFiles []pivnet.ProductFile,
+	pivnetSHA256s map[int]string,
 	productSlug string,
 	releaseID int,
-) error {
+) (map[string]string, error) {
 	c.logger.Info("Filtering download links by glob")
 
 	filtered := productFiles
@@ -292,42 +371,78 @@ func (c InCommand) downloadFiles(
 		var err error
 		filtered, err = c.filter.ProductFileKeysByGlobs(productFiles, globs)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	c.logger.Info("Downloading filtered files")
 
-	files, err := c.downloader.Download(filtered, productSlug, releaseID)
+	files, fileChecksums, err := c.downloader.Download(filtered, productSlug, releaseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fileMD5s := map[string]string{}
+	softwareFiles := map[string]pivnet.ProductFile{}
 	for _, p := range productFiles {
-		parts := strings.Split(p.AWSObjectKey, "/")
-
-		if len(parts) < 1 {
-			panic("not enough components to form filename")
+		if p.FileType == pivnet.FileTypeSoftware {
+			softwareFiles[fileNameForProductFile(p)] = p
 		}
+	}
 
-		fileName := parts[len(parts)-1]
+	fileSHA256s, err := c.compareChecksums(files, fileChecksums, softwareFiles, pivnetSHA256s)
+	if err != nil {
+		return nil, err
+	}
 
-		if fileName == "" {
-			panic("empty file name")
-		}
+	if unpack {
+		c.logger.Info("Unpacking downloaded archives")
 
-		if p.FileType == pivnet.FileTypeSoftware {
-			fileMD5s[fileName] = p.MD5
+		for _, f := range files {
+			_, err := c.unpacker.Unpack(f)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	err = c.compareMD5s(files, fileMD5s)
-	if err != nil {
-		return err
+	return fileSHA256s, nil
+}
+
+func fileNameForProductFile(p pivnet.ProductFile) string {
+	parts := strings.Split(p.AWSObjectKey, "/")
+
+	if len(parts) < 1 {
+		panic("not enough components to form filename")
 	}
 
-	return nil
+	fileName := parts[len(parts)-1]
+
+	if fileName == "" {
+		panic("empty file name")
+	}
+
+	return fileName
+}
+
+// sha256ForProductFile returns the SHA-256 calculated for the downloaded file
+// of the product file, if it was downloaded and its SHA-256 was calculated.
+// Unlike fileNameForProductFile, it does not require the product file to
+// have an AWS object key, as files which were not downloaded may not.
+func sha256ForProductFile(fileSHA256s map[string]string, p pivnet.ProductFile) string {
+	if len(fileSHA256s) == 0 || p.AWSObjectKey == "" || strings.HasSuffix(p.AWSObjectKey, "/") {
+		return ""
+	}
+
+	return fileSHA256s[path.Base(p.AWSObjectKey)]
+}
+
+func containsUpgradePathFrom(upgradePaths []pivnet.ReleaseUpgradePath, version string) bool {
+	for _, u := range upgradePaths {
+		if u.Release.Version == version {
+			return true
+		}
+	}
+	return false
 }
 
 func (c InCommand) addReleaseMetadata(
@@ -358,31 +473,72 @@ func (c InCommand) addReleaseMetadata(
 	return cmdata
 }
 
-func (c InCommand) compareMD5s(filepaths []string, expectedMD5s map[string]string) error {
-	c.logger.Info("Calcuating MD5 for downloaded files")
+func (c InCommand) compareChecksums(
+	filepaths []string,
+	fileChecksums map[string]checksum.Checksums,
+	softwareFiles map[string]pivnet.ProductFile,
+	pivnetSHA256s map[int]string,
+) (map[string]string, error) {
+	c.logger.Info("Comparing checksums for downloaded files")
+
+	fileSHA256s := map[string]string{}
 
 	for _, downloadPath := range filepaths {
 		_, f := filepath.Split(downloadPath)
 
-		actualMD5, err := c.fileSummer.SumFile(downloadPath)
-		if err != nil {
-			return err
+		actual := fileChecksums[downloadPath]
+
+		if actual.SHA256 != "" {
+			fileSHA256s[f] = actual.SHA256
+		}
+
+		pf, ok := softwareFiles[f]
+		if !ok {
+			continue
 		}
 
-		expectedMD5 := expectedMD5s[f]
-		if expectedMD5 != "" && expectedMD5 != actualMD5 {
-			return fmt.Errorf(
+		if actual.MD5 != "" && pf.MD5 != "" && pf.MD5 != actual.MD5 {
+			return nil, fmt.Errorf(
 				"MD5 comparison failed for downloaded file: '%s'. Expected (from pivnet): '%s' - actual (from file): '%s'",
 				downloadPath,
-				expectedMD5,
-				actualMD5,
+				pf.MD5,
+				actual.MD5,
+			)
+		}
+
+		if actual.SHA256 == "" {
+			continue
+		}
+
+		expectedSHA256 := pivnetSHA256s[pf.ID]
+		if expectedSHA256 == "" {
+			if actual.MD5 == "" {
+				return nil, fmt.Errorf(
+					"No SHA256 available from pivnet for downloaded file: '%s' and no MD5 was calculated to verify it against",
+					downloadPath,
+				)
+			}
+
+			c.logger.Info(fmt.Sprintf(
+				"No SHA256 available from pivnet for downloaded file: '%s' - skipping SHA256 comparison",
+				downloadPath,
+			))
+			continue
+		}
+
+		if expectedSHA256 != actual.SHA256 {
+			return nil, fmt.Errorf(
+				"SHA256 comparison failed for downloaded file: '%s'. Expected (from pivnet): '%s' - actual (from file): '%s'",
+				downloadPath,
+				expectedSHA256,
+				actual.SHA256,
 			)
 		}
 	}
 
-	c.logger.Info("MD5 matched for all downloaded files")
+	c.logger.Info("Checksums matched for all downloaded files")
 
 	c.logger.Info("Get complete")
 
-	return nil
+	return fileSHA256s, nil
 }
@@ -3,13 +3,14 @@ package in_test
 import (
 	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
 	"github.com/pivotal-cf/pivnet-resource/in"
 	"github.com/pivotal-cf/pivnet-resource/in/infakes"
@@ -27,10 +28,12 @@ var _ = Describe("In", func() {
 
 		fakeFilter       *infakes.FakeFilter
 		fakeDownloader   *infakes.FakeDownloader
+		fakeUnpacker     *infakes.FakeUnpacker
 		fakePivnetClient *infakes.FakePivnetClient
-		fakeFileSummer   *infakes.FakeFileSummer
 		fakeFileWriter   *infakes.FakeFileWriter
 
+		fakeFingerprinter *infakes.FakeFingerprinter
+
 		fileGroups []pivnet.FileGroup
 
 		releaseProductFiles    []pivnet.ProductFile
@@ -59,13 +62,15 @@ var _ = Describe("In", func() {
 		downloadFilepaths []string
 		fileContentsMD5s  []string
 
+		fileContentsSHA256s []string
+		pivnetSHA256s       map[int]string
+
 		getReleaseErr          error
 		acceptEULAErr          error
 		productFilesErr        error
 		productFileErr         error
 		downloadErr            error
 		filterErr              error
-		md5sumErr              error
 		releaseDependenciesErr error
 		releaseUpgradePathsErr error
 		fileGroupsErr          error
@@ -74,9 +79,14 @@ var _ = Describe("In", func() {
 	BeforeEach(func() {
 		fakeFilter = &infakes.FakeFilter{}
 		fakeDownloader = &infakes.FakeDownloader{}
+		fakeUnpacker = &infakes.FakeUnpacker{}
 		fakePivnetClient = &infakes.FakePivnetClient{}
-		fakeFileSummer = &infakes.FakeFileSummer{}
 		fakeFileWriter = &infakes.FakeFileWriter{}
+		fakeFingerprinter = &infakes.FakeFingerprinter{}
+
+		fakeFingerprinter.FingerprintStub = func(productSlug string, release pivnet.Release) (string, error) {
+			return release.UpdatedAt, nil
+		}
 
 		getReleaseErr = nil
 		acceptEULAErr = nil
@@ -84,7 +94,6 @@ var _ = Describe("In", func() {
 		productFileErr = nil
 		filterErr = nil
 		downloadErr = nil
-		md5sumErr = nil
 		releaseDependenciesErr = nil
 		releaseUpgradePathsErr = nil
 		fileGroupsErr = nil
@@ -100,6 +109,9 @@ var _ = Describe("In", func() {
 			"some-md5 5678",
 		}
 
+		fileContentsSHA256s = nil
+		pivnetSHA256s = map[int]string{}
+
 		var err error
 		versionWithFingerprint, err = versions.CombineVersionAndFingerprint(version, fingerprint)
 		Expect(err).NotTo(HaveOccurred())
@@ -290,46 +302,47 @@ var _ = Describe("In", func() {
 		fakePivnetClient.ReleaseUpgradePathsReturns(releaseUpgradePaths, releaseUpgradePathsErr)
 		fakePivnetClient.FileGroupsForReleaseReturns(fileGroups, fileGroupsErr)
 
-		fakePivnetClient.ProductFileForReleaseStub = func(
+		fakePivnetClient.ProductFileForReleaseWithSHA256Stub = func(
 			productSlug string,
 			releaseID int,
 			productFileID int,
-		) (pivnet.ProductFile, error) {
+		) (pivnet.ProductFile, string, error) {
 			if productFileErr != nil {
-				return pivnet.ProductFile{}, productFileErr
+				return pivnet.ProductFile{}, "", productFileErr
 			}
 
+			sha256 := pivnetSHA256s[productFileID]
+
 			switch productFileID {
 			case releaseProductFile1.ID:
-				return releaseProductFile1, nil
+				return releaseProductFile1, sha256, nil
 			case releaseProductFile2.ID:
-				return releaseProductFile2, nil
+				return releaseProductFile2, sha256, nil
 			case fileGroup1ProductFile.ID:
-				return fileGroup1ProductFile, nil
+				return fileGroup1ProductFile, sha256, nil
 			case fileGroup2ProductFile.ID:
-				return fileGroup2ProductFile, nil
+				return fileGroup2ProductFile, sha256, nil
 			}
 
 			Fail(fmt.Sprintf("unexpected productFileID: %d", productFileID))
-			return pivnet.ProductFile{}, nil
+			return pivnet.ProductFile{}, "", nil
 		}
 
 		fakeFilter.ProductFileKeysByGlobsReturns(filteredProductFiles, filterErr)
-		fakeDownloader.DownloadReturns(downloadFilepaths, downloadErr)
-		fakeFileSummer.SumFileStub = func(path string) (string, error) {
-			if md5sumErr != nil {
-				return "", md5sumErr
+
+		fileChecksums := map[string]checksum.Checksums{}
+		for i, f := range downloadFilepaths {
+			sums := checksum.Checksums{
+				MD5: fileContentsMD5s[i],
 			}
 
-			for i, f := range downloadFilepaths {
-				if strings.HasSuffix(path, f) {
-					return fileContentsMD5s[i], nil
-				}
+			if fileContentsSHA256s != nil {
+				sums.SHA256 = fileContentsSHA256s[i]
 			}
 
-			Fail(fmt.Sprintf("unexpected path: %s", path))
-			return "", nil
+			fileChecksums[f] = sums
 		}
+		fakeDownloader.DownloadReturns(downloadFilepaths, fileChecksums, downloadErr)
 
 		logger := log.New(GinkgoWriter, "", log.LstdFlags)
 		fakeLogger = logshim.NewLogShim(logger, logger, true)
@@ -339,8 +352,9 @@ var _ = Describe("In", func() {
 			fakePivnetClient,
 			fakeFilter,
 			fakeDownloader,
-			fakeFileSummer,
+			fakeUnpacker,
 			fakeFileWriter,
+			fakeFingerprinter,
 		)
 	})
 
@@ -403,13 +417,228 @@ var _ = Describe("In", func() {
 		expectedProductFiles = append(expectedProductFiles, fileGroup1ProductFile)
 		expectedProductFiles = append(expectedProductFiles, fileGroup2ProductFile)
 
-		Expect(fakePivnetClient.ProductFileForReleaseCallCount()).To(Equal(len(expectedProductFiles)))
+		Expect(fakePivnetClient.ProductFileForReleaseWithSHA256CallCount()).To(Equal(len(expectedProductFiles)))
 
 		Expect(fakeDownloader.DownloadCallCount()).To(Equal(1))
 		invokedProductFiles, _, _ := fakeDownloader.DownloadArgsForCall(0)
 		Expect(invokedProductFiles).To(Equal(filteredProductFiles))
+	})
+
+	Context("when skip downloads is true", func() {
+		BeforeEach(func() {
+			inRequest.Params.SkipDownloads = true
+		})
+
+		It("does not download any files", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDownloader.DownloadCallCount()).To(Equal(0))
+			Expect(fakeUnpacker.UnpackCallCount()).To(Equal(0))
+		})
+
+		It("accepts the EULA", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(1))
+		})
+
+		It("writes the metadata for all product files", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFileWriter.WriteVersionFileCallCount()).To(Equal(1))
+			Expect(fakeFileWriter.WriteMetadataJSONFileCallCount()).To(Equal(1))
+			Expect(fakeFileWriter.WriteMetadataYAMLFileCallCount()).To(Equal(1))
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+
+			validateReleaseProductFilesMetadata(invokedMetadata, releaseProductFiles)
+			validateProductFilesMetadata(invokedMetadata, filteredProductFiles)
+			validateFileGroupsMetadata(invokedMetadata, fileGroups)
+			validateReleaseDependenciesMetadata(invokedMetadata, releaseDependencies)
+			validateReleaseUpgradePathsMetadata(invokedMetadata, releaseUpgradePaths)
+		})
+	})
+
+	It("does not unpack downloaded files", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeUnpacker.UnpackCallCount()).To(Equal(0))
+	})
+
+	Context("when unpack is true", func() {
+		BeforeEach(func() {
+			inRequest.Params.Unpack = true
+		})
+
+		It("unpacks each downloaded file", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeUnpacker.UnpackCallCount()).To(Equal(len(downloadFilepaths)))
+			for i, f := range downloadFilepaths {
+				Expect(fakeUnpacker.UnpackArgsForCall(i)).To(Equal(f))
+			}
+		})
+
+		Context("when the checksums do not match", func() {
+			BeforeEach(func() {
+				fileContentsMD5s[0] = "incorrect md5"
+			})
+
+			It("does not unpack any files", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(fakeUnpacker.UnpackCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when unpacking returns an error", func() {
+			var (
+				expectedErr error
+			)
+
+			BeforeEach(func() {
+				expectedErr = fmt.Errorf("some unpack error")
+				fakeUnpacker.UnpackReturns("", expectedErr)
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(Equal(expectedErr))
+			})
+		})
+	})
+
+	It("does not record SHA256 checksums", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+		for _, p := range invokedMetadata.ProductFiles {
+			Expect(p.SHA256).To(BeEmpty())
+		}
+	})
+
+	Describe("when SHA256 checksums are calculated", func() {
+		BeforeEach(func() {
+			fileContentsSHA256s = []string{
+				"some-sha256 1234",
+				"some-sha256 3456",
+				"some-sha256 4567",
+				"some-sha256 5678",
+			}
+
+			pivnetSHA256s = map[int]string{
+				1234: fileContentsSHA256s[0],
+				3456: fileContentsSHA256s[1],
+				4567: fileContentsSHA256s[2],
+				5678: fileContentsSHA256s[3],
+			}
+		})
+
+		It("compares against the SHA256 from pivnet without fetching the product files again", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.ProductFileForReleaseWithSHA256CallCount()).To(Equal(len(downloadFilepaths)))
+		})
+
+		It("includes the SHA256 when invoking metadata writers", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			yamlMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			jsonMetadata := fakeFileWriter.WriteMetadataJSONFileArgsForCall(0)
+
+			for i, sha256 := range fileContentsSHA256s {
+				Expect(yamlMetadata.ProductFiles[i].SHA256).To(Equal(sha256))
+				Expect(jsonMetadata.ProductFiles[i].SHA256).To(Equal(sha256))
+			}
+		})
+
+		Context("when a product file has no AWS object key", func() {
+			BeforeEach(func() {
+				fileGroup2ProductFile.FileType = pivnet.FileTypeDocumentation
+				fileGroup2ProductFile.AWSObjectKey = ""
+			})
+
+			It("does not record a SHA256 for it", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+				Expect(invokedMetadata.ProductFiles[3].SHA256).To(BeEmpty())
+			})
+
+			Context("when skip downloads is true", func() {
+				BeforeEach(func() {
+					inRequest.Params.SkipDownloads = true
+				})
+
+				It("does not record any SHA256", func() {
+					_, err := inCommand.Run(inRequest)
+					Expect(err).NotTo(HaveOccurred())
+
+					invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+					for _, p := range invokedMetadata.ProductFiles {
+						Expect(p.SHA256).To(BeEmpty())
+					}
+				})
+			})
+		})
+
+		Context("when pivnet does not supply a SHA256", func() {
+			BeforeEach(func() {
+				delete(pivnetSHA256s, 1234)
+			})
+
+			It("returns without error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when the MD5 was not calculated", func() {
+				BeforeEach(func() {
+					fileContentsMD5s = make([]string, len(fileContentsMD5s))
+				})
 
-		Expect(fakeFileSummer.SumFileCallCount()).To(Equal(len(downloadFilepaths)))
+				It("returns an error", func() {
+					_, err := inCommand.Run(inRequest)
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("No SHA256 available"))
+				})
+			})
+		})
+
+		Context("when the SHA256 does not match", func() {
+			BeforeEach(func() {
+				fileContentsSHA256s[0] = "incorrect sha256"
+			})
+
+			It("returns an error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err.Error()).To(ContainSubstring("SHA256 comparison failed"))
+			})
+		})
+
+		Context("when the MD5 is not calculated", func() {
+			BeforeEach(func() {
+				fileContentsMD5s = []string{"", "", "", ""}
+			})
+
+			It("does not compare MD5", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
 	})
 
 	Context("when version is provided without fingerprint", func() {
@@ -453,6 +682,115 @@ var _ = Describe("In", func() {
 				actualFingerprint,
 			))
 		})
+
+		Context("when stale fingerprints are allowed", func() {
+			BeforeEach(func() {
+				inRequest.Params.AllowStaleFingerprint = true
+			})
+
+			It("returns without error", func() {
+				response, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Version.ProductVersion).To(Equal(versionWithFingerprint))
+			})
+		})
+	})
+
+	Context("when the actual fingerprint is empty", func() {
+		BeforeEach(func() {
+			fakeFingerprinter.FingerprintStub = nil
+			fakeFingerprinter.FingerprintReturns("", nil)
+		})
+
+		It("returns without error (does not compare against actual fingerprint)", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when fingerprinting the release returns an error", func() {
+		var (
+			fingerprintErr error
+		)
+
+		BeforeEach(func() {
+			fingerprintErr = fmt.Errorf("some fingerprint error")
+
+			fakeFingerprinter.FingerprintStub = nil
+			fakeFingerprinter.FingerprintReturns("", fingerprintErr)
+		})
+
+		It("returns the error", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).To(Equal(fingerprintErr))
+		})
+	})
+
+	It("accepts the EULA and records when it was accepted", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(1))
+		invokedProductSlug, invokedReleaseID := fakePivnetClient.AcceptEULAArgsForCall(0)
+		Expect(invokedProductSlug).To(Equal(productSlug))
+		Expect(invokedReleaseID).To(Equal(release.ID))
+
+		invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+		Expect(invokedMetadata.Release.EULASlug).To(Equal(eulaSlug))
+
+		acceptedAt, err := time.Parse(time.RFC3339, invokedMetadata.Release.EULAAcceptedAt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(acceptedAt).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	Context("when accept EULA is false", func() {
+		BeforeEach(func() {
+			acceptEULA := false
+			inRequest.Params.AcceptEULA = &acceptEULA
+		})
+
+		It("does not accept the EULA", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(0))
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.Release.EULASlug).To(Equal(eulaSlug))
+			Expect(invokedMetadata.Release.EULAAcceptedAt).To(BeEmpty())
+		})
+
+		Context("when downloading files fails because the EULA has not been accepted", func() {
+			BeforeEach(func() {
+				downloadErr = pivnet.ErrUnavailableForLegalReasons{
+					ResponseCode: 451,
+					Message:      "The EULA has not been accepted.",
+				}
+			})
+
+			It("returns an error explaining how to accept the EULA", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err.Error()).To(ContainSubstring("The EULA has not been accepted."))
+				Expect(err.Error()).To(ContainSubstring("accept_eula"))
+			})
+		})
+	})
+
+	Context("when accept EULA is true", func() {
+		BeforeEach(func() {
+			acceptEULA := true
+			inRequest.Params.AcceptEULA = &acceptEULA
+		})
+
+		It("accepts the EULA", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(1))
+		})
 	})
 
 	Context("when accepting EULA returns error", func() {
@@ -517,8 +855,7 @@ var _ = Describe("In", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeFilter.ProductFileKeysByGlobsCallCount()).To(Equal(1))
-			Expect(fakePivnetClient.ProductFileForReleaseCallCount()).To(Equal(len(filteredProductFiles)))
-			Expect(fakeFileSummer.SumFileCallCount()).To(Equal(len(downloadFilepaths)))
+			Expect(fakePivnetClient.ProductFileForReleaseWithSHA256CallCount()).To(Equal(len(filteredProductFiles)))
 		})
 
 		It("includes md5 when invoking metadata writer", func() {
@@ -588,19 +925,6 @@ var _ = Describe("In", func() {
 			})
 		})
 
-		Context("when calculating md5 sum of file returns an error", func() {
-			BeforeEach(func() {
-				md5sumErr = fmt.Errorf("some md5 err error")
-			})
-
-			It("returns the error", func() {
-				_, err := inCommand.Run(inRequest)
-				Expect(err).To(HaveOccurred())
-
-				Expect(err).To(Equal(md5sumErr))
-			})
-		})
-
 		Context("when the MD5 does not match", func() {
 			BeforeEach(func() {
 				fileContentsMD5s[0] = "incorrect md5"
@@ -626,6 +950,37 @@ var _ = Describe("In", func() {
 		})
 	})
 
+	Context("when the deployed version is specified", func() {
+		BeforeEach(func() {
+			inRequest.Source.DeployedVersion = "upgrade release 56"
+		})
+
+		It("reports the upgrade path in the metadata", func() {
+			response, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{
+				Name:  "upgrade_path",
+				Value: fmt.Sprintf("upgrade release 56 -> %s", version),
+			}))
+		})
+
+		Context("when the release has no upgrade path from the deployed version", func() {
+			BeforeEach(func() {
+				inRequest.Source.DeployedVersion = "some-other-version"
+			})
+
+			It("does not report an upgrade path", func() {
+				response, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, m := range response.Metadata {
+					Expect(m.Name).NotTo(Equal("upgrade_path"))
+				}
+			})
+		})
+	})
+
 	Context("when getting release upgrade paths returns an error", func() {
 		BeforeEach(func() {
 			releaseUpgradePathsErr = fmt.Errorf("some release upgrade paths error")
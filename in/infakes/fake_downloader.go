@@ -5,10 +5,11 @@ import (
 	"sync"
 
 	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 )
 
 type FakeDownloader struct {
-	DownloadStub        func(productFiles []go_pivnet.ProductFile, productSlug string, releaseID int) ([]string, error)
+	DownloadStub        func(productFiles []go_pivnet.ProductFile, productSlug string, releaseID int) ([]string, map[string]checksum.Checksums, error)
 	downloadMutex       sync.RWMutex
 	downloadArgsForCall []struct {
 		productFiles []go_pivnet.ProductFile
@@ -17,13 +18,14 @@ type FakeDownloader struct {
 	}
 	downloadReturns struct {
 		result1 []string
-		result2 error
+		result2 map[string]checksum.Checksums
+		result3 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDownloader) Download(productFiles []go_pivnet.ProductFile, productSlug string, releaseID int) ([]string, error) {
+func (fake *FakeDownloader) Download(productFiles []go_pivnet.ProductFile, productSlug string, releaseID int) ([]string, map[string]checksum.Checksums, error) {
 	var productFilesCopy []go_pivnet.ProductFile
 	if productFiles != nil {
 		productFilesCopy = make([]go_pivnet.ProductFile, len(productFiles))
@@ -40,7 +42,7 @@ func (fake *FakeDownloader) Download(productFiles []go_pivnet.ProductFile, produ
 	if fake.DownloadStub != nil {
 		return fake.DownloadStub(productFiles, productSlug, releaseID)
 	} else {
-		return fake.downloadReturns.result1, fake.downloadReturns.result2
+		return fake.downloadReturns.result1, fake.downloadReturns.result2, fake.downloadReturns.result3
 	}
 }
 
@@ -56,12 +58,13 @@ func (fake *FakeDownloader) DownloadArgsForCall(i int) ([]go_pivnet.ProductFile,
 	return fake.downloadArgsForCall[i].productFiles, fake.downloadArgsForCall[i].productSlug, fake.downloadArgsForCall[i].releaseID
 }
 
-func (fake *FakeDownloader) DownloadReturns(result1 []string, result2 error) {
+func (fake *FakeDownloader) DownloadReturns(result1 []string, result2 map[string]checksum.Checksums, result3 error) {
 	fake.DownloadStub = nil
 	fake.downloadReturns = struct {
 		result1 []string
-		result2 error
-	}{result1, result2}
+		result2 map[string]checksum.Checksums
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakeDownloader) Invocations() map[string][][]interface{} {
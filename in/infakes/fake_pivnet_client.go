@@ -47,16 +47,17 @@ type FakePivnetClient struct {
 		result1 []go_pivnet.ProductFile
 		result2 error
 	}
-	ProductFileForReleaseStub        func(productSlug string, releaseID int, productFileID int) (go_pivnet.ProductFile, error)
-	productFileForReleaseMutex       sync.RWMutex
-	productFileForReleaseArgsForCall []struct {
+	ProductFileForReleaseWithSHA256Stub        func(productSlug string, releaseID int, productFileID int) (go_pivnet.ProductFile, string, error)
+	productFileForReleaseWithSHA256Mutex       sync.RWMutex
+	productFileForReleaseWithSHA256ArgsForCall []struct {
 		productSlug   string
 		releaseID     int
 		productFileID int
 	}
-	productFileForReleaseReturns struct {
+	productFileForReleaseWithSHA256Returns struct {
 		result1 go_pivnet.ProductFile
-		result2 error
+		result2 string
+		result3 error
 	}
 	ReleaseDependenciesStub        func(productSlug string, releaseID int) ([]go_pivnet.ReleaseDependency, error)
 	releaseDependenciesMutex       sync.RWMutex
@@ -221,40 +222,41 @@ func (fake *FakePivnetClient) ProductFilesForReleaseReturns(result1 []go_pivnet.
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) ProductFileForRelease(productSlug string, releaseID int, productFileID int) (go_pivnet.ProductFile, error) {
-	fake.productFileForReleaseMutex.Lock()
-	fake.productFileForReleaseArgsForCall = append(fake.productFileForReleaseArgsForCall, struct {
+func (fake *FakePivnetClient) ProductFileForReleaseWithSHA256(productSlug string, releaseID int, productFileID int) (go_pivnet.ProductFile, string, error) {
+	fake.productFileForReleaseWithSHA256Mutex.Lock()
+	fake.productFileForReleaseWithSHA256ArgsForCall = append(fake.productFileForReleaseWithSHA256ArgsForCall, struct {
 		productSlug   string
 		releaseID     int
 		productFileID int
 	}{productSlug, releaseID, productFileID})
-	fake.recordInvocation("ProductFileForRelease", []interface{}{productSlug, releaseID, productFileID})
-	fake.productFileForReleaseMutex.Unlock()
-	if fake.ProductFileForReleaseStub != nil {
-		return fake.ProductFileForReleaseStub(productSlug, releaseID, productFileID)
+	fake.recordInvocation("ProductFileForReleaseWithSHA256", []interface{}{productSlug, releaseID, productFileID})
+	fake.productFileForReleaseWithSHA256Mutex.Unlock()
+	if fake.ProductFileForReleaseWithSHA256Stub != nil {
+		return fake.ProductFileForReleaseWithSHA256Stub(productSlug, releaseID, productFileID)
 	} else {
-		return fake.productFileForReleaseReturns.result1, fake.productFileForReleaseReturns.result2
+		return fake.productFileForReleaseWithSHA256Returns.result1, fake.productFileForReleaseWithSHA256Returns.result2, fake.productFileForReleaseWithSHA256Returns.result3
 	}
 }
 
-func (fake *FakePivnetClient) ProductFileForReleaseCallCount() int {
-	fake.productFileForReleaseMutex.RLock()
-	defer fake.productFileForReleaseMutex.RUnlock()
-	return len(fake.productFileForReleaseArgsForCall)
+func (fake *FakePivnetClient) ProductFileForReleaseWithSHA256CallCount() int {
+	fake.productFileForReleaseWithSHA256Mutex.RLock()
+	defer fake.productFileForReleaseWithSHA256Mutex.RUnlock()
+	return len(fake.productFileForReleaseWithSHA256ArgsForCall)
 }
 
-func (fake *FakePivnetClient) ProductFileForReleaseArgsForCall(i int) (string, int, int) {
-	fake.productFileForReleaseMutex.RLock()
-	defer fake.productFileForReleaseMutex.RUnlock()
-	return fake.productFileForReleaseArgsForCall[i].productSlug, fake.productFileForReleaseArgsForCall[i].releaseID, fake.productFileForReleaseArgsForCall[i].productFileID
+func (fake *FakePivnetClient) ProductFileForReleaseWithSHA256ArgsForCall(i int) (string, int, int) {
+	fake.productFileForReleaseWithSHA256Mutex.RLock()
+	defer fake.productFileForReleaseWithSHA256Mutex.RUnlock()
+	return fake.productFileForReleaseWithSHA256ArgsForCall[i].productSlug, fake.productFileForReleaseWithSHA256ArgsForCall[i].releaseID, fake.productFileForReleaseWithSHA256ArgsForCall[i].productFileID
 }
 
-func (fake *FakePivnetClient) ProductFileForReleaseReturns(result1 go_pivnet.ProductFile, result2 error) {
-	fake.ProductFileForReleaseStub = nil
-	fake.productFileForReleaseReturns = struct {
+func (fake *FakePivnetClient) ProductFileForReleaseWithSHA256Returns(result1 go_pivnet.ProductFile, result2 string, result3 error) {
+	fake.ProductFileForReleaseWithSHA256Stub = nil
+	fake.productFileForReleaseWithSHA256Returns = struct {
 		result1 go_pivnet.ProductFile
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakePivnetClient) ReleaseDependencies(productSlug string, releaseID int) ([]go_pivnet.ReleaseDependency, error) {
@@ -338,8 +340,8 @@ func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	defer fake.fileGroupsForReleaseMutex.RUnlock()
 	fake.productFilesForReleaseMutex.RLock()
 	defer fake.productFilesForReleaseMutex.RUnlock()
-	fake.productFileForReleaseMutex.RLock()
-	defer fake.productFileForReleaseMutex.RUnlock()
+	fake.productFileForReleaseWithSHA256Mutex.RLock()
+	defer fake.productFileForReleaseWithSHA256Mutex.RUnlock()
 	fake.releaseDependenciesMutex.RLock()
 	defer fake.releaseDependenciesMutex.RUnlock()
 	fake.releaseUpgradePathsMutex.RLock()
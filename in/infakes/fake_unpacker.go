@@ -0,0 +1,72 @@
+// This file was generated by counterfeiter
+package infakes
+
+import "sync"
+
+type FakeUnpacker struct {
+	UnpackStub        func(archivePath string) (string, error)
+	unpackMutex       sync.RWMutex
+	unpackArgsForCall []struct {
+		archivePath string
+	}
+	unpackReturns struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeUnpacker) Unpack(archivePath string) (string, error) {
+	fake.unpackMutex.Lock()
+	fake.unpackArgsForCall = append(fake.unpackArgsForCall, struct {
+		archivePath string
+	}{archivePath})
+	fake.recordInvocation("Unpack", []interface{}{archivePath})
+	fake.unpackMutex.Unlock()
+	if fake.UnpackStub != nil {
+		return fake.UnpackStub(archivePath)
+	} else {
+		return fake.unpackReturns.result1, fake.unpackReturns.result2
+	}
+}
+
+func (fake *FakeUnpacker) UnpackCallCount() int {
+	fake.unpackMutex.RLock()
+	defer fake.unpackMutex.RUnlock()
+	return len(fake.unpackArgsForCall)
+}
+
+func (fake *FakeUnpacker) UnpackArgsForCall(i int) string {
+	fake.unpackMutex.RLock()
+	defer fake.unpackMutex.RUnlock()
+	return fake.unpackArgsForCall[i].archivePath
+}
+
+func (fake *FakeUnpacker) UnpackReturns(result1 string, result2 error) {
+	fake.UnpackStub = nil
+	fake.unpackReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUnpacker) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.unpackMutex.RLock()
+	defer fake.unpackMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeUnpacker) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
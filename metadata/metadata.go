@@ -15,6 +15,7 @@ type Release struct {
 	Version               string               `yaml:"version"`
 	ReleaseType           string               `yaml:"release_type"`
 	EULASlug              string               `yaml:"eula_slug"`
+	EULAAcceptedAt        string               `yaml:"eula_accepted_at,omitempty"`
 	ReleaseDate           string               `yaml:"release_date"`
 	Description           string               `yaml:"description"`
 	ReleaseNotesURL       string               `yaml:"release_notes_url"`
@@ -41,6 +42,7 @@ type ProductFile struct {
 	FileType     string `yaml:"file_type,omitempty"`
 	FileVersion  string `yaml:"file_version,omitempty"`
 	MD5          string `yaml:"md5,omitempty"`
+	SHA256       string `yaml:"sha256,omitempty"`
 	ID           int    `yaml:"id,omitempty"`
 }
 
@@ -51,7 +53,8 @@ type FileGroup struct {
 }
 
 type FileGroupProductFile struct {
-	ID int `yaml:"id,omitempty"`
+	ID   int    `yaml:"id,omitempty"`
+	File string `yaml:"file,omitempty"`
 }
 
 type Dependency struct {
@@ -119,5 +122,43 @@ func (m Metadata) Validate() error {
 		}
 	}
 
+	for i, fg := range m.FileGroups {
+		if fg.ID == 0 && fg.Name == "" {
+			return fmt.Errorf(
+				"Either id or name must be provided for file_groups[%d]",
+				i,
+			)
+		}
+
+		for j, pf := range fg.ProductFiles {
+			if pf.ID == 0 && pf.File == "" {
+				return fmt.Errorf(
+					"Either id or file must be provided for file_groups[%d].product_files[%d]",
+					i,
+					j,
+				)
+			}
+
+			if pf.File != "" && !m.containsProductFile(pf.File) {
+				return fmt.Errorf(
+					"file: '%s' in file_groups[%d].product_files[%d] must match a file in product_files",
+					pf.File,
+					i,
+					j,
+				)
+			}
+		}
+	}
+
 	return nil
 }
+
+func (m Metadata) containsProductFile(file string) bool {
+	for _, productFile := range m.ProductFiles {
+		if productFile.File == file {
+			return true
+		}
+	}
+
+	return false
+}
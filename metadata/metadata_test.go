@@ -169,5 +169,62 @@ var _ = Describe("Metadata", func() {
 				})
 			})
 		})
+
+		Context("when file groups are provided", func() {
+			BeforeEach(func() {
+				data.FileGroups = []metadata.FileGroup{
+					{
+						Name: "some-file-group",
+						ProductFiles: []metadata.FileGroupProductFile{
+							{ID: 1234},
+							{File: "hello.txt"},
+						},
+					},
+				}
+			})
+
+			It("returns without error", func() {
+				Expect(data.Validate()).NotTo(HaveOccurred())
+			})
+
+			Context("when id is 0 and name is empty", func() {
+				BeforeEach(func() {
+					data.FileGroups[0].Name = ""
+				})
+
+				It("returns an error", func() {
+					err := data.Validate()
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(MatchRegexp(".*file_groups\\[0\\]"))
+				})
+			})
+
+			Context("when a product file has neither id nor file", func() {
+				BeforeEach(func() {
+					data.FileGroups[0].ProductFiles[0].ID = 0
+				})
+
+				It("returns an error", func() {
+					err := data.Validate()
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(MatchRegexp(".*file_groups\\[0\\].product_files\\[0\\]"))
+				})
+			})
+
+			Context("when a product file does not match any file in product_files", func() {
+				BeforeEach(func() {
+					data.FileGroups[0].ProductFiles[1].File = "goodbye.txt"
+				})
+
+				It("returns an error", func() {
+					err := data.Validate()
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("goodbye.txt"))
+				})
+			})
+		})
 	})
 })
@@ -19,10 +19,15 @@ type OutCommand struct {
 	userGroupsUpdater        userGroupsUpdater
 	releaseDependenciesAdder releaseDependenciesAdder
 	releaseUpgradePathsAdder releaseUpgradePathsAdder
+	releaseFileGroupsAdder   releaseFileGroupsAdder
 	finalizer                finalizer
 	uploader                 uploader
+	transaction              transaction
+	planner                  planner
 	m                        metadata.Metadata
 	skipUpload               bool
+	rollbackOnFailure        bool
+	dryRun                   bool
 }
 
 type OutCommandConfig struct {
@@ -35,10 +40,15 @@ type OutCommandConfig struct {
 	UserGroupsUpdater        userGroupsUpdater
 	ReleaseDependenciesAdder releaseDependenciesAdder
 	ReleaseUpgradePathsAdder releaseUpgradePathsAdder
+	ReleaseFileGroupsAdder   releaseFileGroupsAdder
 	Finalizer                finalizer
 	Uploader                 uploader
+	Transaction              transaction
+	Planner                  planner
 	M                        metadata.Metadata
 	SkipUpload               bool
+	RollbackOnFailure        bool
+	DryRun                   bool
 }
 
 func NewOutCommand(config OutCommandConfig) OutCommand {
@@ -52,10 +62,15 @@ func NewOutCommand(config OutCommandConfig) OutCommand {
 		userGroupsUpdater:        config.UserGroupsUpdater,
 		releaseDependenciesAdder: config.ReleaseDependenciesAdder,
 		releaseUpgradePathsAdder: config.ReleaseUpgradePathsAdder,
+		releaseFileGroupsAdder:   config.ReleaseFileGroupsAdder,
 		finalizer:                config.Finalizer,
 		uploader:                 config.Uploader,
+		transaction:              config.Transaction,
+		planner:                  config.Planner,
 		m:                        config.M,
 		skipUpload:               config.SkipUpload,
+		rollbackOnFailure:        config.RollbackOnFailure,
+		dryRun:                   config.DryRun,
 	}
 }
 
@@ -84,11 +99,26 @@ type releaseUpgradePathsAdder interface {
 	AddReleaseUpgradePaths(release pivnet.Release) error
 }
 
+//go:generate counterfeiter --fake-name ReleaseFileGroupsAdder . releaseFileGroupsAdder
+type releaseFileGroupsAdder interface {
+	AddReleaseFileGroups(release pivnet.Release) error
+}
+
 //go:generate counterfeiter --fake-name Finalizer . finalizer
 type finalizer interface {
 	Finalize(productSlug string, releaseVersion string) (concourse.OutResponse, error)
 }
 
+//go:generate counterfeiter --fake-name Transaction . transaction
+type transaction interface {
+	Rollback() string
+}
+
+//go:generate counterfeiter --fake-name Planner . planner
+type planner interface {
+	Plan(exactGlobs []string) (string, error)
+}
+
 //go:generate counterfeiter --fake-name Validation . validation
 type validation interface {
 	Validate() error
@@ -138,6 +168,54 @@ func (c OutCommand) Run(input concourse.OutRequest) (concourse.OutResponse, erro
 			)
 	}
 
+	if c.dryRun {
+		return c.plan(exactGlobs)
+	}
+
+	out, err := c.put(input, exactGlobs)
+	if err != nil {
+		if c.rollbackOnFailure {
+			c.logger.Info("Put failed - rolling back")
+
+			summary := c.transaction.Rollback()
+			c.logger.Info(summary)
+
+			return concourse.OutResponse{}, fmt.Errorf("%s\n%s", err.Error(), summary)
+		}
+
+		return concourse.OutResponse{}, err
+	}
+
+	c.logger.Info("Put complete")
+
+	return out, nil
+}
+
+// plan logs what a put would do without doing it. The response carries the
+// version from the metadata, as no release is created.
+func (c OutCommand) plan(exactGlobs []string) (concourse.OutResponse, error) {
+	c.logger.Info("Dry run - planning put without making any changes")
+
+	plan, err := c.planner.Plan(exactGlobs)
+	if err != nil {
+		return concourse.OutResponse{}, err
+	}
+
+	c.logger.Info(plan)
+
+	return concourse.OutResponse{
+		Version: concourse.Version{
+			ProductVersion: c.m.Release.Version,
+		},
+		Metadata: []concourse.Metadata{
+			{Name: "dry_run", Value: "true"},
+		},
+	}, nil
+}
+
+// put creates and populates the release. Every side effect is recorded in the
+// transaction so that it can be rolled back if a later step fails.
+func (c OutCommand) put(input concourse.OutRequest, exactGlobs []string) (concourse.OutResponse, error) {
 	pivnetRelease, err := c.creator.Create()
 	if err != nil {
 		return concourse.OutResponse{}, err
@@ -163,17 +241,15 @@ func (c OutCommand) Run(input concourse.OutRequest) (concourse.OutResponse, erro
 		return concourse.OutResponse{}, err
 	}
 
-	pivnetRelease, err = c.userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+	err = c.releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
 	if err != nil {
 		return concourse.OutResponse{}, err
 	}
 
-	out, err := c.finalizer.Finalize(input.Source.ProductSlug, pivnetRelease.Version)
+	pivnetRelease, err = c.userGroupsUpdater.UpdateUserGroups(pivnetRelease)
 	if err != nil {
 		return concourse.OutResponse{}, err
 	}
 
-	c.logger.Info("Put complete")
-
-	return out, nil
+	return c.finalizer.Finalize(input.Source.ProductSlug, pivnetRelease.Version)
 }
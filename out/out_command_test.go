@@ -25,14 +25,19 @@ var _ = Describe("Out", func() {
 			userGroupsUpdater        *outfakes.UserGroupsUpdater
 			releaseDependenciesAdder *outfakes.ReleaseDependenciesAdder
 			releaseUpgradePathsAdder *outfakes.ReleaseUpgradePathsAdder
+			releaseFileGroupsAdder   *outfakes.ReleaseFileGroupsAdder
 			creator                  *outfakes.Creator
 			validator                *outfakes.Validation
 			uploader                 *outfakes.Uploader
 			globber                  *outfakes.Globber
+			transaction              *outfakes.Transaction
+			planner                  *outfakes.Planner
 			cmd                      out.OutCommand
 
-			skipUpload bool
-			request    concourse.OutRequest
+			skipUpload        bool
+			rollbackOnFailure bool
+			dryRun            bool
+			request           concourse.OutRequest
 
 			productSlug string
 
@@ -45,6 +50,7 @@ var _ = Describe("Out", func() {
 			updateUserGroupErr        error
 			addReleaseDependenciesErr error
 			addReleaseUpgradePathsErr error
+			addReleaseFileGroupsErr   error
 			finalizeErr               error
 		)
 
@@ -56,12 +62,17 @@ var _ = Describe("Out", func() {
 			userGroupsUpdater = &outfakes.UserGroupsUpdater{}
 			releaseDependenciesAdder = &outfakes.ReleaseDependenciesAdder{}
 			releaseUpgradePathsAdder = &outfakes.ReleaseUpgradePathsAdder{}
+			releaseFileGroupsAdder = &outfakes.ReleaseFileGroupsAdder{}
 			creator = &outfakes.Creator{}
 			validator = &outfakes.Validation{}
 			uploader = &outfakes.Uploader{}
 			globber = &outfakes.Globber{}
+			transaction = &outfakes.Transaction{}
+			planner = &outfakes.Planner{}
 
 			skipUpload = false
+			rollbackOnFailure = false
+			dryRun = false
 
 			productSlug = "some-product-slug"
 
@@ -74,6 +85,7 @@ var _ = Describe("Out", func() {
 			updateUserGroupErr = nil
 			addReleaseDependenciesErr = nil
 			addReleaseUpgradePathsErr = nil
+			addReleaseFileGroupsErr = nil
 			finalizeErr = nil
 		})
 
@@ -103,9 +115,14 @@ var _ = Describe("Out", func() {
 				UserGroupsUpdater:        userGroupsUpdater,
 				ReleaseDependenciesAdder: releaseDependenciesAdder,
 				ReleaseUpgradePathsAdder: releaseUpgradePathsAdder,
+				ReleaseFileGroupsAdder:   releaseFileGroupsAdder,
 				Uploader:                 uploader,
+				Transaction:              transaction,
+				Planner:                  planner,
 				M:                        meta,
 				SkipUpload:               skipUpload,
+				RollbackOnFailure:        rollbackOnFailure,
+				DryRun:                   dryRun,
 			}
 
 			cmd = out.NewOutCommand(config)
@@ -120,6 +137,7 @@ var _ = Describe("Out", func() {
 			uploader.UploadReturns(uploadErr)
 			releaseDependenciesAdder.AddReleaseDependenciesReturns(addReleaseDependenciesErr)
 			releaseUpgradePathsAdder.AddReleaseUpgradePathsReturns(addReleaseUpgradePathsErr)
+			releaseFileGroupsAdder.AddReleaseFileGroupsReturns(addReleaseFileGroupsErr)
 
 			finalizer.FinalizeReturns(concourse.OutResponse{
 				Version: concourse.Version{
@@ -150,6 +168,7 @@ var _ = Describe("Out", func() {
 
 			Expect(releaseDependenciesAdder.AddReleaseDependenciesCallCount()).To(Equal(1))
 			Expect(releaseUpgradePathsAdder.AddReleaseUpgradePathsCallCount()).To(Equal(1))
+			Expect(releaseFileGroupsAdder.AddReleaseFileGroupsCallCount()).To(Equal(1))
 
 			Expect(uploader.UploadCallCount()).To(Equal(1))
 			invokedPivnetRelease, invokedExactGlobs := uploader.UploadArgsForCall(0)
@@ -222,6 +241,72 @@ var _ = Describe("Out", func() {
 			})
 		})
 
+		Context("when rollback on failure is enabled and the put succeeds", func() {
+			BeforeEach(func() {
+				rollbackOnFailure = true
+			})
+
+			It("does not roll back", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(transaction.RollbackCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when dry run is enabled", func() {
+			BeforeEach(func() {
+				dryRun = true
+				planner.PlanReturns("some plan", nil)
+			})
+
+			It("plans the put with the exact globs", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(planner.PlanCallCount()).To(Equal(1))
+				Expect(planner.PlanArgsForCall(0)).To(Equal(returnedExactGlobs))
+			})
+
+			It("does not put the release", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(creator.CreateCallCount()).To(Equal(0))
+				Expect(uploader.UploadCallCount()).To(Equal(0))
+				Expect(releaseUpgradePathsAdder.AddReleaseUpgradePathsCallCount()).To(Equal(0))
+				Expect(releaseDependenciesAdder.AddReleaseDependenciesCallCount()).To(Equal(0))
+				Expect(releaseFileGroupsAdder.AddReleaseFileGroupsCallCount()).To(Equal(0))
+				Expect(userGroupsUpdater.UpdateUserGroupsCallCount()).To(Equal(0))
+				Expect(finalizer.FinalizeCallCount()).To(Equal(0))
+			})
+
+			It("returns the version from the metadata", func() {
+				response, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(Equal(concourse.OutResponse{
+					Version: concourse.Version{
+						ProductVersion: "release-version",
+					},
+					Metadata: []concourse.Metadata{
+						{Name: "dry_run", Value: "true"},
+					},
+				}))
+			})
+
+			Context("when planning returns an error", func() {
+				BeforeEach(func() {
+					planner.PlanReturns("", errors.New("plan error"))
+				})
+
+				It("returns the error", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("plan error"))
+				})
+			})
+		})
+
 		Context("when a release cannot be created", func() {
 			BeforeEach(func() {
 				createErr = errors.New("some create error")
@@ -242,6 +327,30 @@ var _ = Describe("Out", func() {
 				_, err := cmd.Run(request)
 				Expect(err).To(Equal(uploadErr))
 			})
+
+			It("does not roll back", func() {
+				_, err := cmd.Run(request)
+				Expect(err).To(HaveOccurred())
+
+				Expect(transaction.RollbackCallCount()).To(Equal(0))
+			})
+
+			Context("when rollback on failure is enabled", func() {
+				BeforeEach(func() {
+					rollbackOnFailure = true
+					transaction.RollbackReturns("some rollback summary")
+				})
+
+				It("rolls back and includes the summary in the error", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(HaveOccurred())
+
+					Expect(transaction.RollbackCallCount()).To(Equal(1))
+
+					Expect(err.Error()).To(ContainSubstring("upload error"))
+					Expect(err.Error()).To(ContainSubstring("some rollback summary"))
+				})
+			})
 		})
 
 		Context("when user groups cannot be updated", func() {
@@ -277,6 +386,17 @@ var _ = Describe("Out", func() {
 			})
 		})
 
+		Context("when file groups cannot be added", func() {
+			BeforeEach(func() {
+				addReleaseFileGroupsErr = errors.New("some release file groups error")
+			})
+
+			It("returns an error", func() {
+				_, err := cmd.Run(request)
+				Expect(err).To(Equal(addReleaseFileGroupsErr))
+			})
+		})
+
 		Context("when a release cannot be finalized", func() {
 			BeforeEach(func() {
 				finalizeErr = errors.New("some finalize error")
@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import "sync"
+
+type Planner struct {
+	PlanStub        func(exactGlobs []string) (string, error)
+	planMutex       sync.RWMutex
+	planArgsForCall []struct {
+		exactGlobs []string
+	}
+	planReturns struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Planner) Plan(exactGlobs []string) (string, error) {
+	var exactGlobsCopy []string
+	if exactGlobs != nil {
+		exactGlobsCopy = make([]string, len(exactGlobs))
+		copy(exactGlobsCopy, exactGlobs)
+	}
+	fake.planMutex.Lock()
+	fake.planArgsForCall = append(fake.planArgsForCall, struct {
+		exactGlobs []string
+	}{exactGlobsCopy})
+	fake.recordInvocation("Plan", []interface{}{exactGlobsCopy})
+	fake.planMutex.Unlock()
+	if fake.PlanStub != nil {
+		return fake.PlanStub(exactGlobs)
+	} else {
+		return fake.planReturns.result1, fake.planReturns.result2
+	}
+}
+
+func (fake *Planner) PlanCallCount() int {
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	return len(fake.planArgsForCall)
+}
+
+func (fake *Planner) PlanArgsForCall(i int) []string {
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	return fake.planArgsForCall[i].exactGlobs
+}
+
+func (fake *Planner) PlanReturns(result1 string, result2 error) {
+	fake.PlanStub = nil
+	fake.planReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Planner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *Planner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,60 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import "sync"
+
+type Transaction struct {
+	RollbackStub        func() string
+	rollbackMutex       sync.RWMutex
+	rollbackArgsForCall []struct{}
+	rollbackReturns     struct {
+		result1 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Transaction) Rollback() string {
+	fake.rollbackMutex.Lock()
+	fake.rollbackArgsForCall = append(fake.rollbackArgsForCall, struct{}{})
+	fake.recordInvocation("Rollback", []interface{}{})
+	fake.rollbackMutex.Unlock()
+	if fake.RollbackStub != nil {
+		return fake.RollbackStub()
+	} else {
+		return fake.rollbackReturns.result1
+	}
+}
+
+func (fake *Transaction) RollbackCallCount() int {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return len(fake.rollbackArgsForCall)
+}
+
+func (fake *Transaction) RollbackReturns(result1 string) {
+	fake.RollbackStub = nil
+	fake.rollbackReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *Transaction) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *Transaction) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
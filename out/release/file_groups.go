@@ -0,0 +1,428 @@
+package release
+
+import (
+	"fmt"
+	"path/filepath"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+)
+
+type ReleaseFileGroupsAdder struct {
+	logger      logger.Logger
+	pivnet      releaseFileGroupsAdderClient
+	journal     journal
+	metadata    metadata.Metadata
+	productSlug string
+}
+
+func NewReleaseFileGroupsAdder(
+	logger logger.Logger,
+	pivnetClient releaseFileGroupsAdderClient,
+	journal journal,
+	metadata metadata.Metadata,
+	productSlug string,
+) ReleaseFileGroupsAdder {
+	return ReleaseFileGroupsAdder{
+		logger:      logger,
+		pivnet:      pivnetClient,
+		journal:     journal,
+		metadata:    metadata,
+		productSlug: productSlug,
+	}
+}
+
+//go:generate counterfeiter --fake-name ReleaseFileGroupsAdderClient . releaseFileGroupsAdderClient
+type releaseFileGroupsAdderClient interface {
+	FileGroups(productSlug string) ([]pivnet.FileGroup, error)
+	FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error)
+	CreateFileGroup(productSlug string, name string) (pivnet.FileGroup, error)
+	DeleteFileGroup(productSlug string, fileGroupID int) error
+	AddFileGroup(productSlug string, releaseID int, fileGroupID int) error
+	RemoveFileGroup(productSlug string, releaseID int, fileGroupID int) error
+	AddToFileGroup(productSlug string, fileGroupID int, productFileID int) error
+	RemoveFromFileGroup(productSlug string, fileGroupID int, productFileID int) error
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+}
+
+// AddReleaseFileGroups adds the file groups in the metadata to the release,
+// skipping file groups and product files which have already been added. If
+// the metadata provides the file groups, any others are removed from the
+// release, and file groups which list their product files have any others
+// removed.
+func (rf ReleaseFileGroupsAdder) AddReleaseFileGroups(release pivnet.Release) error {
+	if rf.metadata.FileGroups == nil {
+		return nil
+	}
+
+	existingFileGroups, err := rf.pivnet.FileGroups(rf.productSlug)
+	if err != nil {
+		return err
+	}
+
+	releaseProductFiles, err := rf.pivnet.ProductFilesForRelease(rf.productSlug, release.ID)
+	if err != nil {
+		return err
+	}
+
+	releaseFileGroups, err := rf.pivnet.FileGroupsForRelease(rf.productSlug, release.ID)
+	if err != nil {
+		return err
+	}
+
+	onRelease := map[int]bool{}
+	for _, fg := range releaseFileGroups {
+		onRelease[fg.ID] = true
+	}
+
+	desired := map[int]bool{}
+
+	for i, fg := range rf.metadata.FileGroups {
+		if fg.ID == 0 && fg.Name == "" {
+			return fmt.Errorf(
+				"Either id or name must be provided for file_groups[%d]",
+				i,
+			)
+		}
+
+		fileGroupID := fg.ID
+		if fileGroupID == 0 {
+			fileGroupID = findFileGroupIDForName(existingFileGroups, fg.Name)
+		}
+
+		created := fileGroupID == 0
+		if created {
+			rf.logger.Info(fmt.Sprintf("Creating file group: '%s'", fg.Name))
+
+			fileGroup, err := rf.pivnet.CreateFileGroup(rf.productSlug, fg.Name)
+			if err != nil {
+				return err
+			}
+			fileGroupID = fileGroup.ID
+
+			rf.journal.Record(
+				fmt.Sprintf("created file group: '%s' - id: '%d'", fg.Name, fileGroupID),
+				func() error {
+					return rf.pivnet.DeleteFileGroup(rf.productSlug, fileGroupID)
+				},
+			)
+		} else {
+			rf.logger.Info(fmt.Sprintf("Using existing file group with ID: %d", fileGroupID))
+		}
+
+		desired[fileGroupID] = true
+		desiredProductFiles := map[int]bool{}
+
+		for j, pf := range fg.ProductFiles {
+			productFileID := pf.ID
+			if productFileID == 0 {
+				productFileID, err = rf.productFileIDForFile(releaseProductFiles, pf.File)
+				if err != nil {
+					return fmt.Errorf("file_groups[%d].product_files[%d]: %s", i, j, err.Error())
+				}
+			}
+
+			desiredProductFiles[productFileID] = true
+
+			if fileGroupContains(existingFileGroups, fileGroupID, productFileID) {
+				rf.logger.Info(fmt.Sprintf(
+					"Product file with ID: %d already in file group with ID: %d",
+					productFileID,
+					fileGroupID,
+				))
+				continue
+			}
+
+			rf.logger.Info(fmt.Sprintf(
+				"Adding product file with ID: %d to file group with ID: %d",
+				productFileID,
+				fileGroupID,
+			))
+			err = rf.pivnet.AddToFileGroup(rf.productSlug, fileGroupID, productFileID)
+			if err != nil {
+				return err
+			}
+
+			// Deleting a created file group removes its product files, so
+			// only membership of existing file groups needs to be undone.
+			if !created {
+				groupID, fileID := fileGroupID, productFileID
+				rf.journal.Record(
+					fmt.Sprintf("added product file with ID: %d to file group with ID: %d", fileID, groupID),
+					func() error {
+						return rf.pivnet.RemoveFromFileGroup(rf.productSlug, groupID, fileID)
+					},
+				)
+			}
+		}
+
+		if !created && fg.ProductFiles != nil {
+			err = rf.removeFileGroupProductFiles(existingFileGroups, fileGroupID, desiredProductFiles)
+			if err != nil {
+				return err
+			}
+		}
+
+		if onRelease[fileGroupID] {
+			rf.logger.Info(fmt.Sprintf("File group with ID: %d already added", fileGroupID))
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf("Adding file group with ID: %d", fileGroupID))
+		err = rf.pivnet.AddFileGroup(rf.productSlug, release.ID, fileGroupID)
+		if err != nil {
+			return err
+		}
+
+		// Deleting a created file group also removes it from the release.
+		if !created {
+			groupID := fileGroupID
+			rf.journal.Record(
+				fmt.Sprintf("added file group with ID: %d to release", groupID),
+				func() error {
+					return rf.pivnet.RemoveFileGroup(rf.productSlug, release.ID, groupID)
+				},
+			)
+		}
+	}
+
+	for _, fg := range releaseFileGroups {
+		if desired[fg.ID] {
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf("Removing file group with ID: %d", fg.ID))
+		err = rf.pivnet.RemoveFileGroup(rf.productSlug, release.ID, fg.ID)
+		if err != nil {
+			return err
+		}
+
+		groupID := fg.ID
+		rf.journal.Record(
+			fmt.Sprintf("removed file group with ID: %d from release", groupID),
+			func() error {
+				return rf.pivnet.AddFileGroup(rf.productSlug, release.ID, groupID)
+			},
+		)
+	}
+
+	return nil
+}
+
+// removeFileGroupProductFiles removes the product files from the file group,
+// other than those to keep.
+func (rf ReleaseFileGroupsAdder) removeFileGroupProductFiles(
+	fileGroups []pivnet.FileGroup,
+	fileGroupID int,
+	keep map[int]bool,
+) error {
+	for _, pf := range fileGroupProductFiles(fileGroups, fileGroupID) {
+		if keep[pf.ID] {
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf(
+			"Removing product file with ID: %d from file group with ID: %d",
+			pf.ID,
+			fileGroupID,
+		))
+		err := rf.pivnet.RemoveFromFileGroup(rf.productSlug, fileGroupID, pf.ID)
+		if err != nil {
+			return err
+		}
+
+		groupID, fileID := fileGroupID, pf.ID
+		rf.journal.Record(
+			fmt.Sprintf("removed product file with ID: %d from file group with ID: %d", fileID, groupID),
+			func() error {
+				return rf.pivnet.AddToFileGroup(rf.productSlug, groupID, fileID)
+			},
+		)
+	}
+
+	return nil
+}
+
+// PlanReleaseFileGroups resolves the file groups in the metadata, without
+// changing anything, and adds what AddReleaseFileGroups would do to the plan.
+// Product files which are not on the release yet are named by their file.
+func (rf ReleaseFileGroupsAdder) PlanReleaseFileGroups(plan *Plan, release pivnet.Release) error {
+	if rf.metadata.FileGroups == nil {
+		return nil
+	}
+
+	existingFileGroups, err := rf.pivnet.FileGroups(rf.productSlug)
+	if err != nil {
+		return err
+	}
+
+	var releaseProductFiles []pivnet.ProductFile
+	var releaseFileGroups []pivnet.FileGroup
+	if releaseExists(release) {
+		releaseProductFiles, err = rf.pivnet.ProductFilesForRelease(rf.productSlug, release.ID)
+		if err != nil {
+			return err
+		}
+
+		releaseFileGroups, err = rf.pivnet.FileGroupsForRelease(rf.productSlug, release.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	onRelease := map[int]bool{}
+	for _, fg := range releaseFileGroups {
+		onRelease[fg.ID] = true
+	}
+
+	desired := map[int]bool{}
+
+	for i, fg := range rf.metadata.FileGroups {
+		if fg.ID == 0 && fg.Name == "" {
+			return fmt.Errorf(
+				"Either id or name must be provided for file_groups[%d]",
+				i,
+			)
+		}
+
+		fileGroupID := fg.ID
+		if fileGroupID == 0 {
+			fileGroupID = findFileGroupIDForName(existingFileGroups, fg.Name)
+		}
+
+		name := fmt.Sprintf("file group: '%s'", fg.Name)
+		switch {
+		case fileGroupID == 0:
+			plan.FileGroups = append(plan.FileGroups, fmt.Sprintf("create %s", name))
+		case fg.Name == "":
+			name = fmt.Sprintf("file group - id: '%d'", fileGroupID)
+		default:
+			name = fmt.Sprintf("%s - id: '%d'", name, fileGroupID)
+		}
+
+		desired[fileGroupID] = true
+		desiredProductFiles := map[int]bool{}
+
+		for _, pf := range fg.ProductFiles {
+			productFileID := pf.ID
+			if productFileID == 0 {
+				productFileID, _ = rf.productFileIDForFile(releaseProductFiles, pf.File)
+			}
+
+			desiredProductFiles[productFileID] = true
+
+			if productFileID == 0 {
+				plan.FileGroups = append(plan.FileGroups, fmt.Sprintf(
+					"add product file for file: '%s' to %s",
+					pf.File,
+					name,
+				))
+				continue
+			}
+
+			if fileGroupContains(existingFileGroups, fileGroupID, productFileID) {
+				plan.FileGroups = append(plan.FileGroups, fmt.Sprintf(
+					"product file - id: '%d' is already in %s",
+					productFileID,
+					name,
+				))
+				continue
+			}
+
+			plan.FileGroups = append(plan.FileGroups, fmt.Sprintf(
+				"add product file - id: '%d' to %s",
+				productFileID,
+				name,
+			))
+		}
+
+		if fileGroupID != 0 && fg.ProductFiles != nil {
+			for _, pf := range fileGroupProductFiles(existingFileGroups, fileGroupID) {
+				if desiredProductFiles[pf.ID] {
+					continue
+				}
+
+				plan.FileGroups = append(plan.FileGroups, fmt.Sprintf(
+					"remove product file: '%s' - id: '%d' from %s",
+					pf.Name,
+					pf.ID,
+					name,
+				))
+			}
+		}
+
+		if onRelease[fileGroupID] {
+			plan.FileGroups = append(plan.FileGroups, fmt.Sprintf("%s is already added", name))
+			continue
+		}
+
+		plan.FileGroups = append(plan.FileGroups, fmt.Sprintf("add %s", name))
+	}
+
+	for _, fg := range releaseFileGroups {
+		if desired[fg.ID] {
+			continue
+		}
+
+		plan.FileGroups = append(plan.FileGroups, fmt.Sprintf(
+			"remove file group: '%s' - id: '%d'",
+			fg.Name,
+			fg.ID,
+		))
+	}
+
+	return nil
+}
+
+// productFileIDForFile finds the ID of the release product file that was
+// uploaded for the provided metadata file, using the same name as the uploader.
+func (rf ReleaseFileGroupsAdder) productFileIDForFile(
+	releaseProductFiles []pivnet.ProductFile,
+	file string,
+) (int, error) {
+	name := filepath.Base(file)
+	for _, f := range rf.metadata.ProductFiles {
+		if f.File == file && f.UploadAs != "" {
+			name = f.UploadAs
+		}
+	}
+
+	for _, pf := range releaseProductFiles {
+		if pf.Name == name {
+			return pf.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("No product file found on release for file: '%s'", file)
+}
+
+func findFileGroupIDForName(fileGroups []pivnet.FileGroup, name string) int {
+	for _, fg := range fileGroups {
+		if fg.Name == name {
+			return fg.ID
+		}
+	}
+
+	return 0
+}
+
+func fileGroupContains(fileGroups []pivnet.FileGroup, fileGroupID int, productFileID int) bool {
+	for _, pf := range fileGroupProductFiles(fileGroups, fileGroupID) {
+		if pf.ID == productFileID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fileGroupProductFiles(fileGroups []pivnet.FileGroup, fileGroupID int) []pivnet.ProductFile {
+	for _, fg := range fileGroups {
+		if fg.ID == fileGroupID {
+			return fg.ProductFiles
+		}
+	}
+
+	return nil
+}
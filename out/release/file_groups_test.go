@@ -0,0 +1,641 @@
+package release_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/out/release"
+	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReleaseFileGroupsAdder", func() {
+	Describe("AddReleaseFileGroups", func() {
+		var (
+			fakeLogger logger.Logger
+
+			pivnetClient *releasefakes.ReleaseFileGroupsAdderClient
+			fakeJournal  *releasefakes.Journal
+
+			mdata metadata.Metadata
+
+			productSlug   string
+			pivnetRelease pivnet.Release
+
+			releaseFileGroupsAdder release.ReleaseFileGroupsAdder
+		)
+
+		BeforeEach(func() {
+			logger := log.New(GinkgoWriter, "", log.LstdFlags)
+			fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+			pivnetClient = &releasefakes.ReleaseFileGroupsAdderClient{}
+			fakeJournal = &releasefakes.Journal{}
+
+			productSlug = "some-product-slug"
+
+			pivnetRelease = pivnet.Release{
+				ID:      1337,
+				Version: "some-version",
+			}
+
+			mdata = metadata.Metadata{
+				Release: &metadata.Release{
+					Version: "some-version",
+				},
+				ProductFiles: []metadata.ProductFile{
+					{
+						File: "some/path/some-file",
+					},
+					{
+						File:     "some/path/some-other-file",
+						UploadAs: "some other file",
+					},
+				},
+			}
+
+			pivnetClient.FileGroupsReturns([]pivnet.FileGroup{
+				{ID: 4321, Name: "some-existing-file-group"},
+			}, nil)
+
+			pivnetClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{
+				{ID: 1111, Name: "some-file"},
+				{ID: 2222, Name: "some other file"},
+			}, nil)
+
+			pivnetClient.CreateFileGroupReturns(pivnet.FileGroup{ID: 5678}, nil)
+		})
+
+		JustBeforeEach(func() {
+			releaseFileGroupsAdder = release.NewReleaseFileGroupsAdder(
+				fakeLogger,
+				pivnetClient,
+				fakeJournal,
+				mdata,
+				productSlug,
+			)
+		})
+
+		Context("when no file groups are provided", func() {
+			It("does not contact pivnet", func() {
+				err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.FileGroupsCallCount()).To(Equal(0))
+				Expect(pivnetClient.AddFileGroupCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when file groups are provided", func() {
+			BeforeEach(func() {
+				mdata.FileGroups = []metadata.FileGroup{
+					{
+						Name: "some-new-file-group",
+						ProductFiles: []metadata.FileGroupProductFile{
+							{File: "some/path/some-file"},
+							{File: "some/path/some-other-file"},
+						},
+					},
+					{
+						Name: "some-existing-file-group",
+						ProductFiles: []metadata.FileGroupProductFile{
+							{ID: 9876},
+						},
+					},
+				}
+			})
+
+			It("creates new file groups and reuses existing ones by name", func() {
+				err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.CreateFileGroupCallCount()).To(Equal(1))
+				invokedProductSlug, invokedName := pivnetClient.CreateFileGroupArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(invokedName).To(Equal("some-new-file-group"))
+
+				Expect(pivnetClient.AddFileGroupCallCount()).To(Equal(2))
+				_, invokedReleaseID, invokedFileGroupID := pivnetClient.AddFileGroupArgsForCall(0)
+				Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+				Expect(invokedFileGroupID).To(Equal(5678))
+
+				_, invokedReleaseID, invokedFileGroupID = pivnetClient.AddFileGroupArgsForCall(1)
+				Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+				Expect(invokedFileGroupID).To(Equal(4321))
+			})
+
+			It("adds the product files to the file groups", func() {
+				err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.AddToFileGroupCallCount()).To(Equal(3))
+
+				_, invokedFileGroupID, invokedProductFileID := pivnetClient.AddToFileGroupArgsForCall(0)
+				Expect(invokedFileGroupID).To(Equal(5678))
+				Expect(invokedProductFileID).To(Equal(1111))
+
+				_, invokedFileGroupID, invokedProductFileID = pivnetClient.AddToFileGroupArgsForCall(1)
+				Expect(invokedFileGroupID).To(Equal(5678))
+				Expect(invokedProductFileID).To(Equal(2222))
+
+				_, invokedFileGroupID, invokedProductFileID = pivnetClient.AddToFileGroupArgsForCall(2)
+				Expect(invokedFileGroupID).To(Equal(4321))
+				Expect(invokedProductFileID).To(Equal(9876))
+			})
+
+			It("records deleting created file groups and removing files from existing ones", func() {
+				err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeJournal.RecordCallCount()).To(Equal(3))
+
+				_, undo := fakeJournal.RecordArgsForCall(0)
+				err = undo()
+				Expect(err).NotTo(HaveOccurred())
+
+				invokedProductSlug, invokedFileGroupID := pivnetClient.DeleteFileGroupArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(invokedFileGroupID).To(Equal(5678))
+
+				_, undo = fakeJournal.RecordArgsForCall(1)
+				err = undo()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, invokedFileGroupID, invokedProductFileID := pivnetClient.RemoveFromFileGroupArgsForCall(0)
+				Expect(invokedFileGroupID).To(Equal(4321))
+				Expect(invokedProductFileID).To(Equal(9876))
+			})
+
+			It("records removing existing file groups from the release", func() {
+				err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				description, undo := fakeJournal.RecordArgsForCall(2)
+				Expect(description).To(ContainSubstring("4321"))
+
+				err = undo()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.RemoveFileGroupCallCount()).To(Equal(1))
+				invokedProductSlug, invokedReleaseID, invokedFileGroupID := pivnetClient.RemoveFileGroupArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+				Expect(invokedFileGroupID).To(Equal(4321))
+			})
+
+			Context("when the file groups have already been added", func() {
+				BeforeEach(func() {
+					pivnetClient.FileGroupsReturns([]pivnet.FileGroup{
+						{ID: 4321, Name: "some-existing-file-group", ProductFiles: []pivnet.ProductFile{{ID: 9876}}},
+					}, nil)
+
+					pivnetClient.FileGroupsForReleaseReturns([]pivnet.FileGroup{
+						{ID: 4321},
+					}, nil)
+				})
+
+				It("does not add them again", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					invokedProductSlug, invokedReleaseID := pivnetClient.FileGroupsForReleaseArgsForCall(0)
+					Expect(invokedProductSlug).To(Equal(productSlug))
+					Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+
+					Expect(pivnetClient.AddFileGroupCallCount()).To(Equal(1))
+					_, _, invokedFileGroupID := pivnetClient.AddFileGroupArgsForCall(0)
+					Expect(invokedFileGroupID).To(Equal(5678))
+
+					Expect(pivnetClient.AddToFileGroupCallCount()).To(Equal(2))
+					for i := 0; i < 2; i++ {
+						_, invokedFileGroupID, _ := pivnetClient.AddToFileGroupArgsForCall(i)
+						Expect(invokedFileGroupID).To(Equal(5678))
+					}
+				})
+			})
+
+			Context("when the release has file groups and product files which are not in the metadata", func() {
+				BeforeEach(func() {
+					pivnetClient.FileGroupsReturns([]pivnet.FileGroup{
+						{
+							ID:   4321,
+							Name: "some-existing-file-group",
+							ProductFiles: []pivnet.ProductFile{
+								{ID: 9876},
+								{ID: 5555, Name: "some-stale-file"},
+							},
+						},
+					}, nil)
+
+					pivnetClient.FileGroupsForReleaseReturns([]pivnet.FileGroup{
+						{ID: 4321},
+						{ID: 7777, Name: "some-old-file-group"},
+					}, nil)
+				})
+
+				It("removes them", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.RemoveFromFileGroupCallCount()).To(Equal(1))
+					invokedProductSlug, invokedFileGroupID, invokedProductFileID := pivnetClient.RemoveFromFileGroupArgsForCall(0)
+					Expect(invokedProductSlug).To(Equal(productSlug))
+					Expect(invokedFileGroupID).To(Equal(4321))
+					Expect(invokedProductFileID).To(Equal(5555))
+
+					Expect(pivnetClient.RemoveFileGroupCallCount()).To(Equal(1))
+					invokedProductSlug, invokedReleaseID, invokedFileGroupID := pivnetClient.RemoveFileGroupArgsForCall(0)
+					Expect(invokedProductSlug).To(Equal(productSlug))
+					Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+					Expect(invokedFileGroupID).To(Equal(7777))
+				})
+
+				It("records adding them back", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeJournal.RecordCallCount()).To(Equal(3))
+
+					description, undo := fakeJournal.RecordArgsForCall(1)
+					Expect(description).To(Equal("removed product file with ID: 5555 from file group with ID: 4321"))
+					err = undo()
+					Expect(err).NotTo(HaveOccurred())
+
+					_, invokedFileGroupID, invokedProductFileID := pivnetClient.AddToFileGroupArgsForCall(2)
+					Expect(invokedFileGroupID).To(Equal(4321))
+					Expect(invokedProductFileID).To(Equal(5555))
+
+					description, undo = fakeJournal.RecordArgsForCall(2)
+					Expect(description).To(Equal("removed file group with ID: 7777 from release"))
+					err = undo()
+					Expect(err).NotTo(HaveOccurred())
+
+					_, invokedReleaseID, invokedFileGroupID := pivnetClient.AddFileGroupArgsForCall(1)
+					Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+					Expect(invokedFileGroupID).To(Equal(7777))
+				})
+
+				Context("when the metadata does not list the product files of a file group", func() {
+					BeforeEach(func() {
+						mdata.FileGroups[1].ProductFiles = nil
+					})
+
+					It("does not remove the product files of that file group", func() {
+						err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(pivnetClient.RemoveFromFileGroupCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the metadata provides no file groups", func() {
+					BeforeEach(func() {
+						mdata.FileGroups = []metadata.FileGroup{}
+					})
+
+					It("removes all of the file groups from the release", func() {
+						err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(pivnetClient.RemoveFileGroupCallCount()).To(Equal(2))
+						_, _, invokedFileGroupID := pivnetClient.RemoveFileGroupArgsForCall(0)
+						Expect(invokedFileGroupID).To(Equal(4321))
+						_, _, invokedFileGroupID = pivnetClient.RemoveFileGroupArgsForCall(1)
+						Expect(invokedFileGroupID).To(Equal(7777))
+					})
+				})
+
+				Context("when removing a file group from the release returns an error", func() {
+					var (
+						expectedErr error
+					)
+
+					BeforeEach(func() {
+						expectedErr = fmt.Errorf("some remove file group error")
+						pivnetClient.RemoveFileGroupReturns(expectedErr)
+					})
+
+					It("forwards the error", func() {
+						err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+						Expect(err).To(Equal(expectedErr))
+					})
+				})
+			})
+
+			Context("when listing the file groups of the release returns an error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("some release file groups error")
+					pivnetClient.FileGroupsForReleaseReturns(nil, expectedErr)
+				})
+
+				It("forwards the error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+
+			Context("when the file group id is provided", func() {
+				BeforeEach(func() {
+					mdata.FileGroups[0].ID = 2468
+				})
+
+				It("uses the provided id without creating a file group", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.CreateFileGroupCallCount()).To(Equal(0))
+
+					_, _, invokedFileGroupID := pivnetClient.AddFileGroupArgsForCall(0)
+					Expect(invokedFileGroupID).To(Equal(2468))
+				})
+			})
+
+			Context("when neither id nor name are provided", func() {
+				BeforeEach(func() {
+					mdata.FileGroups[1].Name = ""
+				})
+
+				It("returns an error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("file_groups[1]"))
+				})
+			})
+
+			Context("when a file cannot be found on the release", func() {
+				BeforeEach(func() {
+					pivnetClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{}, nil)
+				})
+
+				It("returns an error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("file_groups[0].product_files[0]"))
+				})
+			})
+
+			Context("when listing file groups returns an error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("some file groups error")
+					pivnetClient.FileGroupsReturns(nil, expectedErr)
+				})
+
+				It("forwards the error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+
+			Context("when listing product files returns an error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("some product files error")
+					pivnetClient.ProductFilesForReleaseReturns(nil, expectedErr)
+				})
+
+				It("forwards the error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+
+			Context("when creating a file group returns an error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("some create error")
+					pivnetClient.CreateFileGroupReturns(pivnet.FileGroup{}, expectedErr)
+				})
+
+				It("forwards the error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+
+			Context("when adding a product file to a file group returns an error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("some add to file group error")
+					pivnetClient.AddToFileGroupReturns(expectedErr)
+				})
+
+				It("forwards the error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+
+			Context("when adding a file group to the release returns an error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("some add file group error")
+					pivnetClient.AddFileGroupReturns(expectedErr)
+				})
+
+				It("forwards the error", func() {
+					err := releaseFileGroupsAdder.AddReleaseFileGroups(pivnetRelease)
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+		})
+	})
+
+	Describe("PlanReleaseFileGroups", func() {
+		var (
+			fakeLogger logger.Logger
+
+			pivnetClient *releasefakes.ReleaseFileGroupsAdderClient
+
+			mdata         metadata.Metadata
+			pivnetRelease pivnet.Release
+			plan          release.Plan
+		)
+
+		BeforeEach(func() {
+			logger := log.New(GinkgoWriter, "", log.LstdFlags)
+			fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+			pivnetClient = &releasefakes.ReleaseFileGroupsAdderClient{}
+
+			pivnetRelease = pivnet.Release{
+				ID:      1337,
+				Version: "some-version",
+			}
+
+			mdata = metadata.Metadata{
+				Release: &metadata.Release{
+					Version: "some-version",
+				},
+				ProductFiles: []metadata.ProductFile{
+					{
+						File: "some/path/some-file",
+					},
+					{
+						File: "some/path/some-new-file",
+					},
+				},
+				FileGroups: []metadata.FileGroup{
+					{
+						Name: "some-new-file-group",
+						ProductFiles: []metadata.FileGroupProductFile{
+							{File: "some/path/some-file"},
+							{File: "some/path/some-new-file"},
+						},
+					},
+					{
+						Name: "some-existing-file-group",
+						ProductFiles: []metadata.FileGroupProductFile{
+							{ID: 1111},
+							{ID: 9876},
+						},
+					},
+				},
+			}
+
+			plan = release.Plan{}
+
+			pivnetClient.FileGroupsReturns([]pivnet.FileGroup{
+				{
+					ID:           4321,
+					Name:         "some-existing-file-group",
+					ProductFiles: []pivnet.ProductFile{{ID: 1111}},
+				},
+			}, nil)
+
+			pivnetClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{
+				{ID: 1111, Name: "some-file"},
+			}, nil)
+
+			pivnetClient.FileGroupsForReleaseReturns([]pivnet.FileGroup{
+				{ID: 4321},
+			}, nil)
+		})
+
+		It("plans the file groups without changing anything", func() {
+			releaseFileGroupsAdder := release.NewReleaseFileGroupsAdder(
+				fakeLogger,
+				pivnetClient,
+				&releasefakes.Journal{},
+				mdata,
+				"some-product-slug",
+			)
+
+			err := releaseFileGroupsAdder.PlanReleaseFileGroups(&plan, pivnetRelease)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.FileGroups).To(Equal([]string{
+				"create file group: 'some-new-file-group'",
+				"add product file - id: '1111' to file group: 'some-new-file-group'",
+				"add product file for file: 'some/path/some-new-file' to file group: 'some-new-file-group'",
+				"add file group: 'some-new-file-group'",
+				"product file - id: '1111' is already in file group: 'some-existing-file-group' - id: '4321'",
+				"add product file - id: '9876' to file group: 'some-existing-file-group' - id: '4321'",
+				"file group: 'some-existing-file-group' - id: '4321' is already added",
+			}))
+
+			Expect(pivnetClient.CreateFileGroupCallCount()).To(Equal(0))
+			Expect(pivnetClient.AddToFileGroupCallCount()).To(Equal(0))
+			Expect(pivnetClient.AddFileGroupCallCount()).To(Equal(0))
+		})
+
+		Context("when the release has file groups and product files which are not in the metadata", func() {
+			BeforeEach(func() {
+				pivnetClient.FileGroupsReturns([]pivnet.FileGroup{
+					{
+						ID:   4321,
+						Name: "some-existing-file-group",
+						ProductFiles: []pivnet.ProductFile{
+							{ID: 1111},
+							{ID: 5555, Name: "some-stale-file"},
+						},
+					},
+				}, nil)
+
+				pivnetClient.FileGroupsForReleaseReturns([]pivnet.FileGroup{
+					{ID: 4321},
+					{ID: 7777, Name: "some-old-file-group"},
+				}, nil)
+			})
+
+			It("plans removing them", func() {
+				releaseFileGroupsAdder := release.NewReleaseFileGroupsAdder(
+					fakeLogger,
+					pivnetClient,
+					&releasefakes.Journal{},
+					mdata,
+					"some-product-slug",
+				)
+
+				err := releaseFileGroupsAdder.PlanReleaseFileGroups(&plan, pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.FileGroups).To(ContainElement(
+					"remove product file: 'some-stale-file' - id: '5555' from file group: 'some-existing-file-group' - id: '4321'",
+				))
+				Expect(plan.FileGroups[len(plan.FileGroups)-1]).To(Equal(
+					"remove file group: 'some-old-file-group' - id: '7777'",
+				))
+
+				Expect(pivnetClient.RemoveFromFileGroupCallCount()).To(Equal(0))
+				Expect(pivnetClient.RemoveFileGroupCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the release does not exist yet", func() {
+			BeforeEach(func() {
+				pivnetRelease = pivnet.Release{Version: "some-version"}
+			})
+
+			It("does not look up the release product files or file groups", func() {
+				releaseFileGroupsAdder := release.NewReleaseFileGroupsAdder(
+					fakeLogger,
+					pivnetClient,
+					&releasefakes.Journal{},
+					mdata,
+					"some-product-slug",
+				)
+
+				err := releaseFileGroupsAdder.PlanReleaseFileGroups(&plan, pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.ProductFilesForReleaseCallCount()).To(Equal(0))
+				Expect(pivnetClient.FileGroupsForReleaseCallCount()).To(Equal(0))
+
+				Expect(plan.FileGroups).To(ContainElement(
+					"add product file for file: 'some/path/some-file' to file group: 'some-new-file-group'",
+				))
+				Expect(plan.FileGroups).To(ContainElement(
+					"add file group: 'some-existing-file-group' - id: '4321'",
+				))
+			})
+		})
+	})
+})
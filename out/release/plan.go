@@ -0,0 +1,147 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+// Plan describes what a put would do to the release, its product files,
+// dependencies, upgrade paths, file groups and user groups, for dry runs.
+type Plan struct {
+	Release      []string
+	ProductFiles []string
+	Dependencies []string
+	UpgradePaths []string
+	FileGroups   []string
+	UserGroups   []string
+}
+
+// String returns the plan one step per line, grouped by section.
+func (p Plan) String() string {
+	lines := []string{"Plan:"}
+	lines = append(lines, planLines("release", p.Release)...)
+	lines = append(lines, planLines("product files", p.ProductFiles)...)
+	lines = append(lines, planLines("dependencies", p.Dependencies)...)
+	lines = append(lines, planLines("upgrade paths", p.UpgradePaths)...)
+	lines = append(lines, planLines("file groups", p.FileGroups)...)
+	lines = append(lines, planLines("user groups", p.UserGroups)...)
+
+	return strings.Join(lines, "\n")
+}
+
+func planLines(heading string, items []string) []string {
+	if len(items) == 0 {
+		return []string{fmt.Sprintf("  %s: no changes", heading)}
+	}
+
+	return summaryLines(heading, items)
+}
+
+//go:generate counterfeiter --fake-name CreatePlanner . createPlanner
+type createPlanner interface {
+	PlanCreate(plan *Plan) (pivnet.Release, error)
+}
+
+//go:generate counterfeiter --fake-name UploadPlanner . uploadPlanner
+type uploadPlanner interface {
+	PlanUpload(plan *Plan, release pivnet.Release, exactGlobs []string) error
+}
+
+//go:generate counterfeiter --fake-name ReleaseDependenciesPlanner . releaseDependenciesPlanner
+type releaseDependenciesPlanner interface {
+	PlanReleaseDependencies(plan *Plan, release pivnet.Release) error
+}
+
+//go:generate counterfeiter --fake-name ReleaseUpgradePathsPlanner . releaseUpgradePathsPlanner
+type releaseUpgradePathsPlanner interface {
+	PlanReleaseUpgradePaths(plan *Plan, release pivnet.Release) error
+}
+
+//go:generate counterfeiter --fake-name ReleaseFileGroupsPlanner . releaseFileGroupsPlanner
+type releaseFileGroupsPlanner interface {
+	PlanReleaseFileGroups(plan *Plan, release pivnet.Release) error
+}
+
+//go:generate counterfeiter --fake-name UserGroupsPlanner . userGroupsPlanner
+type userGroupsPlanner interface {
+	PlanUserGroups(plan *Plan, release pivnet.Release) error
+}
+
+// Planner plans a put without making any changes to Pivnet or S3.
+type Planner struct {
+	creator           createPlanner
+	uploader          uploadPlanner
+	dependenciesAdder releaseDependenciesPlanner
+	upgradePathsAdder releaseUpgradePathsPlanner
+	fileGroupsAdder   releaseFileGroupsPlanner
+	userGroupsUpdater userGroupsPlanner
+	skipUpload        bool
+}
+
+func NewPlanner(
+	creator createPlanner,
+	uploader uploadPlanner,
+	dependenciesAdder releaseDependenciesPlanner,
+	upgradePathsAdder releaseUpgradePathsPlanner,
+	fileGroupsAdder releaseFileGroupsPlanner,
+	userGroupsUpdater userGroupsPlanner,
+	skipUpload bool,
+) Planner {
+	return Planner{
+		creator:           creator,
+		uploader:          uploader,
+		dependenciesAdder: dependenciesAdder,
+		upgradePathsAdder: upgradePathsAdder,
+		fileGroupsAdder:   fileGroupsAdder,
+		userGroupsUpdater: userGroupsUpdater,
+		skipUpload:        skipUpload,
+	}
+}
+
+// Plan runs the validation and lookups of a put for the files and returns
+// what the put would do.
+func (p Planner) Plan(exactGlobs []string) (string, error) {
+	var plan Plan
+
+	release, err := p.creator.PlanCreate(&plan)
+	if err != nil {
+		return "", err
+	}
+
+	if !p.skipUpload {
+		err = p.uploader.PlanUpload(&plan, release, exactGlobs)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = p.upgradePathsAdder.PlanReleaseUpgradePaths(&plan, release)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.dependenciesAdder.PlanReleaseDependencies(&plan, release)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.fileGroupsAdder.PlanReleaseFileGroups(&plan, release)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.userGroupsUpdater.PlanUserGroups(&plan, release)
+	if err != nil {
+		return "", err
+	}
+
+	return plan.String(), nil
+}
+
+// releaseExists returns false for a release which a put would create, and so
+// has nothing to diff against yet.
+func releaseExists(release pivnet.Release) bool {
+	return release.ID != 0
+}
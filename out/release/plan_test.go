@@ -0,0 +1,239 @@
+package release_test
+
+import (
+	"errors"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/out/release"
+	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plan", func() {
+	Describe("String", func() {
+		It("lists the steps of each section", func() {
+			plan := release.Plan{
+				Release:      []string{"create release: '1.2.3'"},
+				Dependencies: []string{"add dependent release - id: '1234'"},
+			}
+
+			Expect(plan.String()).To(Equal(`Plan:
+  release:
+  - create release: '1.2.3'
+  product files: no changes
+  dependencies:
+  - add dependent release - id: '1234'
+  upgrade paths: no changes
+  file groups: no changes
+  user groups: no changes`))
+		})
+	})
+})
+
+var _ = Describe("Planner", func() {
+	Describe("Plan", func() {
+		var (
+			creator           *releasefakes.CreatePlanner
+			uploader          *releasefakes.UploadPlanner
+			dependenciesAdder *releasefakes.ReleaseDependenciesPlanner
+			upgradePathsAdder *releasefakes.ReleaseUpgradePathsPlanner
+			fileGroupsAdder   *releasefakes.ReleaseFileGroupsPlanner
+			userGroupsUpdater *releasefakes.UserGroupsPlanner
+
+			skipUpload     bool
+			plannedRelease pivnet.Release
+			exactGlobs     []string
+
+			calls []string
+
+			planner release.Planner
+		)
+
+		BeforeEach(func() {
+			creator = &releasefakes.CreatePlanner{}
+			uploader = &releasefakes.UploadPlanner{}
+			dependenciesAdder = &releasefakes.ReleaseDependenciesPlanner{}
+			upgradePathsAdder = &releasefakes.ReleaseUpgradePathsPlanner{}
+			fileGroupsAdder = &releasefakes.ReleaseFileGroupsPlanner{}
+			userGroupsUpdater = &releasefakes.UserGroupsPlanner{}
+
+			skipUpload = false
+			plannedRelease = pivnet.Release{Version: "1.2.3"}
+			exactGlobs = []string{"some-glob"}
+
+			calls = nil
+
+			uploader.PlanUploadStub = func(plan *release.Plan, r pivnet.Release, globs []string) error {
+				calls = append(calls, "upload")
+				plan.ProductFiles = append(plan.ProductFiles, "upload file: 'some-file'")
+				return nil
+			}
+			upgradePathsAdder.PlanReleaseUpgradePathsStub = func(plan *release.Plan, r pivnet.Release) error {
+				calls = append(calls, "upgrade paths")
+				return nil
+			}
+			dependenciesAdder.PlanReleaseDependenciesStub = func(plan *release.Plan, r pivnet.Release) error {
+				calls = append(calls, "dependencies")
+				return nil
+			}
+			fileGroupsAdder.PlanReleaseFileGroupsStub = func(plan *release.Plan, r pivnet.Release) error {
+				calls = append(calls, "file groups")
+				return nil
+			}
+			userGroupsUpdater.PlanUserGroupsStub = func(plan *release.Plan, r pivnet.Release) error {
+				calls = append(calls, "user groups")
+				plan.UserGroups = append(plan.UserGroups, "update availability to: 'All Users'")
+				return nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			creator.PlanCreateStub = func(plan *release.Plan) (pivnet.Release, error) {
+				calls = append(calls, "create")
+				plan.Release = append(plan.Release, "create release: '1.2.3'")
+				return plannedRelease, nil
+			}
+
+			planner = release.NewPlanner(
+				creator,
+				uploader,
+				dependenciesAdder,
+				upgradePathsAdder,
+				fileGroupsAdder,
+				userGroupsUpdater,
+				skipUpload,
+			)
+		})
+
+		It("plans each step of the put in order", func() {
+			out, err := planner.Plan(exactGlobs)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(calls).To(Equal([]string{
+				"create",
+				"upload",
+				"upgrade paths",
+				"dependencies",
+				"file groups",
+				"user groups",
+			}))
+
+			_, _, invokedGlobs := uploader.PlanUploadArgsForCall(0)
+			Expect(invokedGlobs).To(Equal(exactGlobs))
+
+			Expect(out).To(Equal(`Plan:
+  release:
+  - create release: '1.2.3'
+  product files:
+  - upload file: 'some-file'
+  dependencies: no changes
+  upgrade paths: no changes
+  file groups: no changes
+  user groups:
+  - update availability to: 'All Users'`))
+		})
+
+		It("plans each step against the release which would be created", func() {
+			_, err := planner.Plan(exactGlobs)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, invokedRelease, _ := uploader.PlanUploadArgsForCall(0)
+			Expect(invokedRelease).To(Equal(plannedRelease))
+			_, invokedRelease = upgradePathsAdder.PlanReleaseUpgradePathsArgsForCall(0)
+			Expect(invokedRelease).To(Equal(plannedRelease))
+			_, invokedRelease = dependenciesAdder.PlanReleaseDependenciesArgsForCall(0)
+			Expect(invokedRelease).To(Equal(plannedRelease))
+			_, invokedRelease = fileGroupsAdder.PlanReleaseFileGroupsArgsForCall(0)
+			Expect(invokedRelease).To(Equal(plannedRelease))
+			_, invokedRelease = userGroupsUpdater.PlanUserGroupsArgsForCall(0)
+			Expect(invokedRelease).To(Equal(plannedRelease))
+		})
+
+		Context("when the release already exists", func() {
+			BeforeEach(func() {
+				plannedRelease = pivnet.Release{ID: 1234, Version: "1.2.3"}
+			})
+
+			It("plans each step against the existing release", func() {
+				_, err := planner.Plan(exactGlobs)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, invokedRelease, _ := uploader.PlanUploadArgsForCall(0)
+				Expect(invokedRelease.ID).To(Equal(1234))
+				_, invokedRelease = upgradePathsAdder.PlanReleaseUpgradePathsArgsForCall(0)
+				Expect(invokedRelease.ID).To(Equal(1234))
+				_, invokedRelease = dependenciesAdder.PlanReleaseDependenciesArgsForCall(0)
+				Expect(invokedRelease.ID).To(Equal(1234))
+				_, invokedRelease = fileGroupsAdder.PlanReleaseFileGroupsArgsForCall(0)
+				Expect(invokedRelease.ID).To(Equal(1234))
+				_, invokedRelease = userGroupsUpdater.PlanUserGroupsArgsForCall(0)
+				Expect(invokedRelease.ID).To(Equal(1234))
+			})
+		})
+
+		Context("when skip upload is set", func() {
+			BeforeEach(func() {
+				skipUpload = true
+			})
+
+			It("does not plan the upload", func() {
+				out, err := planner.Plan(exactGlobs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploader.PlanUploadCallCount()).To(Equal(0))
+				Expect(calls).To(Equal([]string{
+					"create",
+					"upgrade paths",
+					"dependencies",
+					"file groups",
+					"user groups",
+				}))
+				Expect(out).To(ContainSubstring("product files: no changes"))
+			})
+		})
+
+		Context("when planning the release returns an error", func() {
+			var (
+				expectedErr error
+			)
+
+			BeforeEach(func() {
+				expectedErr = errors.New("some create error")
+			})
+
+			JustBeforeEach(func() {
+				creator.PlanCreateStub = nil
+				creator.PlanCreateReturns(pivnet.Release{}, expectedErr)
+			})
+
+			It("returns the error without planning the other steps", func() {
+				_, err := planner.Plan(exactGlobs)
+				Expect(err).To(Equal(expectedErr))
+
+				Expect(uploader.PlanUploadCallCount()).To(Equal(0))
+				Expect(userGroupsUpdater.PlanUserGroupsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when planning a later step returns an error", func() {
+			var (
+				expectedErr error
+			)
+
+			BeforeEach(func() {
+				expectedErr = errors.New("some file groups error")
+				fileGroupsAdder.PlanReleaseFileGroupsStub = nil
+				fileGroupsAdder.PlanReleaseFileGroupsReturns(expectedErr)
+			})
+
+			It("returns the error without planning the remaining steps", func() {
+				_, err := planner.Plan(exactGlobs)
+				Expect(err).To(Equal(expectedErr))
+
+				Expect(userGroupsUpdater.PlanUserGroupsCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
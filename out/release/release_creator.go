@@ -15,6 +15,7 @@ import (
 type ReleaseCreator struct {
 	pivnet          releaseClient
 	semverConverter semverConverter
+	journal         journal
 	logger          logger.Logger
 	metadata        metadata.Metadata
 	sourcesDir      string
@@ -29,17 +30,20 @@ type releaseClient interface {
 	ReleaseTypes() ([]pivnet.ReleaseType, error)
 	ReleasesForProductSlug(string) ([]pivnet.Release, error)
 	CreateRelease(pivnet.CreateReleaseConfig) (pivnet.Release, error)
+	UpdateReleaseMetadata(productSlug string, release pivnet.Release) (pivnet.Release, error)
 	DeleteRelease(productSlug string, release pivnet.Release) error
 }
 
 //go:generate counterfeiter --fake-name FakeSemverConverter . semverConverter
 type semverConverter interface {
 	ToValidSemver(string) (semver.Version, error)
+	MatchesConstraint(input string, constraint string) (bool, error)
 }
 
 func NewReleaseCreator(
 	pivnet releaseClient,
 	semverConverter semverConverter,
+	journal journal,
 	logger logger.Logger,
 	metadata metadata.Metadata,
 	params concourse.OutParams,
@@ -50,6 +54,7 @@ func NewReleaseCreator(
 	return ReleaseCreator{
 		pivnet:          pivnet,
 		semverConverter: semverConverter,
+		journal:         journal,
 		logger:          logger,
 		metadata:        metadata,
 		sourcesDir:      sourcesDir,
@@ -60,12 +65,151 @@ func NewReleaseCreator(
 }
 
 func (rc ReleaseCreator) Create() (pivnet.Release, error) {
+	releaseType, eulaSlug, err := rc.validate()
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	version := rc.metadata.Release.Version
+
+	releases, err := rc.pivnet.ReleasesForProductSlug(rc.productSlug)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	for _, r := range releases {
+		if r.Version != version {
+			continue
+		}
+
+		switch rc.params.OnExistingRelease {
+		case concourse.OnExistingReleaseFail:
+			return pivnet.Release{}, existingReleaseError(r)
+		case concourse.OnExistingReleaseUpdate:
+			return rc.update(r, releaseType, eulaSlug)
+		}
+
+		rc.logger.Info(fmt.Sprintf(
+			"Deleting existing release: '%s' - id: '%d'",
+			r.Version,
+			r.ID,
+		))
+
+		err := rc.pivnet.DeleteRelease(rc.productSlug, r)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+
+		rc.journal.RecordIrreversible(fmt.Sprintf(
+			"deleted existing release: '%s' - id: '%d'",
+			r.Version,
+			r.ID,
+		))
+	}
+
+	config := pivnet.CreateReleaseConfig{
+		ProductSlug:           rc.productSlug,
+		ReleaseType:           string(releaseType),
+		EULASlug:              eulaSlug,
+		Version:               version,
+		Description:           rc.metadata.Release.Description,
+		ReleaseNotesURL:       rc.metadata.Release.ReleaseNotesURL,
+		ReleaseDate:           rc.metadata.Release.ReleaseDate,
+		Controlled:            rc.metadata.Release.Controlled,
+		ECCN:                  rc.metadata.Release.ECCN,
+		LicenseException:      rc.metadata.Release.LicenseException,
+		EndOfSupportDate:      rc.metadata.Release.EndOfSupportDate,
+		EndOfGuidanceDate:     rc.metadata.Release.EndOfGuidanceDate,
+		EndOfAvailabilityDate: rc.metadata.Release.EndOfAvailabilityDate,
+	}
+
+	rc.logger.Info(fmt.Sprintf("Creating new release with config: %+v", config))
+	release, err := rc.pivnet.CreateRelease(config)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	rc.logger.Info(fmt.Sprintf("Created new release with ID: %d", release.ID))
+
+	rc.journal.Record(
+		fmt.Sprintf("created release: '%s' - id: '%d'", release.Version, release.ID),
+		func() error {
+			return rc.pivnet.DeleteRelease(rc.productSlug, release)
+		},
+	)
+
+	return release, nil
+}
+
+// PlanCreate runs the same validation and lookups as Create, without changing
+// anything, and adds what Create would do to the plan. It returns the existing
+// release if it would be updated in place, otherwise a release with only the
+// version set.
+func (rc ReleaseCreator) PlanCreate(plan *Plan) (pivnet.Release, error) {
+	releaseType, eulaSlug, err := rc.validate()
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	version := rc.metadata.Release.Version
+
+	releases, err := rc.pivnet.ReleasesForProductSlug(rc.productSlug)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	for _, r := range releases {
+		if r.Version != version {
+			continue
+		}
+
+		switch rc.params.OnExistingRelease {
+		case concourse.OnExistingReleaseFail:
+			return pivnet.Release{}, existingReleaseError(r)
+		case concourse.OnExistingReleaseUpdate:
+			if releaseUpToDate(r, rc.releaseUpdate(r, releaseType, eulaSlug)) {
+				plan.Release = append(plan.Release, fmt.Sprintf(
+					"existing release: '%s' - id: '%d' is up to date",
+					r.Version,
+					r.ID,
+				))
+			} else {
+				plan.Release = append(plan.Release, fmt.Sprintf(
+					"update existing release: '%s' - id: '%d'",
+					r.Version,
+					r.ID,
+				))
+			}
+
+			return r, nil
+		}
+
+		plan.Release = append(plan.Release, fmt.Sprintf(
+			"delete existing release: '%s' - id: '%d'",
+			r.Version,
+			r.ID,
+		))
+	}
+
+	plan.Release = append(plan.Release, fmt.Sprintf(
+		"create release: '%s' - release type: '%s' - EULA: '%s'",
+		version,
+		releaseType,
+		eulaSlug,
+	))
+
+	return pivnet.Release{Version: version}, nil
+}
+
+// validate checks the version, EULA and release type in the metadata against
+// the source and Pivnet, returning the release type and EULA slug.
+func (rc ReleaseCreator) validate() (pivnet.ReleaseType, string, error) {
 	version := rc.metadata.Release.Version
 
 	if rc.source.SortBy == concourse.SortBySemver {
 		v, err := rc.semverConverter.ToValidSemver(version)
 		if err != nil {
-			return pivnet.Release{}, err
+			return "", "", err
 		}
 		rc.logger.Info(fmt.Sprintf("Successfully parsed semver as: '%s'", v.String()))
 	}
@@ -79,11 +223,11 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 
 		match, err := regexp.MatchString(rc.source.ProductVersion, version)
 		if err != nil {
-			return pivnet.Release{}, err
+			return "", "", err
 		}
 
 		if !match {
-			return pivnet.Release{}, fmt.Errorf(
+			return "", "", fmt.Errorf(
 				"provided product version: '%s' does not match regex in source: '%s'",
 				version,
 				rc.source.ProductVersion,
@@ -91,13 +235,34 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 		}
 	}
 
+	if rc.source.ProductVersionConstraint != "" {
+		rc.logger.Info(fmt.Sprintf(
+			"Validating product version: '%s' against constraint: '%s'",
+			version,
+			rc.source.ProductVersionConstraint,
+		))
+
+		match, err := rc.semverConverter.MatchesConstraint(version, rc.source.ProductVersionConstraint)
+		if err != nil {
+			return "", "", err
+		}
+
+		if !match {
+			return "", "", fmt.Errorf(
+				"provided product version: '%s' does not satisfy constraint in source: '%s'",
+				version,
+				rc.source.ProductVersionConstraint,
+			)
+		}
+	}
+
 	eulaSlug := rc.metadata.Release.EULASlug
 
 	rc.logger.Info(fmt.Sprintf("Validating EULA: '%s'", eulaSlug))
 
 	eulas, err := rc.pivnet.EULAs()
 	if err != nil {
-		return pivnet.Release{}, err
+		return "", "", err
 	}
 
 	eulaSlugs := make([]string, len(eulas))
@@ -115,7 +280,7 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 
 	if !containsSlug {
 		eulaSlugsPrintable := fmt.Sprintf("['%s']", strings.Join(eulaSlugs, "', '"))
-		return pivnet.Release{}, fmt.Errorf(
+		return "", "", fmt.Errorf(
 			"provided EULA slug: '%s' must be one of: %s",
 			eulaSlug,
 			eulaSlugsPrintable,
@@ -128,7 +293,7 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 
 	releaseTypes, err := rc.pivnet.ReleaseTypes()
 	if err != nil {
-		return pivnet.Release{}, err
+		return "", "", err
 	}
 
 	releaseTypesAsStrings := make([]string, len(releaseTypes))
@@ -149,7 +314,7 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 			"['%s']",
 			strings.Join(releaseTypesAsStrings, "', '"),
 		)
-		return pivnet.Release{}, fmt.Errorf(
+		return "", "", fmt.Errorf(
 			"provided release type: '%s' must be one of: %s",
 			releaseType,
 			releaseTypesPrintable,
@@ -158,38 +323,75 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 
 	if pivnet.ReleaseType(rc.source.ReleaseType) != "" &&
 		pivnet.ReleaseType(rc.source.ReleaseType) != releaseType {
-		return pivnet.Release{}, fmt.Errorf(
+		return "", "", fmt.Errorf(
 			"provided release type: '%s' must match '%s' from source configuration",
 			releaseType,
 			rc.source.ReleaseType,
 		)
 	}
 
-	releases, err := rc.pivnet.ReleasesForProductSlug(rc.productSlug)
+	return releaseType, eulaSlug, nil
+}
+
+func existingReleaseError(r pivnet.Release) error {
+	return fmt.Errorf(
+		"release: '%s' already exists - id: '%d'",
+		r.Version,
+		r.ID,
+	)
+}
+
+// update updates the metadata of the existing release in place, preserving
+// its upgrade paths, user groups and download history. Attributes which the
+// metadata leaves empty are cleared. Availability is left to the user groups
+// updater.
+func (rc ReleaseCreator) update(
+	existing pivnet.Release,
+	releaseType pivnet.ReleaseType,
+	eulaSlug string,
+) (pivnet.Release, error) {
+	releaseUpdate := rc.releaseUpdate(existing, releaseType, eulaSlug)
+
+	if releaseUpToDate(existing, releaseUpdate) {
+		rc.logger.Info(fmt.Sprintf(
+			"Existing release: '%s' - id: '%d' is already up to date",
+			existing.Version,
+			existing.ID,
+		))
+		return existing, nil
+	}
+
+	rc.logger.Info(fmt.Sprintf(
+		"Updating existing release: '%s' - id: '%d'",
+		existing.Version,
+		existing.ID,
+	))
+
+	release, err := rc.pivnet.UpdateReleaseMetadata(rc.productSlug, releaseUpdate)
 	if err != nil {
 		return pivnet.Release{}, err
 	}
 
-	for _, r := range releases {
-		if r.Version == version {
-			rc.logger.Info(fmt.Sprintf(
-				"Deleting existing release: '%s' - id: '%d'",
-				r.Version,
-				r.ID,
-			))
-
-			err := rc.pivnet.DeleteRelease(rc.productSlug, r)
-			if err != nil {
-				return pivnet.Release{}, err
-			}
-		}
-	}
+	rc.journal.RecordIrreversible(fmt.Sprintf(
+		"updated existing release: '%s' - id: '%d'",
+		existing.Version,
+		existing.ID,
+	))
 
-	config := pivnet.CreateReleaseConfig{
-		ProductSlug:           rc.productSlug,
-		ReleaseType:           string(releaseType),
-		EULASlug:              eulaSlug,
-		Version:               version,
+	return release, nil
+}
+
+// releaseUpdate returns the existing release with the metadata applied.
+func (rc ReleaseCreator) releaseUpdate(
+	existing pivnet.Release,
+	releaseType pivnet.ReleaseType,
+	eulaSlug string,
+) pivnet.Release {
+	return pivnet.Release{
+		ID:                    existing.ID,
+		Version:               existing.Version,
+		ReleaseType:           releaseType,
+		EULA:                  &pivnet.EULA{Slug: eulaSlug},
 		Description:           rc.metadata.Release.Description,
 		ReleaseNotesURL:       rc.metadata.Release.ReleaseNotesURL,
 		ReleaseDate:           rc.metadata.Release.ReleaseDate,
@@ -200,13 +402,23 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 		EndOfGuidanceDate:     rc.metadata.Release.EndOfGuidanceDate,
 		EndOfAvailabilityDate: rc.metadata.Release.EndOfAvailabilityDate,
 	}
+}
 
-	rc.logger.Info(fmt.Sprintf("Creating new release with config: %+v", config))
-	release, err := rc.pivnet.CreateRelease(config)
-	if err != nil {
-		return pivnet.Release{}, err
+// releaseUpToDate returns true if the existing release already has the
+// attributes of the update, so that updating it can be skipped.
+func releaseUpToDate(existing pivnet.Release, update pivnet.Release) bool {
+	if existing.EULA == nil || existing.EULA.Slug != update.EULA.Slug {
+		return false
 	}
 
-	rc.logger.Info(fmt.Sprintf("Created new release with ID: %d", release.ID))
-	return release, nil
+	return existing.ReleaseType == update.ReleaseType &&
+		existing.Description == update.Description &&
+		existing.ReleaseNotesURL == update.ReleaseNotesURL &&
+		existing.ReleaseDate == update.ReleaseDate &&
+		existing.Controlled == update.Controlled &&
+		existing.ECCN == update.ECCN &&
+		existing.LicenseException == update.LicenseException &&
+		existing.EndOfSupportDate == update.EndOfSupportDate &&
+		existing.EndOfGuidanceDate == update.EndOfGuidanceDate &&
+		existing.EndOfAvailabilityDate == update.EndOfAvailabilityDate
 }
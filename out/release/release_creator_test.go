@@ -24,17 +24,20 @@ var _ = Describe("ReleaseCreator", func() {
 
 		pivnetClient        *releasefakes.ReleaseClient
 		fakeSemverConverter *releasefakes.FakeSemverConverter
+		fakeJournal         *releasefakes.Journal
 
 		creator release.ReleaseCreator
 
 		sourceReleaseType string
 		sourceVersion     string
+		sourceConstraint  string
 		sortBy            concourse.SortBy
 		releaseVersion    string
 		existingReleases  []pivnet.Release
 		eulaSlug          string
 		productSlug       string
 		releaseType       pivnet.ReleaseType
+		onExistingRelease concourse.OnExistingRelease
 	)
 
 	BeforeEach(func() {
@@ -43,6 +46,7 @@ var _ = Describe("ReleaseCreator", func() {
 
 		pivnetClient = &releasefakes.ReleaseClient{}
 		fakeSemverConverter = &releasefakes.FakeSemverConverter{}
+		fakeJournal = &releasefakes.Journal{}
 
 		sortBy = concourse.SortByNone
 
@@ -60,6 +64,8 @@ var _ = Describe("ReleaseCreator", func() {
 
 		sourceReleaseType = string(releaseType)
 		sourceVersion = `1\.8\..*`
+		sourceConstraint = ""
+		onExistingRelease = ""
 
 		pivnetClient.EULAsReturns([]pivnet.EULA{{Slug: eulaSlug}}, nil)
 		pivnetClient.ReleaseTypesReturns([]pivnet.ReleaseType{releaseType}, nil)
@@ -67,46 +73,51 @@ var _ = Describe("ReleaseCreator", func() {
 		pivnetClient.CreateReleaseReturns(pivnet.Release{ID: 1337}, nil)
 	})
 
-	Describe("Create", func() {
-		JustBeforeEach(func() {
-			meta := metadata.Metadata{
-				Release: &metadata.Release{
-					Controlled:      true,
-					EULASlug:        eulaSlug,
-					ReleaseType:     string(releaseType),
-					Version:         releaseVersion,
-					Description:     "wow, a description",
-					ReleaseNotesURL: "some-url",
-					ReleaseDate:     "1/17/2016",
-				},
-				ProductFiles: []metadata.ProductFile{
-					{
-						File:        "some/file",
-						Description: "a description",
-						UploadAs:    "a file",
-					},
+	JustBeforeEach(func() {
+		meta := metadata.Metadata{
+			Release: &metadata.Release{
+				Controlled:      true,
+				EULASlug:        eulaSlug,
+				ReleaseType:     string(releaseType),
+				Version:         releaseVersion,
+				Description:     "wow, a description",
+				ReleaseNotesURL: "some-url",
+				ReleaseDate:     "1/17/2016",
+			},
+			ProductFiles: []metadata.ProductFile{
+				{
+					File:        "some/file",
+					Description: "a description",
+					UploadAs:    "a file",
 				},
-			}
-
-			params := concourse.OutParams{}
-
-			source := concourse.Source{
-				ReleaseType:    sourceReleaseType,
-				ProductVersion: sourceVersion,
-				SortBy:         sortBy,
-			}
-
-			creator = release.NewReleaseCreator(
-				pivnetClient,
-				fakeSemverConverter,
-				fakeLogger,
-				meta,
-				params,
-				source,
-				"/some/sources/dir",
-				productSlug,
-			)
-		})
+			},
+		}
+
+		params := concourse.OutParams{
+			OnExistingRelease: onExistingRelease,
+		}
+
+		source := concourse.Source{
+			ReleaseType:              sourceReleaseType,
+			ProductVersion:           sourceVersion,
+			ProductVersionConstraint: sourceConstraint,
+			SortBy:                   sortBy,
+		}
+
+		creator = release.NewReleaseCreator(
+			pivnetClient,
+			fakeSemverConverter,
+			fakeJournal,
+			fakeLogger,
+			meta,
+			params,
+			source,
+			"/some/sources/dir",
+			productSlug,
+		)
+	})
+
+	Describe("Create", func() {
 
 		It("constructs the release", func() {
 			r, err := creator.Create()
@@ -130,6 +141,25 @@ var _ = Describe("ReleaseCreator", func() {
 			}))
 		})
 
+		It("records deleting the created release", func() {
+			r, err := creator.Create()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeJournal.RecordCallCount()).To(Equal(1))
+
+			description, undo := fakeJournal.RecordArgsForCall(0)
+			Expect(description).To(ContainSubstring("1337"))
+
+			err = undo()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pivnetClient.DeleteReleaseCallCount()).To(Equal(1))
+
+			invokedProductSlug, invokedRelease := pivnetClient.DeleteReleaseArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal(productSlug))
+			Expect(invokedRelease).To(Equal(r))
+		})
+
 		Context("when an error occurs", func() {
 			Context("when pivnet fails getting releases for a product slug", func() {
 				BeforeEach(func() {
@@ -214,6 +244,117 @@ var _ = Describe("ReleaseCreator", func() {
 				Expect(invokedRelease).To(Equal(existingReleases[0]))
 			})
 
+			It("records the deletion as irreversible", func() {
+				_, err := creator.Create()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeJournal.RecordIrreversibleCallCount()).To(Equal(1))
+				Expect(fakeJournal.RecordIrreversibleArgsForCall(0)).To(ContainSubstring(existingReleases[0].Version))
+			})
+
+			Context("when the existing release should fail the put", func() {
+				BeforeEach(func() {
+					onExistingRelease = concourse.OnExistingReleaseFail
+				})
+
+				It("returns an error without deleting or creating a release", func() {
+					_, err := creator.Create()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("already exists"))
+
+					Expect(pivnetClient.DeleteReleaseCallCount()).To(Equal(0))
+					Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the existing release should be updated", func() {
+				BeforeEach(func() {
+					onExistingRelease = concourse.OnExistingReleaseUpdate
+					pivnetClient.UpdateReleaseMetadataReturns(pivnet.Release{ID: 1234, Version: "1.8.1"}, nil)
+				})
+
+				It("updates the release in place", func() {
+					r, err := creator.Create()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(r).To(Equal(pivnet.Release{ID: 1234, Version: "1.8.1"}))
+
+					Expect(pivnetClient.DeleteReleaseCallCount()).To(Equal(0))
+					Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(0))
+
+					Expect(pivnetClient.UpdateReleaseMetadataCallCount()).To(Equal(1))
+					invokedProductSlug, invokedRelease := pivnetClient.UpdateReleaseMetadataArgsForCall(0)
+					Expect(invokedProductSlug).To(Equal(productSlug))
+					Expect(invokedRelease.ID).To(Equal(existingReleases[0].ID))
+					Expect(invokedRelease.ReleaseType).To(Equal(releaseType))
+					Expect(invokedRelease.EULA.Slug).To(Equal(eulaSlug))
+					Expect(invokedRelease.Description).To(Equal("wow, a description"))
+					Expect(invokedRelease.ReleaseNotesURL).To(Equal("some-url"))
+					Expect(invokedRelease.Controlled).To(BeTrue())
+				})
+
+				It("does not record deleting the release", func() {
+					_, err := creator.Create()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeJournal.RecordCallCount()).To(Equal(0))
+					Expect(fakeJournal.RecordIrreversibleCallCount()).To(Equal(1))
+				})
+
+				Context("when the existing release is already up to date", func() {
+					BeforeEach(func() {
+						existingReleases[0].ReleaseType = releaseType
+						existingReleases[0].EULA = &pivnet.EULA{Slug: eulaSlug}
+						existingReleases[0].Description = "wow, a description"
+						existingReleases[0].ReleaseNotesURL = "some-url"
+						existingReleases[0].ReleaseDate = "1/17/2016"
+						existingReleases[0].Controlled = true
+					})
+
+					It("returns the existing release without updating it", func() {
+						r, err := creator.Create()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(r).To(Equal(existingReleases[0]))
+
+						Expect(pivnetClient.UpdateReleaseMetadataCallCount()).To(Equal(0))
+						Expect(fakeJournal.RecordIrreversibleCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the metadata no longer provides some attributes of the existing release", func() {
+					BeforeEach(func() {
+						existingReleases[0].ReleaseType = releaseType
+						existingReleases[0].EULA = &pivnet.EULA{Slug: eulaSlug}
+						existingReleases[0].Description = "wow, a description"
+						existingReleases[0].ReleaseNotesURL = "some-url"
+						existingReleases[0].ReleaseDate = "1/17/2016"
+						existingReleases[0].Controlled = true
+						existingReleases[0].ECCN = "5D002"
+						existingReleases[0].EndOfSupportDate = "2017-01-17"
+					})
+
+					It("updates the release to clear them", func() {
+						_, err := creator.Create()
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(pivnetClient.UpdateReleaseMetadataCallCount()).To(Equal(1))
+						_, invokedRelease := pivnetClient.UpdateReleaseMetadataArgsForCall(0)
+						Expect(invokedRelease.ECCN).To(BeEmpty())
+						Expect(invokedRelease.EndOfSupportDate).To(BeEmpty())
+					})
+				})
+
+				Context("when updating the release returns an error", func() {
+					BeforeEach(func() {
+						pivnetClient.UpdateReleaseMetadataReturns(pivnet.Release{}, errors.New("update error"))
+					})
+
+					It("returns the error", func() {
+						_, err := creator.Create()
+						Expect(err).To(MatchError("update error"))
+					})
+				})
+			})
+
 			Context("when deleting the release returns an error", func() {
 				var (
 					expectedErr error
@@ -255,6 +396,53 @@ var _ = Describe("ReleaseCreator", func() {
 			})
 		})
 
+		Context("when a product version constraint is provided", func() {
+			BeforeEach(func() {
+				sourceConstraint = ">=1.8.0 <1.9.0"
+				fakeSemverConverter.MatchesConstraintReturns(true, nil)
+			})
+
+			It("validates the release version against the constraint", func() {
+				_, err := creator.Create()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSemverConverter.MatchesConstraintCallCount()).To(Equal(1))
+				invokedVersion, invokedConstraint := fakeSemverConverter.MatchesConstraintArgsForCall(0)
+				Expect(invokedVersion).To(Equal(releaseVersion))
+				Expect(invokedConstraint).To(Equal(sourceConstraint))
+			})
+
+			Context("when release version does not satisfy the constraint", func() {
+				BeforeEach(func() {
+					fakeSemverConverter.MatchesConstraintReturns(false, nil)
+				})
+
+				It("returns an error", func() {
+					_, err := creator.Create()
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("does not satisfy constraint"))
+					Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the constraint is invalid", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("constraint parse error")
+					fakeSemverConverter.MatchesConstraintReturns(false, expectedErr)
+				})
+
+				It("returns an error", func() {
+					_, err := creator.Create()
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+		})
+
 		Context("when release type does not match source config", func() {
 			BeforeEach(func() {
 				sourceReleaseType = "different release type"
@@ -292,4 +480,83 @@ var _ = Describe("ReleaseCreator", func() {
 			})
 		})
 	})
+
+	Describe("PlanCreate", func() {
+		var (
+			plan release.Plan
+		)
+
+		BeforeEach(func() {
+			plan = release.Plan{}
+		})
+
+		It("plans creating the release without changing anything", func() {
+			r, err := creator.PlanCreate(&plan)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r).To(Equal(pivnet.Release{Version: releaseVersion}))
+			Expect(plan.Release).To(Equal([]string{
+				"create release: '1.8.3' - release type: 'some-release-type' - EULA: 'magic-slug'",
+			}))
+
+			Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(0))
+		})
+
+		Context("when the release already exists", func() {
+			BeforeEach(func() {
+				releaseVersion = existingReleases[0].Version
+			})
+
+			It("plans deleting and recreating it", func() {
+				_, err := creator.PlanCreate(&plan)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.Release).To(HaveLen(2))
+				Expect(plan.Release[0]).To(Equal("delete existing release: '1.8.1' - id: '1234'"))
+
+				Expect(pivnetClient.DeleteReleaseCallCount()).To(Equal(0))
+				Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(0))
+			})
+
+			Context("when the existing release should be updated", func() {
+				BeforeEach(func() {
+					onExistingRelease = concourse.OnExistingReleaseUpdate
+				})
+
+				It("plans updating it and returns it", func() {
+					r, err := creator.PlanCreate(&plan)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(r).To(Equal(existingReleases[0]))
+					Expect(plan.Release).To(Equal([]string{
+						"update existing release: '1.8.1' - id: '1234'",
+					}))
+
+					Expect(pivnetClient.UpdateReleaseMetadataCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the put should fail", func() {
+				BeforeEach(func() {
+					onExistingRelease = concourse.OnExistingReleaseFail
+				})
+
+				It("returns an error", func() {
+					_, err := creator.PlanCreate(&plan)
+					Expect(err).To(MatchError(ContainSubstring("already exists")))
+				})
+			})
+		})
+
+		Context("when the EULA is invalid", func() {
+			BeforeEach(func() {
+				pivnetClient.EULAsReturns([]pivnet.EULA{{Slug: "some-other-slug"}}, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := creator.PlanCreate(&plan)
+				Expect(err).To(MatchError(ContainSubstring("EULA")))
+			})
+		})
+	})
 })
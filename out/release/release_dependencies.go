@@ -11,6 +11,7 @@ import (
 type ReleaseDependenciesAdder struct {
 	logger      logger.Logger
 	pivnet      releaseDependenciesAdderClient
+	journal     journal
 	metadata    metadata.Metadata
 	productSlug string
 }
@@ -18,12 +19,14 @@ type ReleaseDependenciesAdder struct {
 func NewReleaseDependenciesAdder(
 	logger logger.Logger,
 	pivnetClient releaseDependenciesAdderClient,
+	journal journal,
 	metadata metadata.Metadata,
 	productSlug string,
 ) ReleaseDependenciesAdder {
 	return ReleaseDependenciesAdder{
 		logger:      logger,
 		pivnet:      pivnetClient,
+		journal:     journal,
 		metadata:    metadata,
 		productSlug: productSlug,
 	}
@@ -31,16 +34,171 @@ func NewReleaseDependenciesAdder(
 
 //go:generate counterfeiter --fake-name ReleaseDependenciesAdderClient . releaseDependenciesAdderClient
 type releaseDependenciesAdderClient interface {
+	ReleaseDependencies(productSlug string, releaseID int) ([]pivnet.ReleaseDependency, error)
 	AddReleaseDependency(productSlug string, releaseID int, dependentReleaseID int) error
+	RemoveReleaseDependency(productSlug string, releaseID int, dependentReleaseID int) error
 	GetRelease(productSlug string, releaseVersion string) (pivnet.Release, error)
 }
 
+// AddReleaseDependencies adds the dependencies in the metadata which the
+// release is missing. If the metadata provides the dependencies, any others
+// are removed, leaving an existing release which is updated in place with
+// exactly the dependencies in the metadata.
 func (rf ReleaseDependenciesAdder) AddReleaseDependencies(release pivnet.Release) error {
+	dependentReleaseIDs, err := rf.dependentReleaseIDs()
+	if err != nil {
+		return err
+	}
+
+	existingDependencies, err := rf.pivnet.ReleaseDependencies(rf.productSlug, release.ID)
+	if err != nil {
+		return err
+	}
+
+	existing := map[int]bool{}
+	for _, d := range existingDependencies {
+		existing[d.Release.ID] = true
+	}
+
+	desired := map[int]bool{}
+
+	for _, dependentReleaseID := range dependentReleaseIDs {
+		desired[dependentReleaseID] = true
+
+		if existing[dependentReleaseID] {
+			rf.logger.Info(fmt.Sprintf(
+				"Dependent release with ID: %d already added",
+				dependentReleaseID,
+			))
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf(
+			"Adding dependent release with ID: %d",
+			dependentReleaseID,
+		))
+		err = rf.pivnet.AddReleaseDependency(rf.productSlug, release.ID, dependentReleaseID)
+		if err != nil {
+			return err
+		}
+
+		id := dependentReleaseID
+		rf.journal.Record(
+			fmt.Sprintf("added dependent release with ID: %d", id),
+			func() error {
+				return rf.pivnet.RemoveReleaseDependency(rf.productSlug, release.ID, id)
+			},
+		)
+	}
+
+	if rf.metadata.Dependencies == nil {
+		rf.logger.Info("No dependencies provided in metadata - not removing existing dependencies")
+		return nil
+	}
+
+	for _, d := range existingDependencies {
+		if desired[d.Release.ID] {
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf(
+			"Removing dependent release with ID: %d",
+			d.Release.ID,
+		))
+		err := rf.pivnet.RemoveReleaseDependency(rf.productSlug, release.ID, d.Release.ID)
+		if err != nil {
+			return err
+		}
+
+		id := d.Release.ID
+		rf.journal.Record(
+			fmt.Sprintf("removed dependent release with ID: %d", id),
+			func() error {
+				return rf.pivnet.AddReleaseDependency(rf.productSlug, release.ID, id)
+			},
+		)
+	}
+
+	return nil
+}
+
+// PlanReleaseDependencies resolves the dependencies in the metadata, without
+// changing anything, and adds what AddReleaseDependencies would do to the
+// plan.
+func (rf ReleaseDependenciesAdder) PlanReleaseDependencies(plan *Plan, release pivnet.Release) error {
+	dependentReleaseIDs, err := rf.dependentReleaseIDs()
+	if err != nil {
+		return err
+	}
+
+	var existingDependencies []pivnet.ReleaseDependency
+	if releaseExists(release) {
+		existingDependencies, err = rf.pivnet.ReleaseDependencies(rf.productSlug, release.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	existing := map[int]bool{}
+	for _, d := range existingDependencies {
+		existing[d.Release.ID] = true
+	}
+
+	desired := map[int]bool{}
+
+	for i, dependentReleaseID := range dependentReleaseIDs {
+		desired[dependentReleaseID] = true
+
+		d := rf.metadata.Dependencies[i].Release
+
+		name := fmt.Sprintf("dependent release - id: '%d'", dependentReleaseID)
+		if d.Product.Slug != "" && d.Version != "" {
+			name = fmt.Sprintf(
+				"dependent release: '%s/%s' - id: '%d'",
+				d.Product.Slug,
+				d.Version,
+				dependentReleaseID,
+			)
+		}
+
+		if existing[dependentReleaseID] {
+			plan.Dependencies = append(plan.Dependencies, fmt.Sprintf("%s is already added", name))
+			continue
+		}
+
+		plan.Dependencies = append(plan.Dependencies, fmt.Sprintf("add %s", name))
+	}
+
+	if rf.metadata.Dependencies == nil {
+		return nil
+	}
+
+	for _, d := range existingDependencies {
+		if desired[d.Release.ID] {
+			continue
+		}
+
+		plan.Dependencies = append(plan.Dependencies, fmt.Sprintf(
+			"remove dependent release: '%s/%s' - id: '%d'",
+			d.Release.Product.Slug,
+			d.Release.Version,
+			d.Release.ID,
+		))
+	}
+
+	return nil
+}
+
+// dependentReleaseIDs returns the IDs of the dependencies in the metadata, in
+// order, looking up those which are given by product slug and version.
+func (rf ReleaseDependenciesAdder) dependentReleaseIDs() ([]int, error) {
+	var ids []int
+
 	for i, d := range rf.metadata.Dependencies {
 		dependentReleaseID := d.Release.ID
 		if dependentReleaseID == 0 {
 			if d.Release.Version == "" || d.Release.Product.Slug == "" {
-				return fmt.Errorf(
+				return nil, fmt.Errorf(
 					"Either ReleaseID or release version and product slug must be provided for dependencies[%d]",
 					i,
 				)
@@ -53,20 +211,13 @@ func (rf ReleaseDependenciesAdder) AddReleaseDependencies(release pivnet.Release
 			))
 			r, err := rf.pivnet.GetRelease(d.Release.Product.Slug, d.Release.Version)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			dependentReleaseID = r.ID
 		}
 
-		rf.logger.Info(fmt.Sprintf(
-			"Adding dependent release with ID: %d",
-			dependentReleaseID,
-		))
-		err := rf.pivnet.AddReleaseDependency(rf.productSlug, release.ID, dependentReleaseID)
-		if err != nil {
-			return err
-		}
+		ids = append(ids, dependentReleaseID)
 	}
 
-	return nil
+	return ids, nil
 }
@@ -21,6 +21,7 @@ var _ = Describe("ReleaseDependenciesAdder", func() {
 			fakeLogger logger.Logger
 
 			pivnetClient *releasefakes.ReleaseDependenciesAdderClient
+			fakeJournal  *releasefakes.Journal
 
 			mdata metadata.Metadata
 
@@ -35,6 +36,7 @@ var _ = Describe("ReleaseDependenciesAdder", func() {
 			fakeLogger = logshim.NewLogShim(logger, logger, true)
 
 			pivnetClient = &releasefakes.ReleaseDependenciesAdderClient{}
+			fakeJournal = &releasefakes.Journal{}
 
 			productSlug = "some-product-slug"
 
@@ -63,6 +65,7 @@ var _ = Describe("ReleaseDependenciesAdder", func() {
 			releaseDependenciesAdder = release.NewReleaseDependenciesAdder(
 				fakeLogger,
 				pivnetClient,
+				fakeJournal,
 				mdata,
 				productSlug,
 			)
@@ -97,6 +100,74 @@ var _ = Describe("ReleaseDependenciesAdder", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(pivnetClient.AddReleaseDependencyCallCount()).To(Equal(2))
+				Expect(fakeJournal.RecordCallCount()).To(Equal(2))
+			})
+
+			Context("when the release already has dependencies", func() {
+				BeforeEach(func() {
+					pivnetClient.ReleaseDependenciesReturns([]pivnet.ReleaseDependency{
+						{Release: pivnet.DependentRelease{ID: 9876}},
+						{Release: pivnet.DependentRelease{ID: 5555}},
+					}, nil)
+				})
+
+				It("only adds the missing dependencies and removes the others", func() {
+					err := releaseDependenciesAdder.AddReleaseDependencies(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.AddReleaseDependencyCallCount()).To(Equal(1))
+					_, _, invokedDependentReleaseID := pivnetClient.AddReleaseDependencyArgsForCall(0)
+					Expect(invokedDependentReleaseID).To(Equal(8765))
+
+					Expect(pivnetClient.RemoveReleaseDependencyCallCount()).To(Equal(1))
+					invokedProductSlug, invokedReleaseID, invokedDependentReleaseID := pivnetClient.RemoveReleaseDependencyArgsForCall(0)
+					Expect(invokedProductSlug).To(Equal(productSlug))
+					Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+					Expect(invokedDependentReleaseID).To(Equal(5555))
+				})
+
+				Context("when the metadata does not provide dependencies", func() {
+					BeforeEach(func() {
+						mdata.Dependencies = nil
+					})
+
+					It("does not remove them", func() {
+						err := releaseDependenciesAdder.AddReleaseDependencies(pivnetRelease)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(pivnetClient.RemoveReleaseDependencyCallCount()).To(Equal(0))
+					})
+				})
+
+				It("records re-adding removed dependencies", func() {
+					err := releaseDependenciesAdder.AddReleaseDependencies(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeJournal.RecordCallCount()).To(Equal(2))
+
+					_, undo := fakeJournal.RecordArgsForCall(1)
+					err = undo()
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, invokedDependentReleaseID := pivnetClient.AddReleaseDependencyArgsForCall(1)
+					Expect(invokedDependentReleaseID).To(Equal(5555))
+				})
+			})
+
+			Context("when listing existing dependencies returns an error", func() {
+				var (
+					expectedErr error
+				)
+
+				BeforeEach(func() {
+					expectedErr = fmt.Errorf("boom")
+					pivnetClient.ReleaseDependenciesReturns(nil, expectedErr)
+				})
+
+				It("returns the error", func() {
+					err := releaseDependenciesAdder.AddReleaseDependencies(pivnetRelease)
+					Expect(err).To(Equal(expectedErr))
+				})
 			})
 
 			Context("when the dependent release ID is zero", func() {
@@ -182,4 +253,83 @@ var _ = Describe("ReleaseDependenciesAdder", func() {
 			})
 		})
 	})
+
+	Describe("PlanReleaseDependencies", func() {
+		var (
+			fakeLogger logger.Logger
+
+			pivnetClient *releasefakes.ReleaseDependenciesAdderClient
+
+			mdata         metadata.Metadata
+			pivnetRelease pivnet.Release
+			plan          release.Plan
+		)
+
+		BeforeEach(func() {
+			logger := log.New(GinkgoWriter, "", log.LstdFlags)
+			fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+			pivnetClient = &releasefakes.ReleaseDependenciesAdderClient{}
+
+			pivnetRelease = pivnet.Release{
+				ID:      1337,
+				Version: "some-version",
+			}
+
+			mdata = metadata.Metadata{
+				Release: &metadata.Release{
+					Version: "some-version",
+				},
+				Dependencies: []metadata.Dependency{
+					{
+						Release: metadata.DependentRelease{
+							ID: 9876,
+						},
+					},
+					{
+						Release: metadata.DependentRelease{
+							Version: "some-dependent-release-version",
+							Product: metadata.Product{
+								Slug: "some-dependent-product",
+							},
+						},
+					},
+				},
+			}
+
+			plan = release.Plan{}
+
+			pivnetClient.GetReleaseReturns(pivnet.Release{ID: 8765}, nil)
+			pivnetClient.ReleaseDependenciesReturns([]pivnet.ReleaseDependency{
+				{Release: pivnet.DependentRelease{ID: 9876}},
+				{Release: pivnet.DependentRelease{
+					ID:      5555,
+					Version: "some-old-version",
+					Product: pivnet.Product{Slug: "some-old-product"},
+				}},
+			}, nil)
+		})
+
+		It("plans the dependencies with their resolved release IDs without changing anything", func() {
+			releaseDependenciesAdder := release.NewReleaseDependenciesAdder(
+				fakeLogger,
+				pivnetClient,
+				&releasefakes.Journal{},
+				mdata,
+				"some-product-slug",
+			)
+
+			err := releaseDependenciesAdder.PlanReleaseDependencies(&plan, pivnetRelease)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.Dependencies).To(Equal([]string{
+				"dependent release - id: '9876' is already added",
+				"add dependent release: 'some-dependent-product/some-dependent-release-version' - id: '8765'",
+				"remove dependent release: 'some-old-product/some-old-version' - id: '5555'",
+			}))
+
+			Expect(pivnetClient.AddReleaseDependencyCallCount()).To(Equal(0))
+			Expect(pivnetClient.RemoveReleaseDependencyCallCount()).To(Equal(0))
+		})
+	})
 })
@@ -11,16 +11,18 @@ import (
 )
 
 type ReleaseFinalizer struct {
-	logger      logger.Logger
-	pivnet      finalizerClient
-	metadata    metadata.Metadata
-	params      concourse.OutParams
-	sourcesDir  string
-	productSlug string
+	logger        logger.Logger
+	pivnet        finalizerClient
+	fingerprinter fingerprinter
+	metadata      metadata.Metadata
+	params        concourse.OutParams
+	sourcesDir    string
+	productSlug   string
 }
 
 func NewFinalizer(
 	pivnetClient finalizerClient,
+	fingerprinter fingerprinter,
 	logger logger.Logger,
 	params concourse.OutParams,
 	metadata metadata.Metadata,
@@ -28,12 +30,13 @@ func NewFinalizer(
 	productSlug string,
 ) ReleaseFinalizer {
 	return ReleaseFinalizer{
-		pivnet:      pivnetClient,
-		logger:      logger,
-		params:      params,
-		metadata:    metadata,
-		sourcesDir:  sourcesDir,
-		productSlug: productSlug,
+		pivnet:        pivnetClient,
+		fingerprinter: fingerprinter,
+		logger:        logger,
+		params:        params,
+		metadata:      metadata,
+		sourcesDir:    sourcesDir,
+		productSlug:   productSlug,
 	}
 }
 
@@ -42,13 +45,23 @@ type finalizerClient interface {
 	GetRelease(productSlug string, releaseVersion string) (pivnet.Release, error)
 }
 
+//go:generate counterfeiter --fake-name FakeFingerprinter . fingerprinter
+type fingerprinter interface {
+	Fingerprint(productSlug string, release pivnet.Release) (string, error)
+}
+
 func (rf ReleaseFinalizer) Finalize(productSlug string, releaseVersion string) (concourse.OutResponse, error) {
 	newRelease, err := rf.pivnet.GetRelease(productSlug, releaseVersion)
 	if err != nil {
 		return concourse.OutResponse{}, err
 	}
 
-	outputVersion, err := versions.CombineVersionAndFingerprint(newRelease.Version, newRelease.UpdatedAt)
+	fingerprint, err := rf.fingerprinter.Fingerprint(productSlug, newRelease)
+	if err != nil {
+		return concourse.OutResponse{}, err
+	}
+
+	outputVersion, err := versions.CombineVersionAndFingerprint(newRelease.Version, fingerprint)
 	if err != nil {
 		return concourse.OutResponse{}, err // this will never return an error
 	}
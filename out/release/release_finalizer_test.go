@@ -21,8 +21,9 @@ var _ = Describe("ReleaseFinalizer", func() {
 		var (
 			fakeLogger logger.Logger
 
-			fakePivnet *releasefakes.FinalizerClient
-			params     concourse.OutParams
+			fakePivnet        *releasefakes.FinalizerClient
+			fakeFingerprinter *releasefakes.FakeFingerprinter
+			params            concourse.OutParams
 
 			mdata metadata.Metadata
 
@@ -39,6 +40,11 @@ var _ = Describe("ReleaseFinalizer", func() {
 			fakeLogger = logshim.NewLogShim(logger, logger, true)
 
 			fakePivnet = &releasefakes.FinalizerClient{}
+			fakeFingerprinter = &releasefakes.FakeFingerprinter{}
+
+			fakeFingerprinter.FingerprintStub = func(productSlug string, release pivnet.Release) (string, error) {
+				return release.UpdatedAt, nil
+			}
 
 			params = concourse.OutParams{}
 
@@ -69,6 +75,7 @@ var _ = Describe("ReleaseFinalizer", func() {
 		JustBeforeEach(func() {
 			finalizer = release.NewFinalizer(
 				fakePivnet,
+				fakeFingerprinter,
 				fakeLogger,
 				params,
 				mdata,
@@ -92,6 +99,24 @@ var _ = Describe("ReleaseFinalizer", func() {
 			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "eula_slug", Value: "a_eula_slug"}))
 		})
 
+		Context("when fingerprinting the release returns an error", func() {
+			var (
+				fingerprintErr error
+			)
+
+			BeforeEach(func() {
+				fingerprintErr = errors.New("fingerprint error")
+
+				fakeFingerprinter.FingerprintStub = nil
+				fakeFingerprinter.FingerprintReturns("", fingerprintErr)
+			})
+
+			It("forwards the error", func() {
+				_, err := finalizer.Finalize(productSlug, pivnetRelease.Version)
+				Expect(err).To(Equal(fingerprintErr))
+			})
+		})
+
 		Context("when getting the release returns an error", func() {
 			BeforeEach(func() {
 				releaseErr = errors.New("release error")
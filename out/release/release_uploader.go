@@ -1,25 +1,37 @@
 package release
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 )
 
+var errUploadCancelled = errors.New("upload cancelled")
+
 type ReleaseUploader struct {
 	s3            s3Client
 	pivnet        uploadClient
+	journal       journal
 	logger        logger.Logger
-	md5Summer     md5Summer
+	fileSummer    fileSummer
 	metadata      metadata.Metadata
 	sourcesDir    string
 	productSlug   string
 	asyncTimeout  time.Duration
 	pollFrequency time.Duration
+	// parallelUploads is the maximum number of files uploaded concurrently.
+	parallelUploads int
+	// rollbackS3Objects records uploaded S3 objects so that they are deleted
+	// on rollback. Otherwise they are left in place.
+	rollbackS3Objects bool
 }
 
 //go:generate counterfeiter --fake-name UploadClient . uploadClient
@@ -27,189 +39,637 @@ type uploadClient interface {
 	FindProductForSlug(slug string) (pivnet.Product, error)
 	CreateProductFile(pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
 	AddProductFile(productSlug string, releaseID int, productFileID int) error
+	RemoveProductFile(productSlug string, releaseID int, productFileID int) error
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
 	ProductFiles(productSlug string) ([]pivnet.ProductFile, error)
 	ProductFile(productSlug string, productFileID int) (pivnet.ProductFile, error)
-	DeleteProductFile(productSlug string, releaseID int) (pivnet.ProductFile, error)
+	DeleteProductFile(productSlug string, productFileID int) (pivnet.ProductFile, error)
 }
 
 //go:generate counterfeiter --fake-name S3Client . s3Client
 type s3Client interface {
+	RemotePath(string) (string, error)
 	UploadFile(string) (string, error)
+	DeleteFile(string) error
 }
 
-//go:generate counterfeiter --fake-name Md5Summer . md5Summer
-type md5Summer interface {
-	SumFile(filepath string) (string, error)
+//go:generate counterfeiter --fake-name FileSummer . fileSummer
+type fileSummer interface {
+	SumFile(filepath string) (checksum.Checksums, error)
 }
 
 func NewReleaseUploader(
 	s3 s3Client,
 	pivnet uploadClient,
+	journal journal,
 	logger logger.Logger,
-	md5Summer md5Summer,
+	fileSummer fileSummer,
 	metadata metadata.Metadata,
 	sourcesDir,
 	productSlug string,
 	asyncTimeout time.Duration,
 	pollFrequency time.Duration,
+	parallelUploads int,
+	rollbackS3Objects bool,
 ) ReleaseUploader {
 	return ReleaseUploader{
 		s3:            s3,
 		pivnet:        pivnet,
+		journal:       journal,
 		logger:        logger,
-		md5Summer:     md5Summer,
+		fileSummer:    fileSummer,
 		metadata:      metadata,
 		sourcesDir:    sourcesDir,
 		productSlug:   productSlug,
 		asyncTimeout:  asyncTimeout,
 		pollFrequency: pollFrequency,
+
+		parallelUploads:   parallelUploads,
+		rollbackS3Objects: rollbackS3Objects,
 	}
 }
 
+type uploadResult struct {
+	productFile pivnet.ProductFile
+	err         error
+}
+
+// Upload uploads the files and adds them to the release, using up to
+// parallelUploads concurrent workers, and then waits for the async transfer
+// of all of them to complete. A file is not uploaded again if the release, or
+// the product, already has a product file for it with the same AWS object key,
+// MD5 and attributes, so that retrying a put only uploads what is missing. The
+// product files are listed once and shared by the workers. If any file fails,
+// the files not yet started are cancelled and the first error is returned.
 func (u ReleaseUploader) Upload(release pivnet.Release, exactGlobs []string) error {
+	releaseProductFiles, err := u.releaseProductFiles(release)
+	if err != nil {
+		return err
+	}
+
+	productFiles, err := u.pivnet.ProductFiles(u.productSlug)
+	if err != nil {
+		return err
+	}
+
+	workers := u.parallelUploads
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(exactGlobs) {
+		workers = len(exactGlobs)
+	}
+
+	results := make([]uploadResult, len(exactGlobs))
+	indices := make(chan int, len(exactGlobs))
+	for i := range exactGlobs {
+		indices <- i
+	}
+	close(indices)
+
+	done := make(chan struct{})
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() { close(done) })
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				select {
+				case <-done:
+					results[i].err = errUploadCancelled
+					continue
+				default:
+				}
+
+				results[i].productFile, results[i].err = u.uploadFile(
+					release,
+					releaseProductFiles,
+					productFiles,
+					exactGlobs[i],
+				)
+				if results[i].err != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Uploads are only cancelled as a result of another upload failing, so
+	// return that failure rather than any of the cancellations.
+	for _, r := range results {
+		if r.err != nil && r.err != errUploadCancelled {
+			return r.err
+		}
+	}
+
+	uploadedProductFiles := make([]pivnet.ProductFile, len(results))
+	uploaded := map[int]bool{}
+	for i, r := range results {
+		uploadedProductFiles[i] = r.productFile
+		uploaded[r.productFile.ID] = true
+	}
+
+	err = u.pollForProductFiles(uploadedProductFiles)
+	if err != nil {
+		return err
+	}
+
+	if u.metadata.ProductFiles == nil {
+		u.logger.Info("No product files provided in metadata - not removing other product files from release")
+		return nil
+	}
+
+	return u.removeOtherProductFiles(release, uploaded)
+}
+
+// uploadFile uploads the file, unless a matching product file exists, and
+// adds its product file to the release. It does not wait for the async
+// transfer of the product file to complete.
+func (u ReleaseUploader) uploadFile(
+	release pivnet.Release,
+	releaseProductFiles []pivnet.ProductFile,
+	productFiles []pivnet.ProductFile,
+	exactGlob string,
+) (pivnet.ProductFile, error) {
+	config, err := u.desiredProductFile(release, exactGlob)
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+
+	if existing, found := findMatchingProductFile(releaseProductFiles, config); found {
+		u.logger.Info(fmt.Sprintf(
+			"Product file: '%s' with ID: %d is already on the release - skipping upload",
+			existing.Name,
+			existing.ID,
+		))
+
+		return existing, nil
+	}
+
+	existing, matches, err := u.productFileForKey(productFiles, config)
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+
+	var productFile pivnet.ProductFile
+	if matches {
+		u.logger.Info(fmt.Sprintf(
+			"Reusing existing product file with AWSObjectKey: '%s' - skipping upload",
+			existing.AWSObjectKey,
+		))
+
+		productFile = existing
+	} else if existing.ID != 0 {
+		u.logger.Info(fmt.Sprintf("Deleting existing product file with AWSObjectKey: '%s'", existing.AWSObjectKey))
+
+		_, err = u.pivnet.DeleteProductFile(u.productSlug, existing.ID)
+		if err != nil {
+			return pivnet.ProductFile{}, err
+		}
+
+		u.journal.RecordIrreversible(fmt.Sprintf(
+			"deleted existing product file: '%s' - id: '%d'",
+			existing.Name,
+			existing.ID,
+		))
+	}
+
+	reused := productFile.ID != 0
+	if !reused {
+		productFile, err = u.uploadAndCreate(exactGlob, config)
+		if err != nil {
+			return pivnet.ProductFile{}, err
+		}
+	}
+
+	u.logger.Info(fmt.Sprintf(
+		"Adding product file: '%s' with ID: %d",
+		config.Name,
+		productFile.ID,
+	))
+
+	err = u.pivnet.AddProductFile(u.productSlug, release.ID, productFile.ID)
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+
+	if reused {
+		productFileID := productFile.ID
+		u.journal.Record(
+			fmt.Sprintf("added product file: '%s' - id: '%d' to release", productFile.Name, productFileID),
+			func() error {
+				return u.pivnet.RemoveProductFile(u.productSlug, release.ID, productFileID)
+			},
+		)
+	}
+
+	return productFile, nil
+}
+
+// PlanUpload runs the same checksums and lookups as Upload, without uploading
+// or changing anything, and adds what Upload would do to the plan.
+func (u ReleaseUploader) PlanUpload(plan *Plan, release pivnet.Release, exactGlobs []string) error {
+	var releaseProductFiles []pivnet.ProductFile
+	if releaseExists(release) {
+		var err error
+		releaseProductFiles, err = u.releaseProductFiles(release)
+		if err != nil {
+			return err
+		}
+	}
+
+	productFiles, err := u.pivnet.ProductFiles(u.productSlug)
+	if err != nil {
+		return err
+	}
+
+	kept := map[int]bool{}
+
 	for _, exactGlob := range exactGlobs {
-		fullFilepath := filepath.Join(u.sourcesDir, exactGlob)
-		fileContentsMD5, err := u.md5Summer.SumFile(fullFilepath)
+		config, err := u.desiredProductFile(release, exactGlob)
 		if err != nil {
 			return err
 		}
 
-		u.logger.Info(fmt.Sprintf("uploading to s3: '%s'", exactGlob))
+		if existing, found := findMatchingProductFile(releaseProductFiles, config); found {
+			kept[existing.ID] = true
 
-		awsObjectKey, err := u.s3.UploadFile(exactGlob)
+			plan.ProductFiles = append(plan.ProductFiles, fmt.Sprintf(
+				"product file: '%s' - id: '%d' is up to date",
+				existing.Name,
+				existing.ID,
+			))
+			continue
+		}
+
+		existing, matches, err := u.productFileForKey(productFiles, config)
 		if err != nil {
 			return err
 		}
 
-		filename := filepath.Base(exactGlob)
+		if matches {
+			plan.ProductFiles = append(plan.ProductFiles, fmt.Sprintf(
+				"add existing product file: '%s' - id: '%d' to release",
+				existing.Name,
+				existing.ID,
+			))
+			continue
+		}
 
-		var description string
-		uploadAs := filename
-		fileType := "Software"
+		if existing.ID != 0 {
+			// Deleting the product file also removes it from the release.
+			kept[existing.ID] = true
 
-		for _, f := range u.metadata.ProductFiles {
-			if f.File == exactGlob {
-				u.logger.Info(fmt.Sprintf(
-					"exact glob '%s' matches metadata file: '%s'",
-					exactGlob,
-					f.File,
-				))
+			plan.ProductFiles = append(plan.ProductFiles, fmt.Sprintf(
+				"delete existing product file: '%s' - id: '%d' with AWSObjectKey: '%s'",
+				existing.Name,
+				existing.ID,
+				existing.AWSObjectKey,
+			))
+		}
 
-				if f.UploadAs != "" {
-					u.logger.Info(fmt.Sprintf(
-						"uploading '%s' to remote filename: '%s' instead",
-						exactGlob,
-						f.UploadAs,
-					))
-					uploadAs = f.UploadAs
-				}
+		plan.ProductFiles = append(plan.ProductFiles, fmt.Sprintf(
+			"upload: '%s' to s3: '%s' and create product file: '%s'",
+			exactGlob,
+			config.AWSObjectKey,
+			config.Name,
+		))
+	}
 
-				description = f.Description
+	if u.metadata.ProductFiles == nil {
+		return nil
+	}
 
-				if f.FileType != "" {
-					fileType = f.FileType
-				}
-			} else {
+	for _, pf := range releaseProductFiles {
+		if kept[pf.ID] {
+			continue
+		}
+
+		plan.ProductFiles = append(plan.ProductFiles, fmt.Sprintf(
+			"remove product file: '%s' - id: '%d' from release",
+			pf.Name,
+			pf.ID,
+		))
+	}
+
+	return nil
+}
+
+// desiredProductFile checksums the file and returns the product file config
+// for it, including the AWS object key it is uploaded to.
+func (u ReleaseUploader) desiredProductFile(
+	release pivnet.Release,
+	exactGlob string,
+) (pivnet.CreateProductFileConfig, error) {
+	fullFilepath := filepath.Join(u.sourcesDir, exactGlob)
+	fileContentsChecksums, err := u.fileSummer.SumFile(fullFilepath)
+	if err != nil {
+		return pivnet.CreateProductFileConfig{}, err
+	}
+
+	err = u.compareSHA256(exactGlob, fileContentsChecksums.SHA256)
+	if err != nil {
+		return pivnet.CreateProductFileConfig{}, err
+	}
+
+	awsObjectKey, err := u.s3.RemotePath(exactGlob)
+	if err != nil {
+		return pivnet.CreateProductFileConfig{}, err
+	}
+
+	config := u.productFileConfig(exactGlob)
+	config.AWSObjectKey = awsObjectKey
+	config.FileVersion = release.Version
+	config.MD5 = fileContentsChecksums.MD5
+
+	return config, nil
+}
+
+// productFileForKey returns the product file of the product files which has
+// the AWS object key of the config, if any, and whether it matches the config
+// and so can be reused.
+func (u ReleaseUploader) productFileForKey(
+	productFiles []pivnet.ProductFile,
+	config pivnet.CreateProductFileConfig,
+) (pivnet.ProductFile, bool, error) {
+	for _, pf := range productFiles {
+		if pf.AWSObjectKey == config.AWSObjectKey {
+			existing, err := u.pivnet.ProductFile(u.productSlug, pf.ID)
+			if err != nil {
+				return pivnet.ProductFile{}, false, err
+			}
+
+			return existing, productFileMatches(existing, config), nil
+		}
+	}
+
+	return pivnet.ProductFile{}, false, nil
+}
+
+// uploadAndCreate uploads the file to S3 and creates a product file for it.
+func (u ReleaseUploader) uploadAndCreate(
+	exactGlob string,
+	config pivnet.CreateProductFileConfig,
+) (pivnet.ProductFile, error) {
+	u.logger.Info(fmt.Sprintf("uploading to s3: '%s'", exactGlob))
+
+	awsObjectKey, err := u.s3.UploadFile(exactGlob)
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+
+	if u.rollbackS3Objects {
+		u.journal.Record(
+			fmt.Sprintf("uploaded S3 object: '%s'", awsObjectKey),
+			func() error {
+				return u.s3.DeleteFile(awsObjectKey)
+			},
+		)
+	} else {
+		u.journal.RecordIrreversible(fmt.Sprintf("uploaded S3 object: '%s'", awsObjectKey))
+	}
+
+	u.logger.Info(fmt.Sprintf(
+		"Creating product file with remote name: '%s'",
+		config.Name,
+	))
+
+	config.AWSObjectKey = awsObjectKey
+
+	productFile, err := u.pivnet.CreateProductFile(config)
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+
+	u.journal.Record(
+		fmt.Sprintf("created product file: '%s' - id: '%d'", productFile.Name, productFile.ID),
+		func() error {
+			_, err := u.pivnet.DeleteProductFile(u.productSlug, productFile.ID)
+			return err
+		},
+	)
+
+	return productFile, nil
+}
+
+// productFileConfig returns the config for creating a product file for the
+// file, taking its remote name, description and file type from the metadata.
+func (u ReleaseUploader) productFileConfig(exactGlob string) pivnet.CreateProductFileConfig {
+	config := pivnet.CreateProductFileConfig{
+		ProductSlug: u.productSlug,
+		Name:        filepath.Base(exactGlob),
+		FileType:    "Software",
+	}
+
+	for _, f := range u.metadata.ProductFiles {
+		if f.File == exactGlob {
+			u.logger.Info(fmt.Sprintf(
+				"exact glob '%s' matches metadata file: '%s'",
+				exactGlob,
+				f.File,
+			))
+
+			if f.UploadAs != "" {
 				u.logger.Info(fmt.Sprintf(
-					"exact glob '%s' does not match metadata file: '%s'",
+					"uploading '%s' to remote filename: '%s' instead",
 					exactGlob,
-					f.File,
+					f.UploadAs,
 				))
+				config.Name = f.UploadAs
+			}
+
+			config.Description = f.Description
+
+			if f.FileType != "" {
+				config.FileType = f.FileType
 			}
+		} else {
+			u.logger.Info(fmt.Sprintf(
+				"exact glob '%s' does not match metadata file: '%s'",
+				exactGlob,
+				f.File,
+			))
 		}
+	}
+
+	return config
+}
+
+// releaseProductFiles returns the product files already on the release.
+// Each is fetched individually as the listing does not include the MD5.
+func (u ReleaseUploader) releaseProductFiles(release pivnet.Release) ([]pivnet.ProductFile, error) {
+	productFiles, err := u.pivnet.ProductFilesForRelease(u.productSlug, release.ID)
+	if err != nil {
+		return nil, err
+	}
 
-		productFiles, err := u.pivnet.ProductFiles(u.productSlug)
+	var details []pivnet.ProductFile
+	for _, pf := range productFiles {
+		productFile, err := u.pivnet.ProductFile(u.productSlug, pf.ID)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		details = append(details, productFile)
+	}
 
-		for _, pf := range productFiles {
-			if pf.AWSObjectKey == awsObjectKey {
-				u.logger.Info(fmt.Sprintf("Deleting existing product file with AWSObjectKey: '%s'", pf.AWSObjectKey))
-
-				_, err = u.pivnet.DeleteProductFile(u.productSlug, pf.ID)
-				if err != nil {
-					return err
-				}
+	return details, nil
+}
 
-				break
-			}
+func findMatchingProductFile(
+	productFiles []pivnet.ProductFile,
+	config pivnet.CreateProductFileConfig,
+) (pivnet.ProductFile, bool) {
+	for _, pf := range productFiles {
+		if productFileMatches(pf, config) {
+			return pf, true
 		}
+	}
 
-		u.logger.Info(fmt.Sprintf(
-			"Creating product file with remote name: '%s'",
-			uploadAs,
-		))
+	return pivnet.ProductFile{}, false
+}
 
-		productFile, err := u.pivnet.CreateProductFile(pivnet.CreateProductFileConfig{
-			ProductSlug:  u.productSlug,
-			Name:         uploadAs,
-			AWSObjectKey: awsObjectKey,
-			FileVersion:  release.Version,
-			MD5:          fileContentsMD5,
-			Description:  description,
-			FileType:     fileType,
-		})
-		if err != nil {
-			return err
+func productFileMatches(pf pivnet.ProductFile, config pivnet.CreateProductFileConfig) bool {
+	return pf.AWSObjectKey == config.AWSObjectKey &&
+		pf.MD5 == config.MD5 &&
+		pf.Name == config.Name &&
+		pf.Description == config.Description &&
+		pf.FileType == config.FileType &&
+		pf.FileVersion == config.FileVersion
+}
+
+// removeOtherProductFiles removes product files from the release which were
+// not uploaded by this put, so that an existing release which is updated in
+// place ends up with exactly the uploaded files. It is only called when the
+// metadata provides the product files. The product files themselves
+// are not deleted as they may belong to other releases.
+func (u ReleaseUploader) removeOtherProductFiles(release pivnet.Release, uploaded map[int]bool) error {
+	releaseProductFiles, err := u.pivnet.ProductFilesForRelease(u.productSlug, release.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, pf := range releaseProductFiles {
+		if uploaded[pf.ID] {
+			continue
 		}
 
 		u.logger.Info(fmt.Sprintf(
-			"Adding product file: '%s' with ID: %d",
-			uploadAs,
-			productFile.ID,
+			"Removing product file: '%s' with ID: %d from release",
+			pf.Name,
+			pf.ID,
 		))
 
-		err = u.pivnet.AddProductFile(u.productSlug, release.ID, productFile.ID)
+		err = u.pivnet.RemoveProductFile(u.productSlug, release.ID, pf.ID)
 		if err != nil {
 			return err
 		}
 
-		err = u.pollForProductFile(productFile)
-		if err != nil {
-			return err
+		productFileID := pf.ID
+		u.journal.Record(
+			fmt.Sprintf("removed product file: '%s' - id: '%d' from release", pf.Name, pf.ID),
+			func() error {
+				return u.pivnet.AddProductFile(u.productSlug, release.ID, productFileID)
+			},
+		)
+	}
+
+	return nil
+}
+
+// compareSHA256 ensures the SHA256 of the local file matches the SHA256
+// provided for it in the metadata, if both are present.
+func (u ReleaseUploader) compareSHA256(exactGlob string, actualSHA256 string) error {
+	if actualSHA256 == "" {
+		return nil
+	}
+
+	u.logger.Info(fmt.Sprintf("SHA256 for '%s': '%s'", exactGlob, actualSHA256))
+
+	for _, f := range u.metadata.ProductFiles {
+		if f.File == exactGlob && f.SHA256 != "" && f.SHA256 != actualSHA256 {
+			return fmt.Errorf(
+				"SHA256 comparison failed for file: '%s'. Expected (from metadata): '%s' - actual (from file): '%s'",
+				exactGlob,
+				f.SHA256,
+				actualSHA256,
+			)
 		}
 	}
 
 	return nil
 }
 
-func (u ReleaseUploader) pollForProductFile(productFile pivnet.ProductFile) error {
+// pollForProductFiles waits for the async transfer of all of the product
+// files to complete, polling those which are still incomplete until
+// asyncTimeout has elapsed.
+func (u ReleaseUploader) pollForProductFiles(productFiles []pivnet.ProductFile) error {
+	if len(productFiles) == 0 {
+		return nil
+	}
+
 	u.logger.Info(fmt.Sprintf(
-		"Polling product file: '%s' for async transfer - will wait up to %v",
-		productFile.Name,
+		"Polling %d product files for async transfer - will wait up to %v",
+		len(productFiles),
 		u.asyncTimeout,
 	))
 
 	timeoutTimer := time.NewTimer(u.asyncTimeout)
+	defer timeoutTimer.Stop()
+
 	pollTicker := time.NewTicker(u.pollFrequency)
+	defer pollTicker.Stop()
+
+	pending := productFiles
 
 	for {
 		select {
 		case <-timeoutTimer.C:
-			return fmt.Errorf("timed out")
-		case <-pollTicker.C:
-			pf, err := u.pivnet.ProductFile(u.productSlug, productFile.ID)
-			if err != nil {
-				return err
+			names := make([]string, len(pending))
+			for i, pf := range pending {
+				names[i] = pf.Name
 			}
 
-			if pf.FileTransferStatus == "complete" {
+			return fmt.Errorf(
+				"timed out waiting for async transfer of product files: ['%s']",
+				strings.Join(names, "', '"),
+			)
+		case <-pollTicker.C:
+			var incomplete []pivnet.ProductFile
+
+			for _, productFile := range pending {
+				pf, err := u.pivnet.ProductFile(u.productSlug, productFile.ID)
+				if err != nil {
+					return err
+				}
+
+				if pf.FileTransferStatus == "complete" {
+					u.logger.Info(fmt.Sprintf(
+						"Product file: '%s' async transfer complete",
+						productFile.Name,
+					))
+					continue
+				}
+
 				u.logger.Info(fmt.Sprintf(
-					"Product file: '%s' async transfer complete",
+					"Product file: '%s' async transfer incomplete",
 					productFile.Name,
 				))
 
-				timeoutTimer.Stop()
-				pollTicker.Stop()
+				incomplete = append(incomplete, productFile)
+			}
 
+			if len(incomplete) == 0 {
 				return nil
 			}
 
-			u.logger.Info(fmt.Sprintf(
-				"Product file: '%s' async transfer incomplete",
-				productFile.Name,
-			))
+			pending = incomplete
 		}
 	}
 }
@@ -3,11 +3,13 @@ package release_test
 import (
 	"errors"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/checksum"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 	"github.com/pivotal-cf/pivnet-resource/out/release"
 	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
@@ -22,23 +24,28 @@ var _ = Describe("ReleaseUploader", func() {
 
 		s3Client      *releasefakes.S3Client
 		uploadClient  *releasefakes.UploadClient
-		md5Summer     *releasefakes.Md5Summer
+		fileSummer    *releasefakes.FileSummer
+		fakeJournal   *releasefakes.Journal
 		pivnetRelease pivnet.Release
 		uploader      release.ReleaseUploader
 		asyncTimeout  time.Duration
 		pollFrequency time.Duration
 
-		productSlug string
+		productSlug       string
+		parallelUploads   int
+		rollbackS3Objects bool
 
 		mdata metadata.Metadata
 
 		existingProductFiles []pivnet.ProductFile
 		actualMD5Sum         string
+		actualSHA256Sum      string
 		newAWSObjectKey      string
 
 		existingProductFilesErr error
 		createProductFileErr    error
 		uploadFileErr           error
+		remotePathErr           error
 		sumFileErr              error
 		productFileErr          error
 	)
@@ -49,9 +56,12 @@ var _ = Describe("ReleaseUploader", func() {
 
 		s3Client = &releasefakes.S3Client{}
 		uploadClient = &releasefakes.UploadClient{}
-		md5Summer = &releasefakes.Md5Summer{}
+		fileSummer = &releasefakes.FileSummer{}
+		fakeJournal = &releasefakes.Journal{}
 
 		productSlug = "some-product-slug"
+		parallelUploads = 1
+		rollbackS3Objects = false
 
 		asyncTimeout = 450 * time.Millisecond
 		pollFrequency = 15 * time.Millisecond
@@ -80,11 +90,13 @@ var _ = Describe("ReleaseUploader", func() {
 		}
 
 		actualMD5Sum = "madeupmd5"
+		actualSHA256Sum = ""
 		newAWSObjectKey = "s3-remote-path"
 
 		existingProductFilesErr = nil
 		createProductFileErr = nil
 		uploadFileErr = nil
+		remotePathErr = nil
 		sumFileErr = nil
 		productFileErr = nil
 	})
@@ -93,16 +105,23 @@ var _ = Describe("ReleaseUploader", func() {
 		uploader = release.NewReleaseUploader(
 			s3Client,
 			uploadClient,
+			fakeJournal,
 			fakeLogger,
-			md5Summer,
+			fileSummer,
 			mdata,
 			"/some/sources/dir",
 			productSlug,
 			asyncTimeout,
 			pollFrequency,
+			parallelUploads,
+			rollbackS3Objects,
 		)
 
-		md5Summer.SumFileReturns(actualMD5Sum, sumFileErr)
+		fileSummer.SumFileReturns(checksum.Checksums{
+			MD5:    actualMD5Sum,
+			SHA256: actualSHA256Sum,
+		}, sumFileErr)
+		s3Client.RemotePathReturns(newAWSObjectKey, remotePathErr)
 		s3Client.UploadFileReturns(newAWSObjectKey, uploadFileErr)
 		uploadClient.CreateProductFileReturns(pivnet.ProductFile{ID: 13367}, createProductFileErr)
 		uploadClient.ProductFilesReturns(existingProductFiles, existingProductFilesErr)
@@ -131,7 +150,7 @@ var _ = Describe("ReleaseUploader", func() {
 			err := uploader.Upload(pivnetRelease, []string{"some/file"})
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(md5Summer.SumFileArgsForCall(0)).To(Equal("/some/sources/dir/some/file"))
+			Expect(fileSummer.SumFileArgsForCall(0)).To(Equal("/some/sources/dir/some/file"))
 			Expect(s3Client.UploadFileArgsForCall(0)).To(Equal("some/file"))
 
 			Expect(uploadClient.CreateProductFileArgsForCall(0)).To(Equal(pivnet.CreateProductFileConfig{
@@ -150,6 +169,104 @@ var _ = Describe("ReleaseUploader", func() {
 			Expect(productFileID).To(Equal(13367))
 		})
 
+		It("records deleting the created product file", func() {
+			err := uploader.Upload(pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeJournal.RecordCallCount()).To(Equal(1))
+
+			_, undo := fakeJournal.RecordArgsForCall(0)
+			err = undo()
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedProductSlug, invokedProductFileID := uploadClient.DeleteProductFileArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal(productSlug))
+			Expect(invokedProductFileID).To(Equal(13367))
+		})
+
+		It("records the S3 object as irreversible", func() {
+			err := uploader.Upload(pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeJournal.RecordIrreversibleCallCount()).To(Equal(1))
+			Expect(fakeJournal.RecordIrreversibleArgsForCall(0)).To(ContainSubstring(newAWSObjectKey))
+		})
+
+		It("does not remove the uploaded product files from the release", func() {
+			uploadClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{{ID: 13367}}, nil)
+
+			err := uploader.Upload(pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uploadClient.RemoveProductFileCallCount()).To(Equal(0))
+		})
+
+		Context("when the release has other product files", func() {
+			BeforeEach(func() {
+				uploadClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{
+					{ID: 13367},
+					{ID: 4444, Name: "some-old-file"},
+				}, nil)
+			})
+
+			It("removes them from the release", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadClient.RemoveProductFileCallCount()).To(Equal(1))
+				invokedProductSlug, invokedReleaseID, invokedProductFileID := uploadClient.RemoveProductFileArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+				Expect(invokedProductFileID).To(Equal(4444))
+
+				Expect(uploadClient.DeleteProductFileCallCount()).To(Equal(0))
+			})
+
+			Context("when the metadata does not provide product files", func() {
+				BeforeEach(func() {
+					mdata.ProductFiles = nil
+				})
+
+				It("does not remove them from the release", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(uploadClient.RemoveProductFileCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when removing a product file returns an error", func() {
+				BeforeEach(func() {
+					uploadClient.RemoveProductFileReturns(errors.New("remove error"))
+				})
+
+				It("returns the error", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError("remove error"))
+				})
+			})
+		})
+
+		Context("when S3 objects are rolled back", func() {
+			BeforeEach(func() {
+				rollbackS3Objects = true
+			})
+
+			It("records deleting the S3 object", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeJournal.RecordCallCount()).To(Equal(2))
+				Expect(fakeJournal.RecordIrreversibleCallCount()).To(Equal(0))
+
+				_, undo := fakeJournal.RecordArgsForCall(0)
+				err = undo()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(s3Client.DeleteFileArgsForCall(0)).To(Equal(newAWSObjectKey))
+			})
+		})
+
 		Context("when a product file already exists with AWSObjectKey", func() {
 			BeforeEach(func() {
 				newAWSObjectKey = existingProductFiles[0].AWSObjectKey
@@ -186,6 +303,210 @@ var _ = Describe("ReleaseUploader", func() {
 			})
 		})
 
+		Context("when the release already has a matching product file", func() {
+			BeforeEach(func() {
+				existingProductFiles[0] = pivnet.ProductFile{
+					ID:           1234,
+					AWSObjectKey: newAWSObjectKey,
+					MD5:          actualMD5Sum,
+					FileVersion:  pivnetRelease.Version,
+					Name:         mdata.ProductFiles[0].UploadAs,
+					Description:  mdata.ProductFiles[0].Description,
+					FileType:     mdata.ProductFiles[0].FileType,
+				}
+
+				uploadClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{{ID: 1234}}, nil)
+			})
+
+			It("does not upload the file again", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(s3Client.RemotePathArgsForCall(0)).To(Equal("some/file"))
+				Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+				Expect(uploadClient.CreateProductFileCallCount()).To(Equal(0))
+				Expect(uploadClient.AddProductFileCallCount()).To(Equal(0))
+				Expect(uploadClient.RemoveProductFileCallCount()).To(Equal(0))
+				Expect(fakeJournal.RecordCallCount()).To(Equal(0))
+			})
+
+			Context("when the file has changed", func() {
+				BeforeEach(func() {
+					actualMD5Sum = "some-other-md5"
+				})
+
+				It("uploads it again and removes the old product file from the release", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(1))
+					Expect(uploadClient.CreateProductFileCallCount()).To(Equal(1))
+
+					_, _, invokedProductFileID := uploadClient.RemoveProductFileArgsForCall(0)
+					Expect(invokedProductFileID).To(Equal(1234))
+				})
+			})
+
+			Context("when fetching the release product file returns an error", func() {
+				BeforeEach(func() {
+					productFileErr = errors.New("product file error")
+				})
+
+				It("returns the error", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(Equal(productFileErr))
+				})
+			})
+		})
+
+		Context("when the product has a matching product file which is not on the release", func() {
+			BeforeEach(func() {
+				existingProductFiles[0] = pivnet.ProductFile{
+					ID:           1234,
+					AWSObjectKey: newAWSObjectKey,
+					MD5:          actualMD5Sum,
+					FileVersion:  pivnetRelease.Version,
+					Name:         mdata.ProductFiles[0].UploadAs,
+					Description:  mdata.ProductFiles[0].Description,
+					FileType:     mdata.ProductFiles[0].FileType,
+				}
+			})
+
+			It("adds it to the release without uploading the file again", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+				Expect(uploadClient.CreateProductFileCallCount()).To(Equal(0))
+				Expect(uploadClient.DeleteProductFileCallCount()).To(Equal(0))
+
+				_, invokedReleaseID, invokedProductFileID := uploadClient.AddProductFileArgsForCall(0)
+				Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+				Expect(invokedProductFileID).To(Equal(1234))
+			})
+
+			It("records removing it from the release", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeJournal.RecordCallCount()).To(Equal(1))
+
+				_, undo := fakeJournal.RecordArgsForCall(0)
+				err = undo()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, invokedReleaseID, invokedProductFileID := uploadClient.RemoveProductFileArgsForCall(0)
+				Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+				Expect(invokedProductFileID).To(Equal(1234))
+			})
+		})
+
+		Context("when the s3 remote path cannot be determined", func() {
+			BeforeEach(func() {
+				remotePathErr = errors.New("remote path error")
+			})
+
+			It("returns an error", func() {
+				err := uploader.Upload(pivnetRelease, []string{""})
+				Expect(err).To(Equal(remotePathErr))
+			})
+		})
+
+		Context("when there are multiple files", func() {
+			var (
+				exactGlobs []string
+			)
+
+			BeforeEach(func() {
+				exactGlobs = []string{"some/file", "some/other-file"}
+			})
+
+			It("adds all of the product files before waiting for their transfers", func() {
+				var addedBeforePolling []int
+				uploadClient.ProductFileStub = func(string, int) (pivnet.ProductFile, error) {
+					addedBeforePolling = append(addedBeforePolling, uploadClient.AddProductFileCallCount())
+					return pivnet.ProductFile{FileTransferStatus: "complete"}, nil
+				}
+
+				err := uploader.Upload(pivnetRelease, exactGlobs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadClient.AddProductFileCallCount()).To(Equal(2))
+				Expect(addedBeforePolling).To(Equal([]int{2, 2}))
+			})
+
+			It("lists the product files of the product once", func() {
+				err := uploader.Upload(pivnetRelease, exactGlobs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadClient.ProductFilesCallCount()).To(Equal(1))
+			})
+
+			Context("when parallel uploads is greater than one", func() {
+				BeforeEach(func() {
+					parallelUploads = 2
+				})
+
+				It("uploads the files concurrently", func() {
+					started := make(chan struct{}, len(exactGlobs))
+					allStarted := make(chan struct{})
+
+					var once sync.Once
+					s3Client.UploadFileStub = func(string) (string, error) {
+						started <- struct{}{}
+						if len(started) == cap(started) {
+							once.Do(func() { close(allStarted) })
+						}
+
+						select {
+						case <-allStarted:
+							return newAWSObjectKey, nil
+						case <-time.After(5 * time.Second):
+							return "", errors.New("uploads were not concurrent")
+						}
+					}
+
+					err := uploader.Upload(pivnetRelease, exactGlobs)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(2))
+				})
+			})
+
+			Context("when an upload fails", func() {
+				BeforeEach(func() {
+					uploadFileErr = errors.New("s3 failed")
+				})
+
+				It("does not start the remaining uploads and returns the error", func() {
+					err := uploader.Upload(pivnetRelease, exactGlobs)
+					Expect(err).To(Equal(uploadFileErr))
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the transfers do not complete in time", func() {
+				BeforeEach(func() {
+					asyncTimeout = pollFrequency * 3
+				})
+
+				It("returns an error naming the incomplete product files", func() {
+					uploadClient.CreateProductFileStub = func(config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+						return pivnet.ProductFile{ID: 13367, Name: config.Name}, nil
+					}
+					uploadClient.ProductFileReturns(pivnet.ProductFile{}, nil)
+					uploadClient.ProductFileStub = nil
+
+					err := uploader.Upload(pivnetRelease, exactGlobs)
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("timed out"))
+					Expect(err.Error()).To(ContainSubstring("'a file', 'other-file'"))
+				})
+			})
+		})
+
 		Context("when the file md5 cannot be computed", func() {
 			BeforeEach(func() {
 				sumFileErr = errors.New("md5 error")
@@ -197,6 +518,42 @@ var _ = Describe("ReleaseUploader", func() {
 			})
 		})
 
+		Context("when the SHA256 is calculated", func() {
+			BeforeEach(func() {
+				actualSHA256Sum = "madeupsha256"
+			})
+
+			It("uploads without error", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when the metadata provides a matching SHA256", func() {
+				BeforeEach(func() {
+					mdata.ProductFiles[0].SHA256 = actualSHA256Sum
+				})
+
+				It("uploads without error", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("when the metadata provides a different SHA256", func() {
+				BeforeEach(func() {
+					mdata.ProductFiles[0].SHA256 = "some-other-sha256"
+				})
+
+				It("returns an error without uploading", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("SHA256 comparison failed"))
+					Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when the s3 upload fails", func() {
 			BeforeEach(func() {
 				uploadFileErr = errors.New("s3 failed")
@@ -265,4 +622,116 @@ var _ = Describe("ReleaseUploader", func() {
 			})
 		})
 	})
+
+	Describe("PlanUpload", func() {
+		var (
+			plan release.Plan
+		)
+
+		BeforeEach(func() {
+			plan = release.Plan{}
+		})
+
+		It("plans uploading the files without changing anything", func() {
+			err := uploader.PlanUpload(&plan, pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.ProductFiles).To(Equal([]string{
+				"upload: 'some/file' to s3: 's3-remote-path' and create product file: 'a file'",
+			}))
+
+			Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+			Expect(uploadClient.CreateProductFileCallCount()).To(Equal(0))
+			Expect(uploadClient.AddProductFileCallCount()).To(Equal(0))
+		})
+
+		It("lists the product files of the product once", func() {
+			err := uploader.PlanUpload(&plan, pivnetRelease, []string{"some/file", "some/other-file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uploadClient.ProductFilesCallCount()).To(Equal(1))
+		})
+
+		Context("when a product file already exists with AWSObjectKey", func() {
+			BeforeEach(func() {
+				newAWSObjectKey = existingProductFiles[0].AWSObjectKey
+				existingProductFiles[0].Name = "some-existing-file"
+			})
+
+			It("plans deleting it", func() {
+				err := uploader.PlanUpload(&plan, pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.ProductFiles[0]).To(Equal(
+					"delete existing product file: 'some-existing-file' - id: '1234' with AWSObjectKey: 'some-existing-aws-object-key'",
+				))
+				Expect(plan.ProductFiles).To(HaveLen(2))
+
+				Expect(uploadClient.DeleteProductFileCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the release already exists with product files", func() {
+			BeforeEach(func() {
+				existingProductFiles[0] = pivnet.ProductFile{
+					ID:           1234,
+					Name:         mdata.ProductFiles[0].UploadAs,
+					AWSObjectKey: newAWSObjectKey,
+					MD5:          actualMD5Sum,
+					FileVersion:  pivnetRelease.Version,
+					Description:  mdata.ProductFiles[0].Description,
+					FileType:     mdata.ProductFiles[0].FileType,
+				}
+
+				uploadClient.ProductFilesForReleaseReturns([]pivnet.ProductFile{
+					{ID: 1234},
+				}, nil)
+			})
+
+			It("plans keeping the matching product files", func() {
+				err := uploader.PlanUpload(&plan, pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.ProductFiles).To(Equal([]string{
+					"product file: 'a file' - id: '1234' is up to date",
+				}))
+			})
+
+			It("plans removing the others", func() {
+				err := uploader.PlanUpload(&plan, pivnetRelease, []string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.ProductFiles).To(Equal([]string{
+					"remove product file: 'a file' - id: '1234' from release",
+				}))
+
+				Expect(uploadClient.RemoveProductFileCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the release would be created", func() {
+			BeforeEach(func() {
+				pivnetRelease.ID = 0
+			})
+
+			It("does not look up its product files", func() {
+				err := uploader.PlanUpload(&plan, pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadClient.ProductFilesForReleaseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the metadata provides a different SHA256", func() {
+			BeforeEach(func() {
+				actualSHA256Sum = "madeupsha256"
+				mdata.ProductFiles[0].SHA256 = "some-other-sha256"
+			})
+
+			It("returns an error", func() {
+				err := uploader.PlanUpload(&plan, pivnetRelease, []string{"some/file"})
+				Expect(err).To(MatchError(ContainSubstring("SHA256 comparison failed")))
+			})
+		})
+	})
 })
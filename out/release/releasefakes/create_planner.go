@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	out_release "github.com/pivotal-cf/pivnet-resource/out/release"
+)
+
+type CreatePlanner struct {
+	PlanCreateStub        func(plan *out_release.Plan) (go_pivnet.Release, error)
+	planCreateMutex       sync.RWMutex
+	planCreateArgsForCall []struct {
+		plan *out_release.Plan
+	}
+	planCreateReturns struct {
+		result1 go_pivnet.Release
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CreatePlanner) PlanCreate(plan *out_release.Plan) (go_pivnet.Release, error) {
+	fake.planCreateMutex.Lock()
+	fake.planCreateArgsForCall = append(fake.planCreateArgsForCall, struct {
+		plan *out_release.Plan
+	}{plan})
+	fake.recordInvocation("PlanCreate", []interface{}{plan})
+	fake.planCreateMutex.Unlock()
+	if fake.PlanCreateStub != nil {
+		return fake.PlanCreateStub(plan)
+	} else {
+		return fake.planCreateReturns.result1, fake.planCreateReturns.result2
+	}
+}
+
+func (fake *CreatePlanner) PlanCreateCallCount() int {
+	fake.planCreateMutex.RLock()
+	defer fake.planCreateMutex.RUnlock()
+	return len(fake.planCreateArgsForCall)
+}
+
+func (fake *CreatePlanner) PlanCreateArgsForCall(i int) *out_release.Plan {
+	fake.planCreateMutex.RLock()
+	defer fake.planCreateMutex.RUnlock()
+	return fake.planCreateArgsForCall[i].plan
+}
+
+func (fake *CreatePlanner) PlanCreateReturns(result1 go_pivnet.Release, result2 error) {
+	fake.PlanCreateStub = nil
+	fake.planCreateReturns = struct {
+		result1 go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CreatePlanner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planCreateMutex.RLock()
+	defer fake.planCreateMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *CreatePlanner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
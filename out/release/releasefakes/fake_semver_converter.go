@@ -17,6 +17,16 @@ type FakeSemverConverter struct {
 		result1 semver.Version
 		result2 error
 	}
+	MatchesConstraintStub        func(input string, constraint string) (bool, error)
+	matchesConstraintMutex       sync.RWMutex
+	matchesConstraintArgsForCall []struct {
+		input      string
+		constraint string
+	}
+	matchesConstraintReturns struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -55,11 +65,48 @@ func (fake *FakeSemverConverter) ToValidSemverReturns(result1 semver.Version, re
 	}{result1, result2}
 }
 
+func (fake *FakeSemverConverter) MatchesConstraint(input string, constraint string) (bool, error) {
+	fake.matchesConstraintMutex.Lock()
+	fake.matchesConstraintArgsForCall = append(fake.matchesConstraintArgsForCall, struct {
+		input      string
+		constraint string
+	}{input, constraint})
+	fake.recordInvocation("MatchesConstraint", []interface{}{input, constraint})
+	fake.matchesConstraintMutex.Unlock()
+	if fake.MatchesConstraintStub != nil {
+		return fake.MatchesConstraintStub(input, constraint)
+	} else {
+		return fake.matchesConstraintReturns.result1, fake.matchesConstraintReturns.result2
+	}
+}
+
+func (fake *FakeSemverConverter) MatchesConstraintCallCount() int {
+	fake.matchesConstraintMutex.RLock()
+	defer fake.matchesConstraintMutex.RUnlock()
+	return len(fake.matchesConstraintArgsForCall)
+}
+
+func (fake *FakeSemverConverter) MatchesConstraintArgsForCall(i int) (string, string) {
+	fake.matchesConstraintMutex.RLock()
+	defer fake.matchesConstraintMutex.RUnlock()
+	return fake.matchesConstraintArgsForCall[i].input, fake.matchesConstraintArgsForCall[i].constraint
+}
+
+func (fake *FakeSemverConverter) MatchesConstraintReturns(result1 bool, result2 error) {
+	fake.MatchesConstraintStub = nil
+	fake.matchesConstraintReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSemverConverter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.toValidSemverMutex.RLock()
 	defer fake.toValidSemverMutex.RUnlock()
+	fake.matchesConstraintMutex.RLock()
+	defer fake.matchesConstraintMutex.RUnlock()
 	return fake.invocations
 }
 
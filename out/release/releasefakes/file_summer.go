@@ -1,23 +1,27 @@
 // This file was generated by counterfeiter
 package releasefakes
 
-import "sync"
+import (
+	"sync"
 
-type Md5Summer struct {
-	SumFileStub        func(filepath string) (string, error)
+	"github.com/pivotal-cf/pivnet-resource/checksum"
+)
+
+type FileSummer struct {
+	SumFileStub        func(filepath string) (checksum.Checksums, error)
 	sumFileMutex       sync.RWMutex
 	sumFileArgsForCall []struct {
 		filepath string
 	}
 	sumFileReturns struct {
-		result1 string
+		result1 checksum.Checksums
 		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Md5Summer) SumFile(filepath string) (string, error) {
+func (fake *FileSummer) SumFile(filepath string) (checksum.Checksums, error) {
 	fake.sumFileMutex.Lock()
 	fake.sumFileArgsForCall = append(fake.sumFileArgsForCall, struct {
 		filepath string
@@ -31,27 +35,27 @@ func (fake *Md5Summer) SumFile(filepath string) (string, error) {
 	}
 }
 
-func (fake *Md5Summer) SumFileCallCount() int {
+func (fake *FileSummer) SumFileCallCount() int {
 	fake.sumFileMutex.RLock()
 	defer fake.sumFileMutex.RUnlock()
 	return len(fake.sumFileArgsForCall)
 }
 
-func (fake *Md5Summer) SumFileArgsForCall(i int) string {
+func (fake *FileSummer) SumFileArgsForCall(i int) string {
 	fake.sumFileMutex.RLock()
 	defer fake.sumFileMutex.RUnlock()
 	return fake.sumFileArgsForCall[i].filepath
 }
 
-func (fake *Md5Summer) SumFileReturns(result1 string, result2 error) {
+func (fake *FileSummer) SumFileReturns(result1 checksum.Checksums, result2 error) {
 	fake.SumFileStub = nil
 	fake.sumFileReturns = struct {
-		result1 string
+		result1 checksum.Checksums
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Md5Summer) Invocations() map[string][][]interface{} {
+func (fake *FileSummer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.sumFileMutex.RLock()
@@ -59,7 +63,7 @@ func (fake *Md5Summer) Invocations() map[string][][]interface{} {
 	return fake.invocations
 }
 
-func (fake *Md5Summer) recordInvocation(key string, args []interface{}) {
+func (fake *FileSummer) recordInvocation(key string, args []interface{}) {
 	fake.invocationsMutex.Lock()
 	defer fake.invocationsMutex.Unlock()
 	if fake.invocations == nil {
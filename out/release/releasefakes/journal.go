@@ -0,0 +1,91 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import "sync"
+
+type Journal struct {
+	RecordStub        func(description string, undo func() error)
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		description string
+		undo        func() error
+	}
+	RecordIrreversibleStub        func(description string)
+	recordIrreversibleMutex       sync.RWMutex
+	recordIrreversibleArgsForCall []struct {
+		description string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Journal) Record(description string, undo func() error) {
+	fake.recordMutex.Lock()
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		description string
+		undo        func() error
+	}{description, undo})
+	fake.recordInvocation("Record", []interface{}{description, undo})
+	fake.recordMutex.Unlock()
+	if fake.RecordStub != nil {
+		fake.RecordStub(description, undo)
+	}
+}
+
+func (fake *Journal) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *Journal) RecordArgsForCall(i int) (string, func() error) {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return fake.recordArgsForCall[i].description, fake.recordArgsForCall[i].undo
+}
+
+func (fake *Journal) RecordIrreversible(description string) {
+	fake.recordIrreversibleMutex.Lock()
+	fake.recordIrreversibleArgsForCall = append(fake.recordIrreversibleArgsForCall, struct {
+		description string
+	}{description})
+	fake.recordInvocation("RecordIrreversible", []interface{}{description})
+	fake.recordIrreversibleMutex.Unlock()
+	if fake.RecordIrreversibleStub != nil {
+		fake.RecordIrreversibleStub(description)
+	}
+}
+
+func (fake *Journal) RecordIrreversibleCallCount() int {
+	fake.recordIrreversibleMutex.RLock()
+	defer fake.recordIrreversibleMutex.RUnlock()
+	return len(fake.recordIrreversibleArgsForCall)
+}
+
+func (fake *Journal) RecordIrreversibleArgsForCall(i int) string {
+	fake.recordIrreversibleMutex.RLock()
+	defer fake.recordIrreversibleMutex.RUnlock()
+	return fake.recordIrreversibleArgsForCall[i].description
+}
+
+func (fake *Journal) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	fake.recordIrreversibleMutex.RLock()
+	defer fake.recordIrreversibleMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *Journal) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
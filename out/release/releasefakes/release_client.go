@@ -40,6 +40,16 @@ type ReleaseClient struct {
 		result1 go_pivnet.Release
 		result2 error
 	}
+	UpdateReleaseMetadataStub        func(productSlug string, release go_pivnet.Release) (go_pivnet.Release, error)
+	updateReleaseMetadataMutex       sync.RWMutex
+	updateReleaseMetadataArgsForCall []struct {
+		productSlug string
+		release     go_pivnet.Release
+	}
+	updateReleaseMetadataReturns struct {
+		result1 go_pivnet.Release
+		result2 error
+	}
 	DeleteReleaseStub        func(productSlug string, release go_pivnet.Release) error
 	deleteReleaseMutex       sync.RWMutex
 	deleteReleaseArgsForCall []struct {
@@ -173,6 +183,41 @@ func (fake *ReleaseClient) CreateReleaseReturns(result1 go_pivnet.Release, resul
 	}{result1, result2}
 }
 
+func (fake *ReleaseClient) UpdateReleaseMetadata(productSlug string, release go_pivnet.Release) (go_pivnet.Release, error) {
+	fake.updateReleaseMetadataMutex.Lock()
+	fake.updateReleaseMetadataArgsForCall = append(fake.updateReleaseMetadataArgsForCall, struct {
+		productSlug string
+		release     go_pivnet.Release
+	}{productSlug, release})
+	fake.recordInvocation("UpdateReleaseMetadata", []interface{}{productSlug, release})
+	fake.updateReleaseMetadataMutex.Unlock()
+	if fake.UpdateReleaseMetadataStub != nil {
+		return fake.UpdateReleaseMetadataStub(productSlug, release)
+	} else {
+		return fake.updateReleaseMetadataReturns.result1, fake.updateReleaseMetadataReturns.result2
+	}
+}
+
+func (fake *ReleaseClient) UpdateReleaseMetadataCallCount() int {
+	fake.updateReleaseMetadataMutex.RLock()
+	defer fake.updateReleaseMetadataMutex.RUnlock()
+	return len(fake.updateReleaseMetadataArgsForCall)
+}
+
+func (fake *ReleaseClient) UpdateReleaseMetadataArgsForCall(i int) (string, go_pivnet.Release) {
+	fake.updateReleaseMetadataMutex.RLock()
+	defer fake.updateReleaseMetadataMutex.RUnlock()
+	return fake.updateReleaseMetadataArgsForCall[i].productSlug, fake.updateReleaseMetadataArgsForCall[i].release
+}
+
+func (fake *ReleaseClient) UpdateReleaseMetadataReturns(result1 go_pivnet.Release, result2 error) {
+	fake.UpdateReleaseMetadataStub = nil
+	fake.updateReleaseMetadataReturns = struct {
+		result1 go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ReleaseClient) DeleteRelease(productSlug string, release go_pivnet.Release) error {
 	fake.deleteReleaseMutex.Lock()
 	fake.deleteReleaseArgsForCall = append(fake.deleteReleaseArgsForCall, struct {
@@ -218,6 +263,8 @@ func (fake *ReleaseClient) Invocations() map[string][][]interface{} {
 	defer fake.releasesForProductSlugMutex.RUnlock()
 	fake.createReleaseMutex.RLock()
 	defer fake.createReleaseMutex.RUnlock()
+	fake.updateReleaseMetadataMutex.RLock()
+	defer fake.updateReleaseMetadataMutex.RUnlock()
 	fake.deleteReleaseMutex.RLock()
 	defer fake.deleteReleaseMutex.RUnlock()
 	return fake.invocations
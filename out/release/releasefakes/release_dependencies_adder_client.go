@@ -8,6 +8,16 @@ import (
 )
 
 type ReleaseDependenciesAdderClient struct {
+	ReleaseDependenciesStub        func(productSlug string, releaseID int) ([]go_pivnet.ReleaseDependency, error)
+	releaseDependenciesMutex       sync.RWMutex
+	releaseDependenciesArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	releaseDependenciesReturns struct {
+		result1 []go_pivnet.ReleaseDependency
+		result2 error
+	}
 	AddReleaseDependencyStub        func(productSlug string, releaseID int, dependentReleaseID int) error
 	addReleaseDependencyMutex       sync.RWMutex
 	addReleaseDependencyArgsForCall []struct {
@@ -18,6 +28,16 @@ type ReleaseDependenciesAdderClient struct {
 	addReleaseDependencyReturns struct {
 		result1 error
 	}
+	RemoveReleaseDependencyStub        func(productSlug string, releaseID int, dependentReleaseID int) error
+	removeReleaseDependencyMutex       sync.RWMutex
+	removeReleaseDependencyArgsForCall []struct {
+		productSlug        string
+		releaseID          int
+		dependentReleaseID int
+	}
+	removeReleaseDependencyReturns struct {
+		result1 error
+	}
 	GetReleaseStub        func(productSlug string, releaseVersion string) (go_pivnet.Release, error)
 	getReleaseMutex       sync.RWMutex
 	getReleaseArgsForCall []struct {
@@ -32,6 +52,41 @@ type ReleaseDependenciesAdderClient struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ReleaseDependenciesAdderClient) ReleaseDependencies(productSlug string, releaseID int) ([]go_pivnet.ReleaseDependency, error) {
+	fake.releaseDependenciesMutex.Lock()
+	fake.releaseDependenciesArgsForCall = append(fake.releaseDependenciesArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ReleaseDependencies", []interface{}{productSlug, releaseID})
+	fake.releaseDependenciesMutex.Unlock()
+	if fake.ReleaseDependenciesStub != nil {
+		return fake.ReleaseDependenciesStub(productSlug, releaseID)
+	} else {
+		return fake.releaseDependenciesReturns.result1, fake.releaseDependenciesReturns.result2
+	}
+}
+
+func (fake *ReleaseDependenciesAdderClient) ReleaseDependenciesCallCount() int {
+	fake.releaseDependenciesMutex.RLock()
+	defer fake.releaseDependenciesMutex.RUnlock()
+	return len(fake.releaseDependenciesArgsForCall)
+}
+
+func (fake *ReleaseDependenciesAdderClient) ReleaseDependenciesArgsForCall(i int) (string, int) {
+	fake.releaseDependenciesMutex.RLock()
+	defer fake.releaseDependenciesMutex.RUnlock()
+	return fake.releaseDependenciesArgsForCall[i].productSlug, fake.releaseDependenciesArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseDependenciesAdderClient) ReleaseDependenciesReturns(result1 []go_pivnet.ReleaseDependency, result2 error) {
+	fake.ReleaseDependenciesStub = nil
+	fake.releaseDependenciesReturns = struct {
+		result1 []go_pivnet.ReleaseDependency
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ReleaseDependenciesAdderClient) AddReleaseDependency(productSlug string, releaseID int, dependentReleaseID int) error {
 	fake.addReleaseDependencyMutex.Lock()
 	fake.addReleaseDependencyArgsForCall = append(fake.addReleaseDependencyArgsForCall, struct {
@@ -67,6 +122,41 @@ func (fake *ReleaseDependenciesAdderClient) AddReleaseDependencyReturns(result1
 	}{result1}
 }
 
+func (fake *ReleaseDependenciesAdderClient) RemoveReleaseDependency(productSlug string, releaseID int, dependentReleaseID int) error {
+	fake.removeReleaseDependencyMutex.Lock()
+	fake.removeReleaseDependencyArgsForCall = append(fake.removeReleaseDependencyArgsForCall, struct {
+		productSlug        string
+		releaseID          int
+		dependentReleaseID int
+	}{productSlug, releaseID, dependentReleaseID})
+	fake.recordInvocation("RemoveReleaseDependency", []interface{}{productSlug, releaseID, dependentReleaseID})
+	fake.removeReleaseDependencyMutex.Unlock()
+	if fake.RemoveReleaseDependencyStub != nil {
+		return fake.RemoveReleaseDependencyStub(productSlug, releaseID, dependentReleaseID)
+	} else {
+		return fake.removeReleaseDependencyReturns.result1
+	}
+}
+
+func (fake *ReleaseDependenciesAdderClient) RemoveReleaseDependencyCallCount() int {
+	fake.removeReleaseDependencyMutex.RLock()
+	defer fake.removeReleaseDependencyMutex.RUnlock()
+	return len(fake.removeReleaseDependencyArgsForCall)
+}
+
+func (fake *ReleaseDependenciesAdderClient) RemoveReleaseDependencyArgsForCall(i int) (string, int, int) {
+	fake.removeReleaseDependencyMutex.RLock()
+	defer fake.removeReleaseDependencyMutex.RUnlock()
+	return fake.removeReleaseDependencyArgsForCall[i].productSlug, fake.removeReleaseDependencyArgsForCall[i].releaseID, fake.removeReleaseDependencyArgsForCall[i].dependentReleaseID
+}
+
+func (fake *ReleaseDependenciesAdderClient) RemoveReleaseDependencyReturns(result1 error) {
+	fake.RemoveReleaseDependencyStub = nil
+	fake.removeReleaseDependencyReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ReleaseDependenciesAdderClient) GetRelease(productSlug string, releaseVersion string) (go_pivnet.Release, error) {
 	fake.getReleaseMutex.Lock()
 	fake.getReleaseArgsForCall = append(fake.getReleaseArgsForCall, struct {
@@ -105,8 +195,12 @@ func (fake *ReleaseDependenciesAdderClient) GetReleaseReturns(result1 go_pivnet.
 func (fake *ReleaseDependenciesAdderClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.releaseDependenciesMutex.RLock()
+	defer fake.releaseDependenciesMutex.RUnlock()
 	fake.addReleaseDependencyMutex.RLock()
 	defer fake.addReleaseDependencyMutex.RUnlock()
+	fake.removeReleaseDependencyMutex.RLock()
+	defer fake.removeReleaseDependencyMutex.RUnlock()
 	fake.getReleaseMutex.RLock()
 	defer fake.getReleaseMutex.RUnlock()
 	return fake.invocations
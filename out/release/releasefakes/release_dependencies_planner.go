@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	out_release "github.com/pivotal-cf/pivnet-resource/out/release"
+)
+
+type ReleaseDependenciesPlanner struct {
+	PlanReleaseDependenciesStub        func(plan *out_release.Plan, release go_pivnet.Release) error
+	planReleaseDependenciesMutex       sync.RWMutex
+	planReleaseDependenciesArgsForCall []struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}
+	planReleaseDependenciesReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ReleaseDependenciesPlanner) PlanReleaseDependencies(plan *out_release.Plan, release go_pivnet.Release) error {
+	fake.planReleaseDependenciesMutex.Lock()
+	fake.planReleaseDependenciesArgsForCall = append(fake.planReleaseDependenciesArgsForCall, struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}{plan, release})
+	fake.recordInvocation("PlanReleaseDependencies", []interface{}{plan, release})
+	fake.planReleaseDependenciesMutex.Unlock()
+	if fake.PlanReleaseDependenciesStub != nil {
+		return fake.PlanReleaseDependenciesStub(plan, release)
+	} else {
+		return fake.planReleaseDependenciesReturns.result1
+	}
+}
+
+func (fake *ReleaseDependenciesPlanner) PlanReleaseDependenciesCallCount() int {
+	fake.planReleaseDependenciesMutex.RLock()
+	defer fake.planReleaseDependenciesMutex.RUnlock()
+	return len(fake.planReleaseDependenciesArgsForCall)
+}
+
+func (fake *ReleaseDependenciesPlanner) PlanReleaseDependenciesArgsForCall(i int) (*out_release.Plan, go_pivnet.Release) {
+	fake.planReleaseDependenciesMutex.RLock()
+	defer fake.planReleaseDependenciesMutex.RUnlock()
+	return fake.planReleaseDependenciesArgsForCall[i].plan, fake.planReleaseDependenciesArgsForCall[i].release
+}
+
+func (fake *ReleaseDependenciesPlanner) PlanReleaseDependenciesReturns(result1 error) {
+	fake.PlanReleaseDependenciesStub = nil
+	fake.planReleaseDependenciesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseDependenciesPlanner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planReleaseDependenciesMutex.RLock()
+	defer fake.planReleaseDependenciesMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *ReleaseDependenciesPlanner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
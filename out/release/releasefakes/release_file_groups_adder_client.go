@@ -0,0 +1,450 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+type ReleaseFileGroupsAdderClient struct {
+	FileGroupsStub        func(productSlug string) ([]go_pivnet.FileGroup, error)
+	fileGroupsMutex       sync.RWMutex
+	fileGroupsArgsForCall []struct {
+		productSlug string
+	}
+	fileGroupsReturns struct {
+		result1 []go_pivnet.FileGroup
+		result2 error
+	}
+	FileGroupsForReleaseStub        func(productSlug string, releaseID int) ([]go_pivnet.FileGroup, error)
+	fileGroupsForReleaseMutex       sync.RWMutex
+	fileGroupsForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	fileGroupsForReleaseReturns struct {
+		result1 []go_pivnet.FileGroup
+		result2 error
+	}
+	CreateFileGroupStub        func(productSlug string, name string) (go_pivnet.FileGroup, error)
+	createFileGroupMutex       sync.RWMutex
+	createFileGroupArgsForCall []struct {
+		productSlug string
+		name        string
+	}
+	createFileGroupReturns struct {
+		result1 go_pivnet.FileGroup
+		result2 error
+	}
+	DeleteFileGroupStub        func(productSlug string, fileGroupID int) error
+	deleteFileGroupMutex       sync.RWMutex
+	deleteFileGroupArgsForCall []struct {
+		productSlug string
+		fileGroupID int
+	}
+	deleteFileGroupReturns struct {
+		result1 error
+	}
+	AddFileGroupStub        func(productSlug string, releaseID int, fileGroupID int) error
+	addFileGroupMutex       sync.RWMutex
+	addFileGroupArgsForCall []struct {
+		productSlug string
+		releaseID   int
+		fileGroupID int
+	}
+	addFileGroupReturns struct {
+		result1 error
+	}
+	RemoveFileGroupStub        func(productSlug string, releaseID int, fileGroupID int) error
+	removeFileGroupMutex       sync.RWMutex
+	removeFileGroupArgsForCall []struct {
+		productSlug string
+		releaseID   int
+		fileGroupID int
+	}
+	removeFileGroupReturns struct {
+		result1 error
+	}
+	AddToFileGroupStub        func(productSlug string, fileGroupID int, productFileID int) error
+	addToFileGroupMutex       sync.RWMutex
+	addToFileGroupArgsForCall []struct {
+		productSlug   string
+		fileGroupID   int
+		productFileID int
+	}
+	addToFileGroupReturns struct {
+		result1 error
+	}
+	RemoveFromFileGroupStub        func(productSlug string, fileGroupID int, productFileID int) error
+	removeFromFileGroupMutex       sync.RWMutex
+	removeFromFileGroupArgsForCall []struct {
+		productSlug   string
+		fileGroupID   int
+		productFileID int
+	}
+	removeFromFileGroupReturns struct {
+		result1 error
+	}
+	ProductFilesForReleaseStub        func(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error)
+	productFilesForReleaseMutex       sync.RWMutex
+	productFilesForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	productFilesForReleaseReturns struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroups(productSlug string) ([]go_pivnet.FileGroup, error) {
+	fake.fileGroupsMutex.Lock()
+	fake.fileGroupsArgsForCall = append(fake.fileGroupsArgsForCall, struct {
+		productSlug string
+	}{productSlug})
+	fake.recordInvocation("FileGroups", []interface{}{productSlug})
+	fake.fileGroupsMutex.Unlock()
+	if fake.FileGroupsStub != nil {
+		return fake.FileGroupsStub(productSlug)
+	} else {
+		return fake.fileGroupsReturns.result1, fake.fileGroupsReturns.result2
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroupsCallCount() int {
+	fake.fileGroupsMutex.RLock()
+	defer fake.fileGroupsMutex.RUnlock()
+	return len(fake.fileGroupsArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroupsArgsForCall(i int) string {
+	fake.fileGroupsMutex.RLock()
+	defer fake.fileGroupsMutex.RUnlock()
+	return fake.fileGroupsArgsForCall[i].productSlug
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroupsReturns(result1 []go_pivnet.FileGroup, result2 error) {
+	fake.FileGroupsStub = nil
+	fake.fileGroupsReturns = struct {
+		result1 []go_pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroupsForRelease(productSlug string, releaseID int) ([]go_pivnet.FileGroup, error) {
+	fake.fileGroupsForReleaseMutex.Lock()
+	fake.fileGroupsForReleaseArgsForCall = append(fake.fileGroupsForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("FileGroupsForRelease", []interface{}{productSlug, releaseID})
+	fake.fileGroupsForReleaseMutex.Unlock()
+	if fake.FileGroupsForReleaseStub != nil {
+		return fake.FileGroupsForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.fileGroupsForReleaseReturns.result1, fake.fileGroupsForReleaseReturns.result2
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroupsForReleaseCallCount() int {
+	fake.fileGroupsForReleaseMutex.RLock()
+	defer fake.fileGroupsForReleaseMutex.RUnlock()
+	return len(fake.fileGroupsForReleaseArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroupsForReleaseArgsForCall(i int) (string, int) {
+	fake.fileGroupsForReleaseMutex.RLock()
+	defer fake.fileGroupsForReleaseMutex.RUnlock()
+	return fake.fileGroupsForReleaseArgsForCall[i].productSlug, fake.fileGroupsForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseFileGroupsAdderClient) FileGroupsForReleaseReturns(result1 []go_pivnet.FileGroup, result2 error) {
+	fake.FileGroupsForReleaseStub = nil
+	fake.fileGroupsForReleaseReturns = struct {
+		result1 []go_pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) CreateFileGroup(productSlug string, name string) (go_pivnet.FileGroup, error) {
+	fake.createFileGroupMutex.Lock()
+	fake.createFileGroupArgsForCall = append(fake.createFileGroupArgsForCall, struct {
+		productSlug string
+		name        string
+	}{productSlug, name})
+	fake.recordInvocation("CreateFileGroup", []interface{}{productSlug, name})
+	fake.createFileGroupMutex.Unlock()
+	if fake.CreateFileGroupStub != nil {
+		return fake.CreateFileGroupStub(productSlug, name)
+	} else {
+		return fake.createFileGroupReturns.result1, fake.createFileGroupReturns.result2
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) CreateFileGroupCallCount() int {
+	fake.createFileGroupMutex.RLock()
+	defer fake.createFileGroupMutex.RUnlock()
+	return len(fake.createFileGroupArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) CreateFileGroupArgsForCall(i int) (string, string) {
+	fake.createFileGroupMutex.RLock()
+	defer fake.createFileGroupMutex.RUnlock()
+	return fake.createFileGroupArgsForCall[i].productSlug, fake.createFileGroupArgsForCall[i].name
+}
+
+func (fake *ReleaseFileGroupsAdderClient) CreateFileGroupReturns(result1 go_pivnet.FileGroup, result2 error) {
+	fake.CreateFileGroupStub = nil
+	fake.createFileGroupReturns = struct {
+		result1 go_pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) DeleteFileGroup(productSlug string, fileGroupID int) error {
+	fake.deleteFileGroupMutex.Lock()
+	fake.deleteFileGroupArgsForCall = append(fake.deleteFileGroupArgsForCall, struct {
+		productSlug string
+		fileGroupID int
+	}{productSlug, fileGroupID})
+	fake.recordInvocation("DeleteFileGroup", []interface{}{productSlug, fileGroupID})
+	fake.deleteFileGroupMutex.Unlock()
+	if fake.DeleteFileGroupStub != nil {
+		return fake.DeleteFileGroupStub(productSlug, fileGroupID)
+	} else {
+		return fake.deleteFileGroupReturns.result1
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) DeleteFileGroupCallCount() int {
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
+	return len(fake.deleteFileGroupArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) DeleteFileGroupArgsForCall(i int) (string, int) {
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
+	return fake.deleteFileGroupArgsForCall[i].productSlug, fake.deleteFileGroupArgsForCall[i].fileGroupID
+}
+
+func (fake *ReleaseFileGroupsAdderClient) DeleteFileGroupReturns(result1 error) {
+	fake.DeleteFileGroupStub = nil
+	fake.deleteFileGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddFileGroup(productSlug string, releaseID int, fileGroupID int) error {
+	fake.addFileGroupMutex.Lock()
+	fake.addFileGroupArgsForCall = append(fake.addFileGroupArgsForCall, struct {
+		productSlug string
+		releaseID   int
+		fileGroupID int
+	}{productSlug, releaseID, fileGroupID})
+	fake.recordInvocation("AddFileGroup", []interface{}{productSlug, releaseID, fileGroupID})
+	fake.addFileGroupMutex.Unlock()
+	if fake.AddFileGroupStub != nil {
+		return fake.AddFileGroupStub(productSlug, releaseID, fileGroupID)
+	} else {
+		return fake.addFileGroupReturns.result1
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddFileGroupCallCount() int {
+	fake.addFileGroupMutex.RLock()
+	defer fake.addFileGroupMutex.RUnlock()
+	return len(fake.addFileGroupArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddFileGroupArgsForCall(i int) (string, int, int) {
+	fake.addFileGroupMutex.RLock()
+	defer fake.addFileGroupMutex.RUnlock()
+	return fake.addFileGroupArgsForCall[i].productSlug, fake.addFileGroupArgsForCall[i].releaseID, fake.addFileGroupArgsForCall[i].fileGroupID
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddFileGroupReturns(result1 error) {
+	fake.AddFileGroupStub = nil
+	fake.addFileGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFileGroup(productSlug string, releaseID int, fileGroupID int) error {
+	fake.removeFileGroupMutex.Lock()
+	fake.removeFileGroupArgsForCall = append(fake.removeFileGroupArgsForCall, struct {
+		productSlug string
+		releaseID   int
+		fileGroupID int
+	}{productSlug, releaseID, fileGroupID})
+	fake.recordInvocation("RemoveFileGroup", []interface{}{productSlug, releaseID, fileGroupID})
+	fake.removeFileGroupMutex.Unlock()
+	if fake.RemoveFileGroupStub != nil {
+		return fake.RemoveFileGroupStub(productSlug, releaseID, fileGroupID)
+	} else {
+		return fake.removeFileGroupReturns.result1
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFileGroupCallCount() int {
+	fake.removeFileGroupMutex.RLock()
+	defer fake.removeFileGroupMutex.RUnlock()
+	return len(fake.removeFileGroupArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFileGroupArgsForCall(i int) (string, int, int) {
+	fake.removeFileGroupMutex.RLock()
+	defer fake.removeFileGroupMutex.RUnlock()
+	return fake.removeFileGroupArgsForCall[i].productSlug, fake.removeFileGroupArgsForCall[i].releaseID, fake.removeFileGroupArgsForCall[i].fileGroupID
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFileGroupReturns(result1 error) {
+	fake.RemoveFileGroupStub = nil
+	fake.removeFileGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddToFileGroup(productSlug string, fileGroupID int, productFileID int) error {
+	fake.addToFileGroupMutex.Lock()
+	fake.addToFileGroupArgsForCall = append(fake.addToFileGroupArgsForCall, struct {
+		productSlug   string
+		fileGroupID   int
+		productFileID int
+	}{productSlug, fileGroupID, productFileID})
+	fake.recordInvocation("AddToFileGroup", []interface{}{productSlug, fileGroupID, productFileID})
+	fake.addToFileGroupMutex.Unlock()
+	if fake.AddToFileGroupStub != nil {
+		return fake.AddToFileGroupStub(productSlug, fileGroupID, productFileID)
+	} else {
+		return fake.addToFileGroupReturns.result1
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddToFileGroupCallCount() int {
+	fake.addToFileGroupMutex.RLock()
+	defer fake.addToFileGroupMutex.RUnlock()
+	return len(fake.addToFileGroupArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddToFileGroupArgsForCall(i int) (string, int, int) {
+	fake.addToFileGroupMutex.RLock()
+	defer fake.addToFileGroupMutex.RUnlock()
+	return fake.addToFileGroupArgsForCall[i].productSlug, fake.addToFileGroupArgsForCall[i].fileGroupID, fake.addToFileGroupArgsForCall[i].productFileID
+}
+
+func (fake *ReleaseFileGroupsAdderClient) AddToFileGroupReturns(result1 error) {
+	fake.AddToFileGroupStub = nil
+	fake.addToFileGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFromFileGroup(productSlug string, fileGroupID int, productFileID int) error {
+	fake.removeFromFileGroupMutex.Lock()
+	fake.removeFromFileGroupArgsForCall = append(fake.removeFromFileGroupArgsForCall, struct {
+		productSlug   string
+		fileGroupID   int
+		productFileID int
+	}{productSlug, fileGroupID, productFileID})
+	fake.recordInvocation("RemoveFromFileGroup", []interface{}{productSlug, fileGroupID, productFileID})
+	fake.removeFromFileGroupMutex.Unlock()
+	if fake.RemoveFromFileGroupStub != nil {
+		return fake.RemoveFromFileGroupStub(productSlug, fileGroupID, productFileID)
+	} else {
+		return fake.removeFromFileGroupReturns.result1
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFromFileGroupCallCount() int {
+	fake.removeFromFileGroupMutex.RLock()
+	defer fake.removeFromFileGroupMutex.RUnlock()
+	return len(fake.removeFromFileGroupArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFromFileGroupArgsForCall(i int) (string, int, int) {
+	fake.removeFromFileGroupMutex.RLock()
+	defer fake.removeFromFileGroupMutex.RUnlock()
+	return fake.removeFromFileGroupArgsForCall[i].productSlug, fake.removeFromFileGroupArgsForCall[i].fileGroupID, fake.removeFromFileGroupArgsForCall[i].productFileID
+}
+
+func (fake *ReleaseFileGroupsAdderClient) RemoveFromFileGroupReturns(result1 error) {
+	fake.RemoveFromFileGroupStub = nil
+	fake.removeFromFileGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) ProductFilesForRelease(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error) {
+	fake.productFilesForReleaseMutex.Lock()
+	fake.productFilesForReleaseArgsForCall = append(fake.productFilesForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ProductFilesForRelease", []interface{}{productSlug, releaseID})
+	fake.productFilesForReleaseMutex.Unlock()
+	if fake.ProductFilesForReleaseStub != nil {
+		return fake.ProductFilesForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.productFilesForReleaseReturns.result1, fake.productFilesForReleaseReturns.result2
+	}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) ProductFilesForReleaseCallCount() int {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return len(fake.productFilesForReleaseArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsAdderClient) ProductFilesForReleaseArgsForCall(i int) (string, int) {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return fake.productFilesForReleaseArgsForCall[i].productSlug, fake.productFilesForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseFileGroupsAdderClient) ProductFilesForReleaseReturns(result1 []go_pivnet.ProductFile, result2 error) {
+	fake.ProductFilesForReleaseStub = nil
+	fake.productFilesForReleaseReturns = struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseFileGroupsAdderClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.fileGroupsMutex.RLock()
+	defer fake.fileGroupsMutex.RUnlock()
+	fake.fileGroupsForReleaseMutex.RLock()
+	defer fake.fileGroupsForReleaseMutex.RUnlock()
+	fake.createFileGroupMutex.RLock()
+	defer fake.createFileGroupMutex.RUnlock()
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
+	fake.addFileGroupMutex.RLock()
+	defer fake.addFileGroupMutex.RUnlock()
+	fake.removeFileGroupMutex.RLock()
+	defer fake.removeFileGroupMutex.RUnlock()
+	fake.addToFileGroupMutex.RLock()
+	defer fake.addToFileGroupMutex.RUnlock()
+	fake.removeFromFileGroupMutex.RLock()
+	defer fake.removeFromFileGroupMutex.RUnlock()
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *ReleaseFileGroupsAdderClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	out_release "github.com/pivotal-cf/pivnet-resource/out/release"
+)
+
+type ReleaseFileGroupsPlanner struct {
+	PlanReleaseFileGroupsStub        func(plan *out_release.Plan, release go_pivnet.Release) error
+	planReleaseFileGroupsMutex       sync.RWMutex
+	planReleaseFileGroupsArgsForCall []struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}
+	planReleaseFileGroupsReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ReleaseFileGroupsPlanner) PlanReleaseFileGroups(plan *out_release.Plan, release go_pivnet.Release) error {
+	fake.planReleaseFileGroupsMutex.Lock()
+	fake.planReleaseFileGroupsArgsForCall = append(fake.planReleaseFileGroupsArgsForCall, struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}{plan, release})
+	fake.recordInvocation("PlanReleaseFileGroups", []interface{}{plan, release})
+	fake.planReleaseFileGroupsMutex.Unlock()
+	if fake.PlanReleaseFileGroupsStub != nil {
+		return fake.PlanReleaseFileGroupsStub(plan, release)
+	} else {
+		return fake.planReleaseFileGroupsReturns.result1
+	}
+}
+
+func (fake *ReleaseFileGroupsPlanner) PlanReleaseFileGroupsCallCount() int {
+	fake.planReleaseFileGroupsMutex.RLock()
+	defer fake.planReleaseFileGroupsMutex.RUnlock()
+	return len(fake.planReleaseFileGroupsArgsForCall)
+}
+
+func (fake *ReleaseFileGroupsPlanner) PlanReleaseFileGroupsArgsForCall(i int) (*out_release.Plan, go_pivnet.Release) {
+	fake.planReleaseFileGroupsMutex.RLock()
+	defer fake.planReleaseFileGroupsMutex.RUnlock()
+	return fake.planReleaseFileGroupsArgsForCall[i].plan, fake.planReleaseFileGroupsArgsForCall[i].release
+}
+
+func (fake *ReleaseFileGroupsPlanner) PlanReleaseFileGroupsReturns(result1 error) {
+	fake.PlanReleaseFileGroupsStub = nil
+	fake.planReleaseFileGroupsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseFileGroupsPlanner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planReleaseFileGroupsMutex.RLock()
+	defer fake.planReleaseFileGroupsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *ReleaseFileGroupsPlanner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -18,6 +18,26 @@ type ReleaseUpgradePathsAdderClient struct {
 	addReleaseUpgradePathReturns struct {
 		result1 error
 	}
+	RemoveReleaseUpgradePathStub        func(productSlug string, releaseID int, previousReleaseID int) error
+	removeReleaseUpgradePathMutex       sync.RWMutex
+	removeReleaseUpgradePathArgsForCall []struct {
+		productSlug       string
+		releaseID         int
+		previousReleaseID int
+	}
+	removeReleaseUpgradePathReturns struct {
+		result1 error
+	}
+	ReleaseUpgradePathsStub        func(productSlug string, releaseID int) ([]go_pivnet.ReleaseUpgradePath, error)
+	releaseUpgradePathsMutex       sync.RWMutex
+	releaseUpgradePathsArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	releaseUpgradePathsReturns struct {
+		result1 []go_pivnet.ReleaseUpgradePath
+		result2 error
+	}
 	ReleasesForProductSlugStub        func(productSlug string) ([]go_pivnet.Release, error)
 	releasesForProductSlugMutex       sync.RWMutex
 	releasesForProductSlugArgsForCall []struct {
@@ -66,6 +86,76 @@ func (fake *ReleaseUpgradePathsAdderClient) AddReleaseUpgradePathReturns(result1
 	}{result1}
 }
 
+func (fake *ReleaseUpgradePathsAdderClient) RemoveReleaseUpgradePath(productSlug string, releaseID int, previousReleaseID int) error {
+	fake.removeReleaseUpgradePathMutex.Lock()
+	fake.removeReleaseUpgradePathArgsForCall = append(fake.removeReleaseUpgradePathArgsForCall, struct {
+		productSlug       string
+		releaseID         int
+		previousReleaseID int
+	}{productSlug, releaseID, previousReleaseID})
+	fake.recordInvocation("RemoveReleaseUpgradePath", []interface{}{productSlug, releaseID, previousReleaseID})
+	fake.removeReleaseUpgradePathMutex.Unlock()
+	if fake.RemoveReleaseUpgradePathStub != nil {
+		return fake.RemoveReleaseUpgradePathStub(productSlug, releaseID, previousReleaseID)
+	} else {
+		return fake.removeReleaseUpgradePathReturns.result1
+	}
+}
+
+func (fake *ReleaseUpgradePathsAdderClient) RemoveReleaseUpgradePathCallCount() int {
+	fake.removeReleaseUpgradePathMutex.RLock()
+	defer fake.removeReleaseUpgradePathMutex.RUnlock()
+	return len(fake.removeReleaseUpgradePathArgsForCall)
+}
+
+func (fake *ReleaseUpgradePathsAdderClient) RemoveReleaseUpgradePathArgsForCall(i int) (string, int, int) {
+	fake.removeReleaseUpgradePathMutex.RLock()
+	defer fake.removeReleaseUpgradePathMutex.RUnlock()
+	return fake.removeReleaseUpgradePathArgsForCall[i].productSlug, fake.removeReleaseUpgradePathArgsForCall[i].releaseID, fake.removeReleaseUpgradePathArgsForCall[i].previousReleaseID
+}
+
+func (fake *ReleaseUpgradePathsAdderClient) RemoveReleaseUpgradePathReturns(result1 error) {
+	fake.RemoveReleaseUpgradePathStub = nil
+	fake.removeReleaseUpgradePathReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseUpgradePathsAdderClient) ReleaseUpgradePaths(productSlug string, releaseID int) ([]go_pivnet.ReleaseUpgradePath, error) {
+	fake.releaseUpgradePathsMutex.Lock()
+	fake.releaseUpgradePathsArgsForCall = append(fake.releaseUpgradePathsArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ReleaseUpgradePaths", []interface{}{productSlug, releaseID})
+	fake.releaseUpgradePathsMutex.Unlock()
+	if fake.ReleaseUpgradePathsStub != nil {
+		return fake.ReleaseUpgradePathsStub(productSlug, releaseID)
+	} else {
+		return fake.releaseUpgradePathsReturns.result1, fake.releaseUpgradePathsReturns.result2
+	}
+}
+
+func (fake *ReleaseUpgradePathsAdderClient) ReleaseUpgradePathsCallCount() int {
+	fake.releaseUpgradePathsMutex.RLock()
+	defer fake.releaseUpgradePathsMutex.RUnlock()
+	return len(fake.releaseUpgradePathsArgsForCall)
+}
+
+func (fake *ReleaseUpgradePathsAdderClient) ReleaseUpgradePathsArgsForCall(i int) (string, int) {
+	fake.releaseUpgradePathsMutex.RLock()
+	defer fake.releaseUpgradePathsMutex.RUnlock()
+	return fake.releaseUpgradePathsArgsForCall[i].productSlug, fake.releaseUpgradePathsArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseUpgradePathsAdderClient) ReleaseUpgradePathsReturns(result1 []go_pivnet.ReleaseUpgradePath, result2 error) {
+	fake.ReleaseUpgradePathsStub = nil
+	fake.releaseUpgradePathsReturns = struct {
+		result1 []go_pivnet.ReleaseUpgradePath
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ReleaseUpgradePathsAdderClient) ReleasesForProductSlug(productSlug string) ([]go_pivnet.Release, error) {
 	fake.releasesForProductSlugMutex.Lock()
 	fake.releasesForProductSlugArgsForCall = append(fake.releasesForProductSlugArgsForCall, struct {
@@ -105,6 +195,10 @@ func (fake *ReleaseUpgradePathsAdderClient) Invocations() map[string][][]interfa
 	defer fake.invocationsMutex.RUnlock()
 	fake.addReleaseUpgradePathMutex.RLock()
 	defer fake.addReleaseUpgradePathMutex.RUnlock()
+	fake.removeReleaseUpgradePathMutex.RLock()
+	defer fake.removeReleaseUpgradePathMutex.RUnlock()
+	fake.releaseUpgradePathsMutex.RLock()
+	defer fake.releaseUpgradePathsMutex.RUnlock()
 	fake.releasesForProductSlugMutex.RLock()
 	defer fake.releasesForProductSlugMutex.RUnlock()
 	return fake.invocations
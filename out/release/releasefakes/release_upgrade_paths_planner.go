@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	out_release "github.com/pivotal-cf/pivnet-resource/out/release"
+)
+
+type ReleaseUpgradePathsPlanner struct {
+	PlanReleaseUpgradePathsStub        func(plan *out_release.Plan, release go_pivnet.Release) error
+	planReleaseUpgradePathsMutex       sync.RWMutex
+	planReleaseUpgradePathsArgsForCall []struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}
+	planReleaseUpgradePathsReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ReleaseUpgradePathsPlanner) PlanReleaseUpgradePaths(plan *out_release.Plan, release go_pivnet.Release) error {
+	fake.planReleaseUpgradePathsMutex.Lock()
+	fake.planReleaseUpgradePathsArgsForCall = append(fake.planReleaseUpgradePathsArgsForCall, struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}{plan, release})
+	fake.recordInvocation("PlanReleaseUpgradePaths", []interface{}{plan, release})
+	fake.planReleaseUpgradePathsMutex.Unlock()
+	if fake.PlanReleaseUpgradePathsStub != nil {
+		return fake.PlanReleaseUpgradePathsStub(plan, release)
+	} else {
+		return fake.planReleaseUpgradePathsReturns.result1
+	}
+}
+
+func (fake *ReleaseUpgradePathsPlanner) PlanReleaseUpgradePathsCallCount() int {
+	fake.planReleaseUpgradePathsMutex.RLock()
+	defer fake.planReleaseUpgradePathsMutex.RUnlock()
+	return len(fake.planReleaseUpgradePathsArgsForCall)
+}
+
+func (fake *ReleaseUpgradePathsPlanner) PlanReleaseUpgradePathsArgsForCall(i int) (*out_release.Plan, go_pivnet.Release) {
+	fake.planReleaseUpgradePathsMutex.RLock()
+	defer fake.planReleaseUpgradePathsMutex.RUnlock()
+	return fake.planReleaseUpgradePathsArgsForCall[i].plan, fake.planReleaseUpgradePathsArgsForCall[i].release
+}
+
+func (fake *ReleaseUpgradePathsPlanner) PlanReleaseUpgradePathsReturns(result1 error) {
+	fake.PlanReleaseUpgradePathsStub = nil
+	fake.planReleaseUpgradePathsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ReleaseUpgradePathsPlanner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planReleaseUpgradePathsMutex.RLock()
+	defer fake.planReleaseUpgradePathsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *ReleaseUpgradePathsPlanner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -4,6 +4,15 @@ package releasefakes
 import "sync"
 
 type S3Client struct {
+	RemotePathStub        func(string) (string, error)
+	remotePathMutex       sync.RWMutex
+	remotePathArgsForCall []struct {
+		arg1 string
+	}
+	remotePathReturns struct {
+		result1 string
+		result2 error
+	}
 	UploadFileStub        func(string) (string, error)
 	uploadFileMutex       sync.RWMutex
 	uploadFileArgsForCall []struct {
@@ -13,10 +22,52 @@ type S3Client struct {
 		result1 string
 		result2 error
 	}
+	DeleteFileStub        func(string) error
+	deleteFileMutex       sync.RWMutex
+	deleteFileArgsForCall []struct {
+		arg1 string
+	}
+	deleteFileReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *S3Client) RemotePath(arg1 string) (string, error) {
+	fake.remotePathMutex.Lock()
+	fake.remotePathArgsForCall = append(fake.remotePathArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("RemotePath", []interface{}{arg1})
+	fake.remotePathMutex.Unlock()
+	if fake.RemotePathStub != nil {
+		return fake.RemotePathStub(arg1)
+	} else {
+		return fake.remotePathReturns.result1, fake.remotePathReturns.result2
+	}
+}
+
+func (fake *S3Client) RemotePathCallCount() int {
+	fake.remotePathMutex.RLock()
+	defer fake.remotePathMutex.RUnlock()
+	return len(fake.remotePathArgsForCall)
+}
+
+func (fake *S3Client) RemotePathArgsForCall(i int) string {
+	fake.remotePathMutex.RLock()
+	defer fake.remotePathMutex.RUnlock()
+	return fake.remotePathArgsForCall[i].arg1
+}
+
+func (fake *S3Client) RemotePathReturns(result1 string, result2 error) {
+	fake.RemotePathStub = nil
+	fake.remotePathReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *S3Client) UploadFile(arg1 string) (string, error) {
 	fake.uploadFileMutex.Lock()
 	fake.uploadFileArgsForCall = append(fake.uploadFileArgsForCall, struct {
@@ -51,11 +102,48 @@ func (fake *S3Client) UploadFileReturns(result1 string, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *S3Client) DeleteFile(arg1 string) error {
+	fake.deleteFileMutex.Lock()
+	fake.deleteFileArgsForCall = append(fake.deleteFileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteFile", []interface{}{arg1})
+	fake.deleteFileMutex.Unlock()
+	if fake.DeleteFileStub != nil {
+		return fake.DeleteFileStub(arg1)
+	} else {
+		return fake.deleteFileReturns.result1
+	}
+}
+
+func (fake *S3Client) DeleteFileCallCount() int {
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
+	return len(fake.deleteFileArgsForCall)
+}
+
+func (fake *S3Client) DeleteFileArgsForCall(i int) string {
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
+	return fake.deleteFileArgsForCall[i].arg1
+}
+
+func (fake *S3Client) DeleteFileReturns(result1 error) {
+	fake.DeleteFileStub = nil
+	fake.deleteFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *S3Client) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.remotePathMutex.RLock()
+	defer fake.remotePathMutex.RUnlock()
 	fake.uploadFileMutex.RLock()
 	defer fake.uploadFileMutex.RUnlock()
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
 	return fake.invocations
 }
 
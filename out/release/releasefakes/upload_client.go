@@ -36,6 +36,26 @@ type UploadClient struct {
 	addProductFileReturns struct {
 		result1 error
 	}
+	RemoveProductFileStub        func(productSlug string, releaseID int, productFileID int) error
+	removeProductFileMutex       sync.RWMutex
+	removeProductFileArgsForCall []struct {
+		productSlug   string
+		releaseID     int
+		productFileID int
+	}
+	removeProductFileReturns struct {
+		result1 error
+	}
+	ProductFilesForReleaseStub        func(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error)
+	productFilesForReleaseMutex       sync.RWMutex
+	productFilesForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	productFilesForReleaseReturns struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}
 	ProductFilesStub        func(productSlug string) ([]go_pivnet.ProductFile, error)
 	productFilesMutex       sync.RWMutex
 	productFilesArgsForCall []struct {
@@ -55,11 +75,11 @@ type UploadClient struct {
 		result1 go_pivnet.ProductFile
 		result2 error
 	}
-	DeleteProductFileStub        func(productSlug string, releaseID int) (go_pivnet.ProductFile, error)
+	DeleteProductFileStub        func(productSlug string, productFileID int) (go_pivnet.ProductFile, error)
 	deleteProductFileMutex       sync.RWMutex
 	deleteProductFileArgsForCall []struct {
-		productSlug string
-		releaseID   int
+		productSlug   string
+		productFileID int
 	}
 	deleteProductFileReturns struct {
 		result1 go_pivnet.ProductFile
@@ -172,6 +192,76 @@ func (fake *UploadClient) AddProductFileReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *UploadClient) RemoveProductFile(productSlug string, releaseID int, productFileID int) error {
+	fake.removeProductFileMutex.Lock()
+	fake.removeProductFileArgsForCall = append(fake.removeProductFileArgsForCall, struct {
+		productSlug   string
+		releaseID     int
+		productFileID int
+	}{productSlug, releaseID, productFileID})
+	fake.recordInvocation("RemoveProductFile", []interface{}{productSlug, releaseID, productFileID})
+	fake.removeProductFileMutex.Unlock()
+	if fake.RemoveProductFileStub != nil {
+		return fake.RemoveProductFileStub(productSlug, releaseID, productFileID)
+	} else {
+		return fake.removeProductFileReturns.result1
+	}
+}
+
+func (fake *UploadClient) RemoveProductFileCallCount() int {
+	fake.removeProductFileMutex.RLock()
+	defer fake.removeProductFileMutex.RUnlock()
+	return len(fake.removeProductFileArgsForCall)
+}
+
+func (fake *UploadClient) RemoveProductFileArgsForCall(i int) (string, int, int) {
+	fake.removeProductFileMutex.RLock()
+	defer fake.removeProductFileMutex.RUnlock()
+	return fake.removeProductFileArgsForCall[i].productSlug, fake.removeProductFileArgsForCall[i].releaseID, fake.removeProductFileArgsForCall[i].productFileID
+}
+
+func (fake *UploadClient) RemoveProductFileReturns(result1 error) {
+	fake.RemoveProductFileStub = nil
+	fake.removeProductFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UploadClient) ProductFilesForRelease(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error) {
+	fake.productFilesForReleaseMutex.Lock()
+	fake.productFilesForReleaseArgsForCall = append(fake.productFilesForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ProductFilesForRelease", []interface{}{productSlug, releaseID})
+	fake.productFilesForReleaseMutex.Unlock()
+	if fake.ProductFilesForReleaseStub != nil {
+		return fake.ProductFilesForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.productFilesForReleaseReturns.result1, fake.productFilesForReleaseReturns.result2
+	}
+}
+
+func (fake *UploadClient) ProductFilesForReleaseCallCount() int {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return len(fake.productFilesForReleaseArgsForCall)
+}
+
+func (fake *UploadClient) ProductFilesForReleaseArgsForCall(i int) (string, int) {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return fake.productFilesForReleaseArgsForCall[i].productSlug, fake.productFilesForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *UploadClient) ProductFilesForReleaseReturns(result1 []go_pivnet.ProductFile, result2 error) {
+	fake.ProductFilesForReleaseStub = nil
+	fake.productFilesForReleaseReturns = struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *UploadClient) ProductFiles(productSlug string) ([]go_pivnet.ProductFile, error) {
 	fake.productFilesMutex.Lock()
 	fake.productFilesArgsForCall = append(fake.productFilesArgsForCall, struct {
@@ -241,16 +331,16 @@ func (fake *UploadClient) ProductFileReturns(result1 go_pivnet.ProductFile, resu
 	}{result1, result2}
 }
 
-func (fake *UploadClient) DeleteProductFile(productSlug string, releaseID int) (go_pivnet.ProductFile, error) {
+func (fake *UploadClient) DeleteProductFile(productSlug string, productFileID int) (go_pivnet.ProductFile, error) {
 	fake.deleteProductFileMutex.Lock()
 	fake.deleteProductFileArgsForCall = append(fake.deleteProductFileArgsForCall, struct {
-		productSlug string
-		releaseID   int
-	}{productSlug, releaseID})
-	fake.recordInvocation("DeleteProductFile", []interface{}{productSlug, releaseID})
+		productSlug   string
+		productFileID int
+	}{productSlug, productFileID})
+	fake.recordInvocation("DeleteProductFile", []interface{}{productSlug, productFileID})
 	fake.deleteProductFileMutex.Unlock()
 	if fake.DeleteProductFileStub != nil {
-		return fake.DeleteProductFileStub(productSlug, releaseID)
+		return fake.DeleteProductFileStub(productSlug, productFileID)
 	} else {
 		return fake.deleteProductFileReturns.result1, fake.deleteProductFileReturns.result2
 	}
@@ -265,7 +355,7 @@ func (fake *UploadClient) DeleteProductFileCallCount() int {
 func (fake *UploadClient) DeleteProductFileArgsForCall(i int) (string, int) {
 	fake.deleteProductFileMutex.RLock()
 	defer fake.deleteProductFileMutex.RUnlock()
-	return fake.deleteProductFileArgsForCall[i].productSlug, fake.deleteProductFileArgsForCall[i].releaseID
+	return fake.deleteProductFileArgsForCall[i].productSlug, fake.deleteProductFileArgsForCall[i].productFileID
 }
 
 func (fake *UploadClient) DeleteProductFileReturns(result1 go_pivnet.ProductFile, result2 error) {
@@ -285,6 +375,10 @@ func (fake *UploadClient) Invocations() map[string][][]interface{} {
 	defer fake.createProductFileMutex.RUnlock()
 	fake.addProductFileMutex.RLock()
 	defer fake.addProductFileMutex.RUnlock()
+	fake.removeProductFileMutex.RLock()
+	defer fake.removeProductFileMutex.RUnlock()
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
 	fake.productFilesMutex.RLock()
 	defer fake.productFilesMutex.RUnlock()
 	fake.productFileMutex.RLock()
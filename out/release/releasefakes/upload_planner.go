@@ -0,0 +1,84 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	out_release "github.com/pivotal-cf/pivnet-resource/out/release"
+)
+
+type UploadPlanner struct {
+	PlanUploadStub        func(plan *out_release.Plan, release go_pivnet.Release, exactGlobs []string) error
+	planUploadMutex       sync.RWMutex
+	planUploadArgsForCall []struct {
+		plan       *out_release.Plan
+		release    go_pivnet.Release
+		exactGlobs []string
+	}
+	planUploadReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *UploadPlanner) PlanUpload(plan *out_release.Plan, release go_pivnet.Release, exactGlobs []string) error {
+	var exactGlobsCopy []string
+	if exactGlobs != nil {
+		exactGlobsCopy = make([]string, len(exactGlobs))
+		copy(exactGlobsCopy, exactGlobs)
+	}
+	fake.planUploadMutex.Lock()
+	fake.planUploadArgsForCall = append(fake.planUploadArgsForCall, struct {
+		plan       *out_release.Plan
+		release    go_pivnet.Release
+		exactGlobs []string
+	}{plan, release, exactGlobsCopy})
+	fake.recordInvocation("PlanUpload", []interface{}{plan, release, exactGlobsCopy})
+	fake.planUploadMutex.Unlock()
+	if fake.PlanUploadStub != nil {
+		return fake.PlanUploadStub(plan, release, exactGlobs)
+	} else {
+		return fake.planUploadReturns.result1
+	}
+}
+
+func (fake *UploadPlanner) PlanUploadCallCount() int {
+	fake.planUploadMutex.RLock()
+	defer fake.planUploadMutex.RUnlock()
+	return len(fake.planUploadArgsForCall)
+}
+
+func (fake *UploadPlanner) PlanUploadArgsForCall(i int) (*out_release.Plan, go_pivnet.Release, []string) {
+	fake.planUploadMutex.RLock()
+	defer fake.planUploadMutex.RUnlock()
+	return fake.planUploadArgsForCall[i].plan, fake.planUploadArgsForCall[i].release, fake.planUploadArgsForCall[i].exactGlobs
+}
+
+func (fake *UploadPlanner) PlanUploadReturns(result1 error) {
+	fake.PlanUploadStub = nil
+	fake.planUploadReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UploadPlanner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planUploadMutex.RLock()
+	defer fake.planUploadMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *UploadPlanner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	out_release "github.com/pivotal-cf/pivnet-resource/out/release"
+)
+
+type UserGroupsPlanner struct {
+	PlanUserGroupsStub        func(plan *out_release.Plan, release go_pivnet.Release) error
+	planUserGroupsMutex       sync.RWMutex
+	planUserGroupsArgsForCall []struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}
+	planUserGroupsReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *UserGroupsPlanner) PlanUserGroups(plan *out_release.Plan, release go_pivnet.Release) error {
+	fake.planUserGroupsMutex.Lock()
+	fake.planUserGroupsArgsForCall = append(fake.planUserGroupsArgsForCall, struct {
+		plan    *out_release.Plan
+		release go_pivnet.Release
+	}{plan, release})
+	fake.recordInvocation("PlanUserGroups", []interface{}{plan, release})
+	fake.planUserGroupsMutex.Unlock()
+	if fake.PlanUserGroupsStub != nil {
+		return fake.PlanUserGroupsStub(plan, release)
+	} else {
+		return fake.planUserGroupsReturns.result1
+	}
+}
+
+func (fake *UserGroupsPlanner) PlanUserGroupsCallCount() int {
+	fake.planUserGroupsMutex.RLock()
+	defer fake.planUserGroupsMutex.RUnlock()
+	return len(fake.planUserGroupsArgsForCall)
+}
+
+func (fake *UserGroupsPlanner) PlanUserGroupsArgsForCall(i int) (*out_release.Plan, go_pivnet.Release) {
+	fake.planUserGroupsMutex.RLock()
+	defer fake.planUserGroupsMutex.RUnlock()
+	return fake.planUserGroupsArgsForCall[i].plan, fake.planUserGroupsArgsForCall[i].release
+}
+
+func (fake *UserGroupsPlanner) PlanUserGroupsReturns(result1 error) {
+	fake.PlanUserGroupsStub = nil
+	fake.planUserGroupsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UserGroupsPlanner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planUserGroupsMutex.RLock()
+	defer fake.planUserGroupsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *UserGroupsPlanner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -28,6 +28,26 @@ type UserGroupsUpdaterClient struct {
 	addUserGroupReturns struct {
 		result1 error
 	}
+	RemoveUserGroupStub        func(productSlug string, releaseID int, userGroupID int) error
+	removeUserGroupMutex       sync.RWMutex
+	removeUserGroupArgsForCall []struct {
+		productSlug string
+		releaseID   int
+		userGroupID int
+	}
+	removeUserGroupReturns struct {
+		result1 error
+	}
+	UserGroupsStub        func(productSlug string, releaseID int) ([]go_pivnet.UserGroup, error)
+	userGroupsMutex       sync.RWMutex
+	userGroupsArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	userGroupsReturns struct {
+		result1 []go_pivnet.UserGroup
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -102,6 +122,76 @@ func (fake *UserGroupsUpdaterClient) AddUserGroupReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *UserGroupsUpdaterClient) RemoveUserGroup(productSlug string, releaseID int, userGroupID int) error {
+	fake.removeUserGroupMutex.Lock()
+	fake.removeUserGroupArgsForCall = append(fake.removeUserGroupArgsForCall, struct {
+		productSlug string
+		releaseID   int
+		userGroupID int
+	}{productSlug, releaseID, userGroupID})
+	fake.recordInvocation("RemoveUserGroup", []interface{}{productSlug, releaseID, userGroupID})
+	fake.removeUserGroupMutex.Unlock()
+	if fake.RemoveUserGroupStub != nil {
+		return fake.RemoveUserGroupStub(productSlug, releaseID, userGroupID)
+	} else {
+		return fake.removeUserGroupReturns.result1
+	}
+}
+
+func (fake *UserGroupsUpdaterClient) RemoveUserGroupCallCount() int {
+	fake.removeUserGroupMutex.RLock()
+	defer fake.removeUserGroupMutex.RUnlock()
+	return len(fake.removeUserGroupArgsForCall)
+}
+
+func (fake *UserGroupsUpdaterClient) RemoveUserGroupArgsForCall(i int) (string, int, int) {
+	fake.removeUserGroupMutex.RLock()
+	defer fake.removeUserGroupMutex.RUnlock()
+	return fake.removeUserGroupArgsForCall[i].productSlug, fake.removeUserGroupArgsForCall[i].releaseID, fake.removeUserGroupArgsForCall[i].userGroupID
+}
+
+func (fake *UserGroupsUpdaterClient) RemoveUserGroupReturns(result1 error) {
+	fake.RemoveUserGroupStub = nil
+	fake.removeUserGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UserGroupsUpdaterClient) UserGroups(productSlug string, releaseID int) ([]go_pivnet.UserGroup, error) {
+	fake.userGroupsMutex.Lock()
+	fake.userGroupsArgsForCall = append(fake.userGroupsArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("UserGroups", []interface{}{productSlug, releaseID})
+	fake.userGroupsMutex.Unlock()
+	if fake.UserGroupsStub != nil {
+		return fake.UserGroupsStub(productSlug, releaseID)
+	} else {
+		return fake.userGroupsReturns.result1, fake.userGroupsReturns.result2
+	}
+}
+
+func (fake *UserGroupsUpdaterClient) UserGroupsCallCount() int {
+	fake.userGroupsMutex.RLock()
+	defer fake.userGroupsMutex.RUnlock()
+	return len(fake.userGroupsArgsForCall)
+}
+
+func (fake *UserGroupsUpdaterClient) UserGroupsArgsForCall(i int) (string, int) {
+	fake.userGroupsMutex.RLock()
+	defer fake.userGroupsMutex.RUnlock()
+	return fake.userGroupsArgsForCall[i].productSlug, fake.userGroupsArgsForCall[i].releaseID
+}
+
+func (fake *UserGroupsUpdaterClient) UserGroupsReturns(result1 []go_pivnet.UserGroup, result2 error) {
+	fake.UserGroupsStub = nil
+	fake.userGroupsReturns = struct {
+		result1 []go_pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *UserGroupsUpdaterClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -109,6 +199,10 @@ func (fake *UserGroupsUpdaterClient) Invocations() map[string][][]interface{} {
 	defer fake.updateReleaseMutex.RUnlock()
 	fake.addUserGroupMutex.RLock()
 	defer fake.addUserGroupMutex.RUnlock()
+	fake.removeUserGroupMutex.RLock()
+	defer fake.removeUserGroupMutex.RUnlock()
+	fake.userGroupsMutex.RLock()
+	defer fake.userGroupsMutex.RUnlock()
 	return fake.invocations
 }
 
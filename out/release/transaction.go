@@ -0,0 +1,109 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+// Transaction records the side effects of a put so that they can be undone,
+// in reverse order, if the put fails. It is safe for concurrent use.
+type Transaction struct {
+	mu           sync.Mutex
+	logger       logger.Logger
+	steps        []transactionStep
+	irreversible []string
+}
+
+type transactionStep struct {
+	description string
+	undo        func() error
+}
+
+//go:generate counterfeiter --fake-name Journal . journal
+type journal interface {
+	Record(description string, undo func() error)
+	RecordIrreversible(description string)
+}
+
+func NewTransaction(logger logger.Logger) *Transaction {
+	return &Transaction{
+		logger: logger,
+	}
+}
+
+// Record registers a side effect along with the function that undoes it. The
+// description names the side effect, e.g. "created release: '1.2.3'".
+func (t *Transaction) Record(description string, undo func() error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.steps = append(t.steps, transactionStep{
+		description: description,
+		undo:        undo,
+	})
+}
+
+// RecordIrreversible registers a side effect which cannot be undone, so that
+// it is reported when rolling back.
+func (t *Transaction) RecordIrreversible(description string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.irreversible = append(t.irreversible, description)
+}
+
+// Rollback undoes the recorded side effects, most recent first, and returns a
+// summary of what was reverted. A step which fails to be undone does not stop
+// the remaining steps from being attempted.
+func (t *Transaction) Rollback() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.steps) == 0 && len(t.irreversible) == 0 {
+		return "Rollback: nothing to revert"
+	}
+
+	var reverted, failed []string
+	for i := len(t.steps) - 1; i >= 0; i-- {
+		step := t.steps[i]
+
+		t.logger.Info(fmt.Sprintf("Rolling back: %s", step.description))
+
+		err := step.undo()
+		if err != nil {
+			t.logger.Info(fmt.Sprintf(
+				"Failed to roll back: %s: %s",
+				step.description,
+				err.Error(),
+			))
+			failed = append(failed, fmt.Sprintf("%s: %s", step.description, err.Error()))
+			continue
+		}
+
+		reverted = append(reverted, step.description)
+	}
+
+	t.steps = nil
+
+	lines := []string{"Rollback:"}
+	lines = append(lines, summaryLines("reverted", reverted)...)
+	lines = append(lines, summaryLines("failed to revert", failed)...)
+	lines = append(lines, summaryLines("could not be reverted", t.irreversible)...)
+
+	return strings.Join(lines, "\n")
+}
+
+func summaryLines(heading string, items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("  %s:", heading)}
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("  - %s", item))
+	}
+	return lines
+}
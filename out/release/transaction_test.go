@@ -0,0 +1,92 @@
+package release_test
+
+import (
+	"errors"
+	"log"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/out/release"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transaction", func() {
+	var (
+		fakeLogger logger.Logger
+
+		transaction *release.Transaction
+	)
+
+	BeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		transaction = release.NewTransaction(fakeLogger)
+	})
+
+	Describe("Rollback", func() {
+		It("undoes recorded side effects in reverse order", func() {
+			var undone []string
+
+			transaction.Record("created release", func() error {
+				undone = append(undone, "release")
+				return nil
+			})
+			transaction.Record("created product file", func() error {
+				undone = append(undone, "product file")
+				return nil
+			})
+
+			summary := transaction.Rollback()
+
+			Expect(undone).To(Equal([]string{"product file", "release"}))
+			Expect(summary).To(ContainSubstring("reverted:\n  - created product file\n  - created release"))
+		})
+
+		It("continues after a step fails and reports it", func() {
+			var undone []string
+
+			transaction.Record("created release", func() error {
+				undone = append(undone, "release")
+				return nil
+			})
+			transaction.Record("created product file", func() error {
+				return errors.New("some error")
+			})
+
+			summary := transaction.Rollback()
+
+			Expect(undone).To(Equal([]string{"release"}))
+			Expect(summary).To(ContainSubstring("failed to revert:\n  - created product file: some error"))
+		})
+
+		It("reports irreversible side effects", func() {
+			transaction.RecordIrreversible("deleted existing release")
+
+			summary := transaction.Rollback()
+
+			Expect(summary).To(ContainSubstring("could not be reverted:\n  - deleted existing release"))
+		})
+
+		It("does not undo a step twice", func() {
+			count := 0
+			transaction.Record("created release", func() error {
+				count++
+				return nil
+			})
+
+			transaction.Rollback()
+			transaction.Rollback()
+
+			Expect(count).To(Equal(1))
+		})
+
+		Context("when nothing was recorded", func() {
+			It("says so", func() {
+				Expect(transaction.Rollback()).To(ContainSubstring("nothing to revert"))
+			})
+		})
+	})
+})
@@ -11,6 +11,7 @@ import (
 type ReleaseUpgradePathsAdder struct {
 	logger      logger.Logger
 	pivnet      releaseUpgradePathsAdderClient
+	journal     journal
 	metadata    metadata.Metadata
 	productSlug string
 	filter      filter
@@ -19,6 +20,7 @@ type ReleaseUpgradePathsAdder struct {
 func NewReleaseUpgradePathsAdder(
 	logger logger.Logger,
 	pivnetClient releaseUpgradePathsAdderClient,
+	journal journal,
 	metadata metadata.Metadata,
 	productSlug string,
 	filter filter,
@@ -26,6 +28,7 @@ func NewReleaseUpgradePathsAdder(
 	return ReleaseUpgradePathsAdder{
 		logger:      logger,
 		pivnet:      pivnetClient,
+		journal:     journal,
 		metadata:    metadata,
 		productSlug: productSlug,
 		filter:      filter,
@@ -35,6 +38,8 @@ func NewReleaseUpgradePathsAdder(
 //go:generate counterfeiter --fake-name ReleaseUpgradePathsAdderClient . releaseUpgradePathsAdderClient
 type releaseUpgradePathsAdderClient interface {
 	AddReleaseUpgradePath(productSlug string, releaseID int, previousReleaseID int) error
+	RemoveReleaseUpgradePath(productSlug string, releaseID int, previousReleaseID int) error
+	ReleaseUpgradePaths(productSlug string, releaseID int) ([]pivnet.ReleaseUpgradePath, error)
 	ReleasesForProductSlug(productSlug string) ([]pivnet.Release, error)
 }
 
@@ -43,17 +48,185 @@ type filter interface {
 	ReleasesByVersion(releases []pivnet.Release, version string) ([]pivnet.Release, error)
 }
 
+// AddReleaseUpgradePaths adds the upgrade paths in the metadata which the
+// release is missing. If the metadata provides the upgrade paths, any others
+// are removed so that they match.
 func (rf ReleaseUpgradePathsAdder) AddReleaseUpgradePaths(release pivnet.Release) error {
 	allReleases, err := rf.pivnet.ReleasesForProductSlug(rf.productSlug)
 	if err != nil {
 		return err
 	}
 
-	upgradeFromReleases := map[pivnet.Release]interface{}{}
+	// An existing release which is updated in place may already have some of
+	// the upgrade paths, or ones which are no longer in the metadata.
+	existingUpgradePaths, err := rf.pivnet.ReleaseUpgradePaths(rf.productSlug, release.ID)
+	if err != nil {
+		return err
+	}
+
+	existing := map[int]bool{}
+	for _, u := range existingUpgradePaths {
+		existing[u.Release.ID] = true
+	}
+
+	upgradeFromReleases, err := rf.upgradeFromReleases(allReleases)
+	if err != nil {
+		return err
+	}
+
+	desired := map[int]bool{}
+
+	for _, r := range upgradeFromReleases {
+		rf.logger.Info(fmt.Sprintf(
+			"Adding upgrade path: '%s'",
+			r.Version,
+		))
+
+		if r.ID == release.ID {
+			rf.logger.Info(fmt.Sprintf("skipping release: %s", r.Version))
+			continue
+		}
+
+		desired[r.ID] = true
+
+		if existing[r.ID] {
+			rf.logger.Info(fmt.Sprintf("upgrade path already exists: %s", r.Version))
+			continue
+		}
+
+		err := rf.pivnet.AddReleaseUpgradePath(rf.productSlug, release.ID, r.ID)
+		if err != nil {
+			return err
+		}
+
+		id := r.ID
+		rf.journal.Record(
+			fmt.Sprintf("added upgrade path from release: '%s' - id: '%d'", r.Version, id),
+			func() error {
+				return rf.pivnet.RemoveReleaseUpgradePath(rf.productSlug, release.ID, id)
+			},
+		)
+	}
+
+	if rf.metadata.UpgradePaths == nil {
+		rf.logger.Info("No upgrade paths provided in metadata - not removing existing upgrade paths")
+		return nil
+	}
+
+	for _, u := range existingUpgradePaths {
+		if desired[u.Release.ID] {
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf(
+			"Removing upgrade path: '%s'",
+			u.Release.Version,
+		))
+
+		err := rf.pivnet.RemoveReleaseUpgradePath(rf.productSlug, release.ID, u.Release.ID)
+		if err != nil {
+			return err
+		}
+
+		id := u.Release.ID
+		rf.journal.Record(
+			fmt.Sprintf("removed upgrade path from release: '%s' - id: '%d'", u.Release.Version, id),
+			func() error {
+				return rf.pivnet.AddReleaseUpgradePath(rf.productSlug, release.ID, id)
+			},
+		)
+	}
+
+	return nil
+}
+
+// PlanReleaseUpgradePaths resolves the upgrade paths in the metadata, without
+// changing anything, and adds what AddReleaseUpgradePaths would do to the
+// plan.
+func (rf ReleaseUpgradePathsAdder) PlanReleaseUpgradePaths(plan *Plan, release pivnet.Release) error {
+	allReleases, err := rf.pivnet.ReleasesForProductSlug(rf.productSlug)
+	if err != nil {
+		return err
+	}
+
+	upgradeFromReleases, err := rf.upgradeFromReleases(allReleases)
+	if err != nil {
+		return err
+	}
+
+	var existingUpgradePaths []pivnet.ReleaseUpgradePath
+	if releaseExists(release) {
+		existingUpgradePaths, err = rf.pivnet.ReleaseUpgradePaths(rf.productSlug, release.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	existing := map[int]bool{}
+	for _, u := range existingUpgradePaths {
+		existing[u.Release.ID] = true
+	}
+
+	desired := map[int]bool{}
+
+	for _, r := range upgradeFromReleases {
+		if r.ID == release.ID || r.Version == release.Version {
+			continue
+		}
+
+		desired[r.ID] = true
+
+		if existing[r.ID] {
+			plan.UpgradePaths = append(plan.UpgradePaths, fmt.Sprintf(
+				"upgrade path from release: '%s' - id: '%d' already exists",
+				r.Version,
+				r.ID,
+			))
+			continue
+		}
+
+		plan.UpgradePaths = append(plan.UpgradePaths, fmt.Sprintf(
+			"add upgrade path from release: '%s' - id: '%d'",
+			r.Version,
+			r.ID,
+		))
+	}
+
+	if rf.metadata.UpgradePaths == nil {
+		return nil
+	}
+
+	for _, u := range existingUpgradePaths {
+		if desired[u.Release.ID] {
+			continue
+		}
+
+		plan.UpgradePaths = append(plan.UpgradePaths, fmt.Sprintf(
+			"remove upgrade path from release: '%s' - id: '%d'",
+			u.Release.Version,
+			u.Release.ID,
+		))
+	}
+
+	return nil
+}
+
+// upgradeFromReleases returns the releases the upgrade paths in the metadata
+// refer to, without duplicates.
+func (rf ReleaseUpgradePathsAdder) upgradeFromReleases(allReleases []pivnet.Release) ([]pivnet.Release, error) {
+	var releases []pivnet.Release
+	seen := map[int]bool{}
+
+	add := func(r pivnet.Release) {
+		if !seen[r.ID] {
+			seen[r.ID] = true
+			releases = append(releases, r)
+		}
+	}
 
 	for i, u := range rf.metadata.UpgradePaths {
 		if u.ID == 0 && u.Version == "" {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"Either id or version must be provided for upgrade_paths[%d]",
 				i,
 			)
@@ -62,44 +235,27 @@ func (rf ReleaseUpgradePathsAdder) AddReleaseUpgradePaths(release pivnet.Release
 		if u.ID == 0 {
 			matchingReleases, err := rf.filter.ReleasesByVersion(allReleases, u.Version)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			if len(matchingReleases) == 0 {
-				return fmt.Errorf("No releases found for version: '%s'", u.Version)
+				return nil, fmt.Errorf("No releases found for version: '%s'", u.Version)
 			}
 
 			for _, r := range matchingReleases {
-				upgradeFromReleases[r] = nil
+				add(r)
 			}
 		} else {
 			r, err := filterReleasesForID(allReleases, u.ID)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			upgradeFromReleases[r] = nil
+			add(r)
 		}
 	}
 
-	for r, _ := range upgradeFromReleases {
-		rf.logger.Info(fmt.Sprintf(
-			"Adding upgrade path: '%s'",
-			r.Version,
-		))
-
-		if r.ID == release.ID {
-			rf.logger.Info(fmt.Sprintf("skipping release: %s", r.Version))
-			continue
-		}
-
-		err := rf.pivnet.AddReleaseUpgradePath(rf.productSlug, release.ID, r.ID)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return releases, nil
 }
 
 func filterReleasesForID(releases []pivnet.Release, id int) (pivnet.Release, error) {
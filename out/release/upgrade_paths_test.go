@@ -23,6 +23,7 @@ var _ = Describe("ReleaseUpgradePathsAdder", func() {
 
 			pivnetClient *releasefakes.ReleaseUpgradePathsAdderClient
 			fakeFilter   *releasefakes.FakeFilter
+			fakeJournal  *releasefakes.Journal
 
 			existingReleases []pivnet.Release
 			filteredReleases []pivnet.Release
@@ -44,6 +45,7 @@ var _ = Describe("ReleaseUpgradePathsAdder", func() {
 
 			pivnetClient = &releasefakes.ReleaseUpgradePathsAdderClient{}
 			fakeFilter = &releasefakes.FakeFilter{}
+			fakeJournal = &releasefakes.Journal{}
 
 			existingReleases = []pivnet.Release{
 				{
@@ -91,6 +93,7 @@ var _ = Describe("ReleaseUpgradePathsAdder", func() {
 			releaseUpgradePathsAdder = release.NewReleaseUpgradePathsAdder(
 				fakeLogger,
 				pivnetClient,
+				fakeJournal,
 				mdata,
 				productSlug,
 				fakeFilter,
@@ -118,6 +121,107 @@ var _ = Describe("ReleaseUpgradePathsAdder", func() {
 				Expect(invokedPreviousReleaseID).To(Equal(existingReleases[0].ID))
 			})
 
+			It("records removing the added upgrade paths", func() {
+				err := releaseUpgradePathsAdder.AddReleaseUpgradePaths(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeJournal.RecordCallCount()).To(Equal(1))
+
+				_, undo := fakeJournal.RecordArgsForCall(0)
+				err = undo()
+				Expect(err).NotTo(HaveOccurred())
+
+				invokedProductSlug, invokedReleaseID, invokedPreviousReleaseID :=
+					pivnetClient.RemoveReleaseUpgradePathArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+				Expect(invokedPreviousReleaseID).To(Equal(existingReleases[0].ID))
+			})
+
+			Context("when the release already has the upgrade path", func() {
+				BeforeEach(func() {
+					pivnetClient.ReleaseUpgradePathsReturns([]pivnet.ReleaseUpgradePath{
+						{Release: pivnet.UpgradePathRelease{ID: existingReleases[0].ID}},
+					}, nil)
+				})
+
+				It("does not add it again", func() {
+					err := releaseUpgradePathsAdder.AddReleaseUpgradePaths(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.AddReleaseUpgradePathCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the release has other upgrade paths", func() {
+				BeforeEach(func() {
+					pivnetClient.ReleaseUpgradePathsReturns([]pivnet.ReleaseUpgradePath{
+						{Release: pivnet.UpgradePathRelease{ID: existingReleases[3].ID}},
+					}, nil)
+				})
+
+				It("removes them", func() {
+					err := releaseUpgradePathsAdder.AddReleaseUpgradePaths(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.RemoveReleaseUpgradePathCallCount()).To(Equal(1))
+					invokedProductSlug, invokedReleaseID, invokedPreviousReleaseID :=
+						pivnetClient.RemoveReleaseUpgradePathArgsForCall(0)
+					Expect(invokedProductSlug).To(Equal(productSlug))
+					Expect(invokedReleaseID).To(Equal(pivnetRelease.ID))
+					Expect(invokedPreviousReleaseID).To(Equal(existingReleases[3].ID))
+				})
+
+				It("records re-adding the removed upgrade paths", func() {
+					err := releaseUpgradePathsAdder.AddReleaseUpgradePaths(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeJournal.RecordCallCount()).To(Equal(2))
+
+					_, undo := fakeJournal.RecordArgsForCall(1)
+					err = undo()
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, invokedPreviousReleaseID := pivnetClient.AddReleaseUpgradePathArgsForCall(1)
+					Expect(invokedPreviousReleaseID).To(Equal(existingReleases[3].ID))
+				})
+
+				Context("when the metadata does not provide upgrade paths", func() {
+					BeforeEach(func() {
+						mdata.UpgradePaths = nil
+					})
+
+					It("does not remove them", func() {
+						err := releaseUpgradePathsAdder.AddReleaseUpgradePaths(pivnetRelease)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(pivnetClient.RemoveReleaseUpgradePathCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when removing an upgrade path returns an error", func() {
+					BeforeEach(func() {
+						pivnetClient.RemoveReleaseUpgradePathReturns(errors.New("remove error"))
+					})
+
+					It("returns an error", func() {
+						err := releaseUpgradePathsAdder.AddReleaseUpgradePaths(pivnetRelease)
+						Expect(err).To(MatchError("remove error"))
+					})
+				})
+			})
+
+			Context("when listing existing upgrade paths returns an error", func() {
+				BeforeEach(func() {
+					pivnetClient.ReleaseUpgradePathsReturns(nil, errors.New("upgrade paths error"))
+				})
+
+				It("returns an error", func() {
+					err := releaseUpgradePathsAdder.AddReleaseUpgradePaths(pivnetRelease)
+					Expect(err).To(MatchError("upgrade paths error"))
+				})
+			})
+
 			Context("when provided ID does not match any existing release", func() {
 				BeforeEach(func() {
 					mdata.UpgradePaths[0].ID = 19283
@@ -207,6 +311,52 @@ var _ = Describe("ReleaseUpgradePathsAdder", func() {
 			})
 		})
 
+		Describe("PlanReleaseUpgradePaths", func() {
+			var (
+				plan release.Plan
+			)
+
+			BeforeEach(func() {
+				plan = release.Plan{}
+
+				mdata.UpgradePaths[0].ID = 0
+				mdata.UpgradePaths[0].Version = "1.2.*"
+
+				pivnetClient.ReleaseUpgradePathsReturns([]pivnet.ReleaseUpgradePath{
+					{Release: pivnet.UpgradePathRelease{ID: 1234, Version: "1.2.3"}},
+					{Release: pivnet.UpgradePathRelease{ID: 3456, Version: "3.4.5"}},
+				}, nil)
+			})
+
+			It("plans the upgrade paths with their resolved release IDs without changing anything", func() {
+				err := releaseUpgradePathsAdder.PlanReleaseUpgradePaths(&plan, pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.UpgradePaths).To(Equal([]string{
+					"upgrade path from release: '1.2.3' - id: '1234' already exists",
+					"add upgrade path from release: '1.2.4' - id: '1235'",
+					"remove upgrade path from release: '3.4.5' - id: '3456'",
+				}))
+
+				Expect(pivnetClient.AddReleaseUpgradePathCallCount()).To(Equal(0))
+				Expect(pivnetClient.RemoveReleaseUpgradePathCallCount()).To(Equal(0))
+			})
+
+			Context("when the release would be created", func() {
+				BeforeEach(func() {
+					pivnetRelease = pivnet.Release{Version: pivnetRelease.Version}
+				})
+
+				It("does not look up its upgrade paths", func() {
+					err := releaseUpgradePathsAdder.PlanReleaseUpgradePaths(&plan, pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.ReleaseUpgradePathsCallCount()).To(Equal(0))
+					Expect(plan.UpgradePaths).To(HaveLen(2))
+				})
+			})
+		})
+
 		Context("when previous release version is empty and ID is 0", func() {
 			BeforeEach(func() {
 				mdata.UpgradePaths[0].Version = ""
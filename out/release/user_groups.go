@@ -9,9 +9,12 @@ import (
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 )
 
+const selectedUserGroupsOnly = "Selected User Groups Only"
+
 type UserGroupsUpdater struct {
 	logger      logger.Logger
 	pivnet      userGroupsUpdaterClient
+	journal     journal
 	metadata    metadata.Metadata
 	productSlug string
 }
@@ -19,12 +22,14 @@ type UserGroupsUpdater struct {
 func NewUserGroupsUpdater(
 	logger logger.Logger,
 	pivnetClient userGroupsUpdaterClient,
+	journal journal,
 	metadata metadata.Metadata,
 	productSlug string,
 ) UserGroupsUpdater {
 	return UserGroupsUpdater{
 		logger:      logger,
 		pivnet:      pivnetClient,
+		journal:     journal,
 		metadata:    metadata,
 		productSlug: productSlug,
 	}
@@ -34,13 +39,26 @@ func NewUserGroupsUpdater(
 type userGroupsUpdaterClient interface {
 	UpdateRelease(productSlug string, release pivnet.Release) (pivnet.Release, error)
 	AddUserGroup(productSlug string, releaseID int, userGroupID int) error
+	RemoveUserGroup(productSlug string, releaseID int, userGroupID int) error
+	UserGroups(productSlug string, releaseID int) ([]pivnet.UserGroup, error)
 }
 
+// UpdateUserGroups sets the availability of the release and, for releases
+// available to selected user groups only, makes its user groups match the
+// metadata. A release which is no longer available to selected user groups
+// only has its user groups removed. The availability is only updated if it
+// differs. If the metadata does not provide the availability, neither the
+// availability nor the user groups are changed.
 func (rf UserGroupsUpdater) UpdateUserGroups(release pivnet.Release) (pivnet.Release, error) {
-
 	availability := rf.metadata.Release.Availability
+	if availability == "" {
+		rf.logger.Info("No availability provided in metadata - not updating availability or user groups")
+		return release, nil
+	}
 
-	if availability != "Admins Only" {
+	previousAvailability := release.Availability
+
+	if release.Availability != availability {
 		releaseUpdate := pivnet.Release{
 			ID:           release.ID,
 			Availability: availability,
@@ -57,26 +75,209 @@ func (rf UserGroupsUpdater) UpdateUserGroups(release pivnet.Release) (pivnet.Rel
 			return pivnet.Release{}, err
 		}
 
-		if availability == "Selected User Groups Only" {
-			userGroupIDs := rf.metadata.Release.UserGroupIDs
+		rf.journal.RecordIrreversible(fmt.Sprintf(
+			"updated availability of release with ID: %d to: '%s'",
+			releaseUpdate.ID,
+			availability,
+		))
+	} else {
+		rf.logger.Info(fmt.Sprintf(
+			"Availability already: '%s'",
+			availability,
+		))
+	}
+
+	switch {
+	case availability == selectedUserGroupsOnly:
+		err := rf.updateUserGroups(release)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+	case previousAvailability == selectedUserGroupsOnly:
+		existingUserGroups, err := rf.pivnet.UserGroups(rf.productSlug, release.ID)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+
+		err = rf.removeUserGroups(release, existingUserGroups, nil)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+	}
+
+	return release, nil
+}
+
+// PlanUserGroups resolves the availability and user groups in the metadata,
+// without changing anything, and adds what UpdateUserGroups would do to the
+// plan. Pivnet creates releases as Admins Only.
+func (rf UserGroupsUpdater) PlanUserGroups(plan *Plan, release pivnet.Release) error {
+	availability := rf.metadata.Release.Availability
+	if availability == "" {
+		return nil
+	}
+
+	previousAvailability := release.Availability
+	if !releaseExists(release) {
+		previousAvailability = "Admins Only"
+	}
+
+	if previousAvailability != availability {
+		plan.UserGroups = append(plan.UserGroups, fmt.Sprintf(
+			"update availability to: '%s'",
+			availability,
+		))
+	}
+
+	if availability != selectedUserGroupsOnly && previousAvailability != selectedUserGroupsOnly {
+		return nil
+	}
+
+	var existingUserGroups []pivnet.UserGroup
+	if releaseExists(release) {
+		var err error
+		existingUserGroups, err = rf.pivnet.UserGroups(rf.productSlug, release.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	existing := map[int]bool{}
+	for _, g := range existingUserGroups {
+		existing[g.ID] = true
+	}
+
+	desired := map[int]bool{}
 
-			for _, userGroupIDString := range userGroupIDs {
-				userGroupID, err := strconv.Atoi(userGroupIDString)
-				if err != nil {
-					return pivnet.Release{}, err
-				}
+	if availability == selectedUserGroupsOnly {
+		for _, userGroupIDString := range rf.metadata.Release.UserGroupIDs {
+			userGroupID, err := strconv.Atoi(userGroupIDString)
+			if err != nil {
+				return err
+			}
+
+			desired[userGroupID] = true
 
-				rf.logger.Info(fmt.Sprintf(
-					"Adding user group with ID: %d",
+			if existing[userGroupID] {
+				plan.UserGroups = append(plan.UserGroups, fmt.Sprintf(
+					"user group - id: '%d' is already added",
 					userGroupID,
 				))
-				err = rf.pivnet.AddUserGroup(rf.productSlug, release.ID, userGroupID)
-				if err != nil {
-					return pivnet.Release{}, err
-				}
+				continue
 			}
+
+			plan.UserGroups = append(plan.UserGroups, fmt.Sprintf(
+				"add user group - id: '%d'",
+				userGroupID,
+			))
+		}
+
+		if rf.metadata.Release.UserGroupIDs == nil {
+			return nil
 		}
 	}
 
-	return release, nil
+	for _, g := range existingUserGroups {
+		if desired[g.ID] {
+			continue
+		}
+
+		plan.UserGroups = append(plan.UserGroups, fmt.Sprintf(
+			"remove user group: '%s' - id: '%d'",
+			g.Name,
+			g.ID,
+		))
+	}
+
+	return nil
+}
+
+// updateUserGroups adds the user groups in the metadata which the release is
+// missing. If the metadata provides the user groups, any others are removed.
+func (rf UserGroupsUpdater) updateUserGroups(release pivnet.Release) error {
+	existingUserGroups, err := rf.pivnet.UserGroups(rf.productSlug, release.ID)
+	if err != nil {
+		return err
+	}
+
+	existing := map[int]bool{}
+	for _, g := range existingUserGroups {
+		existing[g.ID] = true
+	}
+
+	desired := map[int]bool{}
+
+	for _, userGroupIDString := range rf.metadata.Release.UserGroupIDs {
+		userGroupID, err := strconv.Atoi(userGroupIDString)
+		if err != nil {
+			return err
+		}
+
+		desired[userGroupID] = true
+
+		if existing[userGroupID] {
+			rf.logger.Info(fmt.Sprintf(
+				"User group with ID: %d already added",
+				userGroupID,
+			))
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf(
+			"Adding user group with ID: %d",
+			userGroupID,
+		))
+		err = rf.pivnet.AddUserGroup(rf.productSlug, release.ID, userGroupID)
+		if err != nil {
+			return err
+		}
+
+		id := userGroupID
+		rf.journal.Record(
+			fmt.Sprintf("added user group with ID: %d", id),
+			func() error {
+				return rf.pivnet.RemoveUserGroup(rf.productSlug, release.ID, id)
+			},
+		)
+	}
+
+	if rf.metadata.Release.UserGroupIDs == nil {
+		rf.logger.Info("No user groups provided in metadata - not removing existing user groups")
+		return nil
+	}
+
+	return rf.removeUserGroups(release, existingUserGroups, desired)
+}
+
+// removeUserGroups removes the user groups from the release, other than those
+// to keep.
+func (rf UserGroupsUpdater) removeUserGroups(
+	release pivnet.Release,
+	userGroups []pivnet.UserGroup,
+	keep map[int]bool,
+) error {
+	for _, g := range userGroups {
+		if keep[g.ID] {
+			continue
+		}
+
+		rf.logger.Info(fmt.Sprintf(
+			"Removing user group with ID: %d",
+			g.ID,
+		))
+		err := rf.pivnet.RemoveUserGroup(rf.productSlug, release.ID, g.ID)
+		if err != nil {
+			return err
+		}
+
+		id := g.ID
+		rf.journal.Record(
+			fmt.Sprintf("removed user group with ID: %d", id),
+			func() error {
+				return rf.pivnet.AddUserGroup(rf.productSlug, release.ID, id)
+			},
+		)
+	}
+
+	return nil
 }
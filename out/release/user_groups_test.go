@@ -21,6 +21,7 @@ var _ = Describe("UserGroupsUpdater", func() {
 			fakeLogger logger.Logger
 
 			pivnetClient *releasefakes.UserGroupsUpdaterClient
+			fakeJournal  *releasefakes.Journal
 
 			mdata metadata.Metadata
 
@@ -35,11 +36,12 @@ var _ = Describe("UserGroupsUpdater", func() {
 			fakeLogger = logshim.NewLogShim(logger, logger, true)
 
 			pivnetClient = &releasefakes.UserGroupsUpdaterClient{}
+			fakeJournal = &releasefakes.Journal{}
 
 			productSlug = "some-product-slug"
 
 			pivnetRelease = pivnet.Release{
-				Availability: "some-value",
+				Availability: "Admins Only",
 				ID:           1337,
 				Version:      "some-version",
 				EULA: &pivnet.EULA{
@@ -63,6 +65,7 @@ var _ = Describe("UserGroupsUpdater", func() {
 			userGroupsUpdater = release.NewUserGroupsUpdater(
 				fakeLogger,
 				pivnetClient,
+				fakeJournal,
 				mdata,
 				productSlug,
 			)
@@ -76,7 +79,41 @@ var _ = Describe("UserGroupsUpdater", func() {
 
 			invokedProductSlug, invokedReleaseUpdate := pivnetClient.UpdateReleaseArgsForCall(0)
 			Expect(invokedProductSlug).To(Equal(productSlug))
-			Expect(invokedReleaseUpdate).To(Equal(pivnet.Release{ID: pivnetRelease.ID, Availability: pivnetRelease.Availability}))
+			Expect(invokedReleaseUpdate).To(Equal(pivnet.Release{ID: pivnetRelease.ID, Availability: mdata.Release.Availability}))
+
+			Expect(fakeJournal.RecordIrreversibleCallCount()).To(Equal(1))
+		})
+
+		Context("when the release already has the availability", func() {
+			BeforeEach(func() {
+				pivnetRelease.Availability = mdata.Release.Availability
+			})
+
+			It("does not update the release", func() {
+				response, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.UpdateReleaseCallCount()).To(BeZero())
+				Expect(response).To(Equal(pivnetRelease))
+			})
+		})
+
+		Context("when the metadata does not provide the availability", func() {
+			BeforeEach(func() {
+				mdata.Release.Availability = ""
+				pivnetRelease.Availability = "Selected User Groups Only"
+			})
+
+			It("does not update the release or its user groups", func() {
+				response, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(Equal(pivnetRelease))
+				Expect(pivnetClient.UpdateReleaseCallCount()).To(BeZero())
+				Expect(pivnetClient.UserGroupsCallCount()).To(BeZero())
+				Expect(pivnetClient.RemoveUserGroupCallCount()).To(BeZero())
+				Expect(fakeJournal.RecordIrreversibleCallCount()).To(BeZero())
+			})
 		})
 
 		Context("when the release availability is Admins Only", func() {
@@ -90,6 +127,35 @@ var _ = Describe("UserGroupsUpdater", func() {
 
 				Expect(pivnetClient.UpdateReleaseCallCount()).To(BeZero())
 				Expect(pivnetClient.AddUserGroupCallCount()).To(BeZero())
+				Expect(pivnetClient.UserGroupsCallCount()).To(BeZero())
+			})
+
+			Context("when the release was available to selected user groups only", func() {
+				BeforeEach(func() {
+					pivnetRelease.Availability = "Selected User Groups Only"
+
+					pivnetClient.UpdateReleaseReturns(pivnet.Release{ID: 1337, Availability: "Admins Only"}, nil)
+					pivnetClient.UserGroupsReturns([]pivnet.UserGroup{
+						{ID: 111},
+						{ID: 222},
+					}, nil)
+				})
+
+				It("updates the availability and removes all of its user groups", func() {
+					_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.UpdateReleaseCallCount()).To(Equal(1))
+
+					Expect(pivnetClient.RemoveUserGroupCallCount()).To(Equal(2))
+					_, releaseID, userGroupID := pivnetClient.RemoveUserGroupArgsForCall(0)
+					Expect(releaseID).To(Equal(1337))
+					Expect(userGroupID).To(Equal(111))
+					_, _, userGroupID = pivnetClient.RemoveUserGroupArgsForCall(1)
+					Expect(userGroupID).To(Equal(222))
+
+					Expect(fakeJournal.RecordCallCount()).To(Equal(2))
+				})
 			})
 		})
 
@@ -120,6 +186,89 @@ var _ = Describe("UserGroupsUpdater", func() {
 				Expect(response.Version).To(Equal("another-version"))
 			})
 
+			It("records removing the added user groups", func() {
+				_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeJournal.RecordCallCount()).To(Equal(2))
+
+				_, undo := fakeJournal.RecordArgsForCall(0)
+				err = undo()
+				Expect(err).NotTo(HaveOccurred())
+
+				slug, releaseID, userGroupID := pivnetClient.RemoveUserGroupArgsForCall(0)
+				Expect(slug).To(Equal(productSlug))
+				Expect(releaseID).To(Equal(2001))
+				Expect(userGroupID).To(Equal(111))
+			})
+
+			Context("when the release already has user groups", func() {
+				BeforeEach(func() {
+					pivnetClient.UserGroupsReturns([]pivnet.UserGroup{
+						{ID: 111},
+						{ID: 333},
+					}, nil)
+				})
+
+				It("only adds the missing user groups and removes the others", func() {
+					_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					slug, releaseID := pivnetClient.UserGroupsArgsForCall(0)
+					Expect(slug).To(Equal(productSlug))
+					Expect(releaseID).To(Equal(2001))
+
+					Expect(pivnetClient.AddUserGroupCallCount()).To(Equal(1))
+					_, _, userGroupID := pivnetClient.AddUserGroupArgsForCall(0)
+					Expect(userGroupID).To(Equal(222))
+
+					Expect(pivnetClient.RemoveUserGroupCallCount()).To(Equal(1))
+					_, _, userGroupID = pivnetClient.RemoveUserGroupArgsForCall(0)
+					Expect(userGroupID).To(Equal(333))
+				})
+
+				Context("when the metadata does not provide user groups", func() {
+					BeforeEach(func() {
+						mdata.Release.UserGroupIDs = nil
+					})
+
+					It("does not remove the existing user groups", func() {
+						_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(pivnetClient.AddUserGroupCallCount()).To(BeZero())
+						Expect(pivnetClient.RemoveUserGroupCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when the metadata provides no user groups", func() {
+					BeforeEach(func() {
+						mdata.Release.UserGroupIDs = []string{}
+					})
+
+					It("removes all of the existing user groups", func() {
+						_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(pivnetClient.RemoveUserGroupCallCount()).To(Equal(2))
+					})
+				})
+
+				It("records re-adding the removed user groups", func() {
+					_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeJournal.RecordCallCount()).To(Equal(2))
+
+					_, undo := fakeJournal.RecordArgsForCall(1)
+					err = undo()
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, userGroupID := pivnetClient.AddUserGroupArgsForCall(1)
+					Expect(userGroupID).To(Equal(333))
+				})
+			})
+
 			Context("when an error occurs", func() {
 				Context("when a user group ID cannpt be converted to a number", func() {
 					BeforeEach(func() {
@@ -132,6 +281,29 @@ var _ = Describe("UserGroupsUpdater", func() {
 					})
 				})
 
+				Context("when listing the user groups of the release fails", func() {
+					BeforeEach(func() {
+						pivnetClient.UserGroupsReturns(nil, errors.New("failed to list user groups"))
+					})
+
+					It("returns an error", func() {
+						_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+						Expect(err).To(MatchError(errors.New("failed to list user groups")))
+					})
+				})
+
+				Context("when removing a user group from pivnet fails", func() {
+					BeforeEach(func() {
+						pivnetClient.UserGroupsReturns([]pivnet.UserGroup{{ID: 333}}, nil)
+						pivnetClient.RemoveUserGroupReturns(errors.New("failed to remove user group"))
+					})
+
+					It("returns an error", func() {
+						_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+						Expect(err).To(MatchError(errors.New("failed to remove user group")))
+					})
+				})
+
 				Context("when adding a user group to pivnet fails", func() {
 					BeforeEach(func() {
 						pivnetClient.AddUserGroupReturns(errors.New("failed to add user group"))
@@ -145,4 +317,134 @@ var _ = Describe("UserGroupsUpdater", func() {
 			})
 		})
 	})
+
+	Describe("PlanUserGroups", func() {
+		var (
+			fakeLogger logger.Logger
+
+			pivnetClient *releasefakes.UserGroupsUpdaterClient
+
+			mdata         metadata.Metadata
+			pivnetRelease pivnet.Release
+			plan          release.Plan
+
+			userGroupsUpdater release.UserGroupsUpdater
+		)
+
+		BeforeEach(func() {
+			logger := log.New(GinkgoWriter, "", log.LstdFlags)
+			fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+			pivnetClient = &releasefakes.UserGroupsUpdaterClient{}
+
+			pivnetRelease = pivnet.Release{
+				Availability: "Selected User Groups Only",
+				ID:           1337,
+				Version:      "some-version",
+			}
+
+			mdata = metadata.Metadata{
+				Release: &metadata.Release{
+					Availability: "Selected User Groups Only",
+					Version:      "some-version",
+					UserGroupIDs: []string{"6", "8"},
+				},
+			}
+
+			plan = release.Plan{}
+
+			pivnetClient.UserGroupsReturns([]pivnet.UserGroup{
+				{ID: 6, Name: "some-user-group"},
+				{ID: 7, Name: "some-old-user-group"},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			userGroupsUpdater = release.NewUserGroupsUpdater(
+				fakeLogger,
+				pivnetClient,
+				&releasefakes.Journal{},
+				mdata,
+				"some-product-slug",
+			)
+		})
+
+		It("plans the user groups without changing anything", func() {
+			err := userGroupsUpdater.PlanUserGroups(&plan, pivnetRelease)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.UserGroups).To(Equal([]string{
+				"user group - id: '6' is already added",
+				"add user group - id: '8'",
+				"remove user group: 'some-old-user-group' - id: '7'",
+			}))
+
+			Expect(pivnetClient.UpdateReleaseCallCount()).To(Equal(0))
+			Expect(pivnetClient.AddUserGroupCallCount()).To(Equal(0))
+			Expect(pivnetClient.RemoveUserGroupCallCount()).To(Equal(0))
+		})
+
+		Context("when the release does not exist yet", func() {
+			BeforeEach(func() {
+				pivnetRelease = pivnet.Release{Version: "some-version"}
+			})
+
+			It("plans updating the availability from Admins Only", func() {
+				err := userGroupsUpdater.PlanUserGroups(&plan, pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.UserGroupsCallCount()).To(Equal(0))
+
+				Expect(plan.UserGroups).To(Equal([]string{
+					"update availability to: 'Selected User Groups Only'",
+					"add user group - id: '6'",
+					"add user group - id: '8'",
+				}))
+			})
+		})
+
+		Context("when the release is no longer for selected user groups only", func() {
+			BeforeEach(func() {
+				mdata.Release.Availability = "Admins Only"
+			})
+
+			It("plans removing all the user groups", func() {
+				err := userGroupsUpdater.PlanUserGroups(&plan, pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.UserGroups).To(Equal([]string{
+					"update availability to: 'Admins Only'",
+					"remove user group: 'some-user-group' - id: '6'",
+					"remove user group: 'some-old-user-group' - id: '7'",
+				}))
+			})
+		})
+
+		Context("when the availability is not provided", func() {
+			BeforeEach(func() {
+				mdata.Release.Availability = ""
+			})
+
+			It("does not plan changing the availability or user groups", func() {
+				err := userGroupsUpdater.PlanUserGroups(&plan, pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.UserGroups).To(BeEmpty())
+				Expect(pivnetClient.UserGroupsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when user groups are not provided", func() {
+			BeforeEach(func() {
+				mdata.Release.UserGroupIDs = nil
+			})
+
+			It("does not plan removing the existing user groups", func() {
+				err := userGroupsUpdater.PlanUserGroups(&plan, pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.UserGroups).To(BeEmpty())
+			})
+		})
+	})
 })
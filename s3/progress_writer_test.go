@@ -0,0 +1,58 @@
+package s3
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("progressWriter", func() {
+	var (
+		out  *bytes.Buffer
+		lock *sync.Mutex
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		lock = &sync.Mutex{}
+	})
+
+	It("labels each progress update with the file", func() {
+		w := progressWriter{lock: lock, out: out, prefix: "some-file"}
+
+		n, err := w.Write([]byte("\r10 B / 20 B"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(len("\r10 B / 20 B")))
+
+		w.finish()
+
+		Expect(out.String()).To(Equal("\rsome-file: 10 B / 20 B\n"))
+	})
+
+	It("does not interleave the updates of concurrent uploads", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				w := progressWriter{lock: lock, out: out, prefix: fmt.Sprintf("file-%d", i)}
+				for j := 0; j < 50; j++ {
+					_, err := w.Write([]byte("\r20 B / 20 B"))
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		updates := strings.Split(strings.TrimPrefix(out.String(), "\r"), "\r")
+		Expect(updates).To(HaveLen(200))
+		for _, u := range updates {
+			Expect(u).To(MatchRegexp(`^file-\d: 20 B / 20 B$`))
+		}
+	})
+})
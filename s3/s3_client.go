@@ -1,9 +1,11 @@
 package s3
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"path/filepath"
+	"sync"
 
 	"github.com/concourse/s3-resource"
 	"github.com/pivotal-cf/go-pivnet/logger"
@@ -18,7 +20,15 @@ type Client struct {
 	logger logger.Logger
 	stderr io.Writer
 
+	// progressLock serialises writes to stderr, as files may be uploaded
+	// concurrently.
+	progressLock *sync.Mutex
+
 	s3client s3resource.S3Client
+
+	// newS3Client returns a client which writes its progress to the writer.
+	// Each upload has its own so that its progress can be labelled.
+	newS3Client func(progressOutput io.Writer) s3resource.S3Client
 }
 
 type NewClientConfig struct {
@@ -54,8 +64,12 @@ func NewClient(config NewClientConfig) *Client {
 		regionName:      config.RegionName,
 		bucket:          config.Bucket,
 		stderr:          config.Stderr,
+		progressLock:    &sync.Mutex{},
 		logger:          config.Logger,
 		s3client:        s3client,
+		newS3Client: func(progressOutput io.Writer) s3resource.S3Client {
+			return s3resource.NewS3Client(progressOutput, awsConfig)
+		},
 	}
 }
 
@@ -90,7 +104,13 @@ func (c Client) Upload(fileGlob string, to string, sourcesDir string) error {
 		remotePath,
 	))
 
-	_, err = c.s3client.UploadFile(
+	progress := progressWriter{
+		lock:   c.progressLock,
+		out:    c.stderr,
+		prefix: filepath.Base(localPath),
+	}
+
+	_, err = c.newS3Client(progress).UploadFile(
 		c.bucket,
 		remotePath,
 		localPath,
@@ -101,7 +121,7 @@ func (c Client) Upload(fileGlob string, to string, sourcesDir string) error {
 	}
 
 	// the s3client does not append a new-line to its output
-	fmt.Fprintln(c.stderr)
+	progress.finish()
 
 	c.logger.Info(fmt.Sprintf(
 		"Successfully uploaded '%s' to 's3://%s/%s'",
@@ -112,3 +132,41 @@ func (c Client) Upload(fileGlob string, to string, sourcesDir string) error {
 
 	return nil
 }
+
+// progressWriter labels each progress bar update with the file it is for, so
+// that the progress of concurrent uploads is not interleaved on stderr.
+type progressWriter struct {
+	lock   *sync.Mutex
+	out    io.Writer
+	prefix string
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// Each update starts with a carriage return to redraw the bar in place.
+	_, err := fmt.Fprintf(w.out, "\r%s: %s", w.prefix, bytes.TrimPrefix(p, []byte("\r")))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w progressWriter) finish() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	fmt.Fprintln(w.out)
+}
+
+func (c Client) Delete(remotePath string) error {
+	c.logger.Info(fmt.Sprintf(
+		"Deleting s3://%s/%s",
+		c.bucket,
+		remotePath,
+	))
+
+	return c.s3client.DeleteFile(c.bucket, remotePath)
+}
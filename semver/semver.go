@@ -51,3 +51,36 @@ func (s SemverConverter) ToValidSemver(input string) (semver.Version, error) {
 
 	return semver.Version{}, err
 }
+
+// ParseConstraint parses a semver range e.g. ">=1.9.3 <2.0.0".
+// See https://github.com/blang/semver#ranges for the supported syntax.
+func ParseConstraint(constraint string) (semver.Range, error) {
+	r, err := semver.ParseRange(constraint)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse semver constraint: '%s': %s",
+			constraint,
+			err.Error(),
+		)
+	}
+
+	return r, nil
+}
+
+// MatchesConstraint returns whether the input, once converted to valid semver,
+// satisfies the provided semver range.
+// If the input cannot be converted to valid semver it does not match and
+// no error is returned.
+func (s SemverConverter) MatchesConstraint(input string, constraint string) (bool, error) {
+	r, err := ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	v, err := s.ToValidSemver(input)
+	if err != nil {
+		return false, nil
+	}
+
+	return r(v), nil
+}
@@ -105,4 +105,75 @@ var _ = Describe("SemverConverter", func() {
 			})
 		})
 	})
+
+	Describe("MatchesConstraint", func() {
+		var (
+			input      string
+			constraint string
+		)
+
+		BeforeEach(func() {
+			input = "1.9.3"
+			constraint = ">=1.9.3 <2.0.0"
+		})
+
+		It("returns true when the input satisfies the constraint", func() {
+			matches, err := s.MatchesConstraint(input, constraint)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(matches).To(BeTrue())
+		})
+
+		Context("when the input does not satisfy the constraint", func() {
+			BeforeEach(func() {
+				input = "2.0.0"
+			})
+
+			It("returns false", func() {
+				matches, err := s.MatchesConstraint(input, constraint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(matches).To(BeFalse())
+			})
+		})
+
+		Context("when the input has fewer than 3 components", func() {
+			BeforeEach(func() {
+				input = "1.9"
+			})
+
+			It("adds zeros before comparing", func() {
+				matches, err := s.MatchesConstraint(input, constraint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(matches).To(BeFalse())
+			})
+		})
+
+		Context("when the input is not valid semver", func() {
+			BeforeEach(func() {
+				input = "Build 2016-10-04"
+			})
+
+			It("returns false without error", func() {
+				matches, err := s.MatchesConstraint(input, constraint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(matches).To(BeFalse())
+			})
+		})
+
+		Context("when the constraint is not valid", func() {
+			BeforeEach(func() {
+				constraint = "not-a-constraint"
+			})
+
+			It("returns an error", func() {
+				_, err := s.MatchesConstraint(input, constraint)
+				Expect(err).To(HaveOccurred())
+
+				Expect(err.Error()).To(ContainSubstring("not-a-constraint"))
+			})
+		})
+	})
 })
@@ -2,10 +2,13 @@ package sorter
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/blang/semver"
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
 )
 
 //go:generate counterfeiter --fake-name FakeSemverConverter . semverConverter
@@ -64,6 +67,58 @@ func (s Sorter) SortBySemver(input []pivnet.Release) ([]pivnet.Release, error) {
 	return sortedReleases, nil
 }
 
+// SortByReleaseDate returns the provided releases, ordered by release date,
+// in descending order i.e. the most recently released first.
+// Releases with the same release date are ordered by when they were last
+// updated, most recent first.
+// If a release date cannot be parsed, this is logged to stdout and that
+// release is not returned. No error is returned in this case.
+func (s Sorter) SortByReleaseDate(input []pivnet.Release) ([]pivnet.Release, error) {
+	type datedRelease struct {
+		release     pivnet.Release
+		releaseDate time.Time
+		updatedAt   time.Time
+	}
+
+	var dated []datedRelease
+
+	for _, release := range input {
+		releaseDate, err := time.Parse(concourse.ReleaseDateFormat, release.ReleaseDate)
+		if err != nil {
+			s.logger.Info(fmt.Sprintf(
+				"failed to parse release date: '%s' for release: '%s'",
+				release.ReleaseDate,
+				release.Version,
+			))
+			continue
+		}
+
+		// A missing or malformed updated_at only affects the ordering of
+		// releases on the same day, so the zero time is acceptable.
+		updatedAt, _ := time.Parse(time.RFC3339, release.UpdatedAt)
+
+		dated = append(dated, datedRelease{
+			release:     release,
+			releaseDate: releaseDate,
+			updatedAt:   updatedAt,
+		})
+	}
+
+	sort.SliceStable(dated, func(i, j int) bool {
+		if !dated[i].releaseDate.Equal(dated[j].releaseDate) {
+			return dated[i].releaseDate.After(dated[j].releaseDate)
+		}
+		return dated[i].updatedAt.After(dated[j].updatedAt)
+	})
+
+	sortedReleases := make([]pivnet.Release, len(dated))
+	for i, d := range dated {
+		sortedReleases[i] = d.release
+	}
+
+	return sortedReleases, nil
+}
+
 func toStrings(input semver.Versions) []string {
 	strings := make([]string, len(input))
 
@@ -113,6 +113,63 @@ var _ = Describe("Sorter", func() {
 	})
 })
 
+var _ = Describe("SortByReleaseDate", func() {
+	var (
+		s *sorter.Sorter
+	)
+
+	BeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger := logshim.NewLogShim(logger, logger, true)
+
+		s = sorter.NewSorter(fakeLogger, &sorterfakes.FakeSemverConverter{})
+	})
+
+	It("sorts descending by release date", func() {
+		input := []pivnet.Release{
+			{Version: "Build 2016-10-04", ReleaseDate: "2016-10-04"},
+			{Version: "Build 2017-01-12", ReleaseDate: "2017-01-12"},
+			{Version: "Build 2015-03-30", ReleaseDate: "2015-03-30"},
+		}
+
+		returned, err := s.SortByReleaseDate(input)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(versionsFromReleases(returned)).To(Equal(
+			[]string{"Build 2017-01-12", "Build 2016-10-04", "Build 2015-03-30"}))
+	})
+
+	Context("when releases have the same release date", func() {
+		It("orders them by most recently updated", func() {
+			input := []pivnet.Release{
+				{Version: "a", ReleaseDate: "2016-10-04", UpdatedAt: "2016-10-04T09:00:00Z"},
+				{Version: "b", ReleaseDate: "2016-10-04", UpdatedAt: "2016-10-04T17:30:00Z"},
+				{Version: "c", ReleaseDate: "2016-10-03"},
+			}
+
+			returned, err := s.SortByReleaseDate(input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsFromReleases(returned)).To(Equal([]string{"b", "a", "c"}))
+		})
+	})
+
+	Context("when parsing a release date fails", func() {
+		It("ignores that release", func() {
+			input := []pivnet.Release{
+				{Version: "a", ReleaseDate: "2016-10-04"},
+				{Version: "b", ReleaseDate: "not-a-date"},
+				{Version: "c"},
+			}
+
+			returned, err := s.SortByReleaseDate(input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsFromReleases(returned)).To(Equal([]string{"a"}))
+		})
+	})
+})
+
 func releasesWithVersions(versions ...string) []pivnet.Release {
 	var releases []pivnet.Release
 	for _, v := range versions {
@@ -0,0 +1,214 @@
+package unpacker
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+// archiveExtensions are the file extensions of archives that can be unpacked,
+// mapped to whether the archive is a zip (rather than a gzipped tarball).
+var archiveExtensions = []struct {
+	extension string
+	zip       bool
+}{
+	{".tar.gz", false},
+	{".tgz", false},
+	{".zip", true},
+	{".pivotal", true},
+}
+
+type Unpacker struct {
+	deleteArchives bool
+	logger         logger.Logger
+}
+
+func NewUnpacker(deleteArchives bool, logger logger.Logger) *Unpacker {
+	return &Unpacker{
+		deleteArchives: deleteArchives,
+		logger:         logger,
+	}
+}
+
+// Unpack extracts the archive into a directory alongside it, named after the
+// archive without its extension, and returns the path of that directory.
+// Files that are not a supported archive are left untouched and an empty
+// path is returned.
+//
+// Only regular files and directories are extracted; entries that would be
+// written outside of the destination directory cause an error.
+func (u Unpacker) Unpack(archivePath string) (string, error) {
+	for _, a := range archiveExtensions {
+		if !strings.HasSuffix(archivePath, a.extension) {
+			continue
+		}
+
+		destination := strings.TrimSuffix(archivePath, a.extension)
+
+		u.logger.Info(fmt.Sprintf(
+			"Unpacking: '%s' to directory: '%s'",
+			archivePath,
+			destination,
+		))
+
+		err := os.MkdirAll(destination, os.ModePerm)
+		if err != nil {
+			return "", err
+		}
+
+		if a.zip {
+			err = u.unzip(archivePath, destination)
+		} else {
+			err = u.untar(archivePath, destination)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if u.deleteArchives {
+			u.logger.Info(fmt.Sprintf("Deleting unpacked archive: '%s'", archivePath))
+
+			err = os.Remove(archivePath)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return destination, nil
+	}
+
+	u.logger.Debug(fmt.Sprintf("Not unpacking file: '%s' - not an archive", archivePath))
+
+	return "", nil
+}
+
+func (u Unpacker) untar(archivePath string, destination string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := entryPath(destination, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, os.ModePerm)
+		case tar.TypeReg:
+			err = writeFile(target, tr, header.FileInfo().Mode())
+		default:
+			u.logger.Info(fmt.Sprintf(
+				"Skipping unsupported entry: '%s' in archive: '%s'",
+				header.Name,
+				archivePath,
+			))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (u Unpacker) unzip(archivePath string, destination string) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		target, err := entryPath(destination, zf.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := zf.Mode()
+
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, os.ModePerm)
+		case mode.IsRegular():
+			err = unzipFile(zf, target)
+		default:
+			u.logger.Info(fmt.Sprintf(
+				"Skipping unsupported entry: '%s' in archive: '%s'",
+				zf.Name,
+				archivePath,
+			))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unzipFile(zf *zip.File, target string) error {
+	rc, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return writeFile(target, rc, zf.Mode())
+}
+
+// entryPath returns the path an archive entry should be extracted to,
+// returning an error if that path is outside of the destination directory.
+func entryPath(destination string, name string) (string, error) {
+	target := filepath.Join(destination, name)
+
+	if target != filepath.Clean(destination) &&
+		!strings.HasPrefix(target, filepath.Clean(destination)+string(os.PathSeparator)) {
+		return "", fmt.Errorf(
+			"archive entry: '%s' would be extracted outside of directory: '%s'",
+			name,
+			destination,
+		)
+	}
+
+	return target, nil
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(target), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -0,0 +1,276 @@
+package unpacker_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/unpacker"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type archiveEntry struct {
+	name     string
+	contents string
+	dir      bool
+	link     string
+}
+
+func writeTarGz(path string, entries []archiveEntry) {
+	f, err := os.Create(path)
+	Expect(err).NotTo(HaveOccurred())
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	for _, e := range entries {
+		header := &tar.Header{
+			Name:     e.name,
+			Mode:     0644,
+			Size:     int64(len(e.contents)),
+			Typeflag: tar.TypeReg,
+		}
+
+		switch {
+		case e.dir:
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		case e.link != "":
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = e.link
+		}
+
+		err := tw.WriteHeader(header)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = tw.Write([]byte(e.contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
+
+func writeZip(path string, entries []archiveEntry) {
+	f, err := os.Create(path)
+	Expect(err).NotTo(HaveOccurred())
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	defer zw.Close()
+
+	for _, e := range entries {
+		name := e.name
+		if e.dir {
+			name = name + "/"
+		}
+
+		w, err := zw.Create(name)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = w.Write([]byte(e.contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
+
+var _ = Describe("Unpacker", func() {
+	var (
+		fakeLogger logger.Logger
+
+		dir            string
+		entries        []archiveEntry
+		deleteArchives bool
+
+		u *unpacker.Unpacker
+	)
+
+	BeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		var err error
+		dir, err = ioutil.TempDir("", "pivnet-resource")
+		Expect(err).NotTo(HaveOccurred())
+
+		entries = []archiveEntry{
+			{name: "some-dir", dir: true},
+			{name: "some-dir/some-file", contents: "some-contents"},
+			{name: "some-other-file", contents: "some-other-contents"},
+		}
+
+		deleteArchives = false
+	})
+
+	JustBeforeEach(func() {
+		u = unpacker.NewUnpacker(deleteArchives, fakeLogger)
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(dir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	expectUnpacked := func(destination string) {
+		Expect(destination).To(Equal(filepath.Join(dir, "some-archive")))
+
+		contents, err := ioutil.ReadFile(filepath.Join(destination, "some-dir", "some-file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-contents"))
+
+		contents, err = ioutil.ReadFile(filepath.Join(destination, "some-other-file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-other-contents"))
+	}
+
+	for _, extension := range []string{".tgz", ".tar.gz"} {
+		extension := extension
+
+		Describe("Unpack "+extension, func() {
+			var (
+				archivePath string
+			)
+
+			JustBeforeEach(func() {
+				archivePath = filepath.Join(dir, "some-archive"+extension)
+				writeTarGz(archivePath, entries)
+			})
+
+			It("unpacks into a sibling directory and keeps the archive", func() {
+				destination, err := u.Unpack(archivePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectUnpacked(destination)
+
+				_, err = os.Stat(archivePath)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when deleting archives", func() {
+				BeforeEach(func() {
+					deleteArchives = true
+				})
+
+				It("deletes the archive after unpacking", func() {
+					destination, err := u.Unpack(archivePath)
+					Expect(err).NotTo(HaveOccurred())
+
+					expectUnpacked(destination)
+
+					_, err = os.Stat(archivePath)
+					Expect(os.IsNotExist(err)).To(BeTrue())
+				})
+			})
+
+			Context("when an entry is outside of the destination", func() {
+				BeforeEach(func() {
+					entries = append(entries, archiveEntry{
+						name:     "../some-escaped-file",
+						contents: "some-escaped-contents",
+					})
+				})
+
+				It("returns an error without writing the entry", func() {
+					_, err := u.Unpack(archivePath)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("outside of directory"))
+
+					_, err = os.Stat(filepath.Join(dir, "some-escaped-file"))
+					Expect(os.IsNotExist(err)).To(BeTrue())
+				})
+			})
+
+			Context("when the archive contains a symlink", func() {
+				BeforeEach(func() {
+					entries = append(entries, archiveEntry{
+						name: "some-link",
+						link: "/etc",
+					})
+				})
+
+				It("skips the symlink", func() {
+					destination, err := u.Unpack(archivePath)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = os.Lstat(filepath.Join(destination, "some-link"))
+					Expect(os.IsNotExist(err)).To(BeTrue())
+				})
+			})
+
+			Context("when the archive is not valid", func() {
+				JustBeforeEach(func() {
+					err := ioutil.WriteFile(archivePath, []byte("not an archive"), os.ModePerm)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error", func() {
+					_, err := u.Unpack(archivePath)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+	}
+
+	for _, extension := range []string{".zip", ".pivotal"} {
+		extension := extension
+
+		Describe("Unpack "+extension, func() {
+			var (
+				archivePath string
+			)
+
+			JustBeforeEach(func() {
+				archivePath = filepath.Join(dir, "some-archive"+extension)
+				writeZip(archivePath, entries)
+			})
+
+			It("unpacks into a sibling directory", func() {
+				destination, err := u.Unpack(archivePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectUnpacked(destination)
+			})
+
+			Context("when an entry is outside of the destination", func() {
+				BeforeEach(func() {
+					entries = append(entries, archiveEntry{
+						name:     "../some-escaped-file",
+						contents: "some-escaped-contents",
+					})
+				})
+
+				It("returns an error without writing the entry", func() {
+					_, err := u.Unpack(archivePath)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("outside of directory"))
+
+					_, err = os.Stat(filepath.Join(dir, "some-escaped-file"))
+					Expect(os.IsNotExist(err)).To(BeTrue())
+				})
+			})
+		})
+	}
+
+	Context("when the file is not an archive", func() {
+		It("does nothing", func() {
+			filePath := filepath.Join(dir, "some-file.txt")
+			err := ioutil.WriteFile(filePath, []byte("some-contents"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+
+			destination, err := u.Unpack(filePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destination).To(BeEmpty())
+
+			_, err = os.Stat(filePath)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
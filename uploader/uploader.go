@@ -9,6 +9,7 @@ import (
 //go:generate counterfeiter --fake-name FakeTransport . transport
 type transport interface {
 	Upload(fileGlob string, filepathPrefix string, sourcesDir string) error
+	Delete(remotePath string) error
 }
 
 type Client struct {
@@ -34,28 +35,36 @@ func NewClient(config Config) *Client {
 	}
 }
 
-func (c Client) UploadFile(exactGlob string) (string, error) {
+// RemotePath returns the path UploadFile uploads the file to, which is the
+// AWS object key of its product file.
+func (c Client) RemotePath(exactGlob string) (string, error) {
 	if exactGlob == "" {
 		return "", fmt.Errorf("glob must not be empty")
 	}
 
-	filename := filepath.Base(exactGlob)
+	return c.remoteDir() + filepath.Base(exactGlob), nil
+}
 
-	var remoteDir string
+func (c Client) remoteDir() string {
 	switch {
 	case strings.HasPrefix(c.filepathPrefix, "product-files"):
-		remoteDir = c.filepathPrefix + "/"
+		return c.filepathPrefix + "/"
 	case strings.HasPrefix(c.filepathPrefix, "product_files"):
-		remoteDir = c.filepathPrefix + "/"
+		return c.filepathPrefix + "/"
 	default:
-		remoteDir = "product_files/" + c.filepathPrefix + "/"
+		return "product_files/" + c.filepathPrefix + "/"
 	}
+}
 
-	remotePath := fmt.Sprintf("%s%s", remoteDir, filename)
+func (c Client) UploadFile(exactGlob string) (string, error) {
+	remotePath, err := c.RemotePath(exactGlob)
+	if err != nil {
+		return "", err
+	}
 
-	err := c.transport.Upload(
+	err = c.transport.Upload(
 		exactGlob,
-		remoteDir,
+		c.remoteDir(),
 		c.sourcesDir,
 	)
 	if err != nil {
@@ -64,3 +73,13 @@ func (c Client) UploadFile(exactGlob string) (string, error) {
 
 	return remotePath, nil
 }
+
+// DeleteFile deletes a file previously uploaded with UploadFile, identified by
+// the remote path UploadFile returned.
+func (c Client) DeleteFile(remotePath string) error {
+	if remotePath == "" {
+		return fmt.Errorf("remote path must not be empty")
+	}
+
+	return c.transport.Delete(remotePath)
+}
@@ -146,4 +146,77 @@ var _ = Describe("Uploader", func() {
 			})
 		})
 	})
+
+	Describe("RemotePath", func() {
+		var (
+			fakeTransport  *uploaderfakes.FakeTransport
+			uploaderClient *uploader.Client
+		)
+
+		BeforeEach(func() {
+			fakeTransport = &uploaderfakes.FakeTransport{}
+
+			uploaderClient = uploader.NewClient(uploader.Config{
+				FilepathPrefix: "Some-Filepath-Prefix",
+				Transport:      fakeTransport,
+			})
+		})
+
+		It("returns the remote path without uploading", func() {
+			remotePath, err := uploaderClient.RemotePath("my_files/file-0")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(remotePath).To(Equal("product_files/Some-Filepath-Prefix/file-0"))
+			Expect(fakeTransport.UploadCallCount()).To(Equal(0))
+		})
+
+		Context("when the glob is empty", func() {
+			It("returns an error", func() {
+				_, err := uploaderClient.RemotePath("")
+				Expect(err).To(HaveOccurred())
+
+				Expect(err.Error()).To(ContainSubstring("glob"))
+			})
+		})
+	})
+
+	Describe("DeleteFile", func() {
+		var (
+			fakeTransport  *uploaderfakes.FakeTransport
+			uploaderClient *uploader.Client
+		)
+
+		BeforeEach(func() {
+			fakeTransport = &uploaderfakes.FakeTransport{}
+
+			uploaderClient = uploader.NewClient(uploader.Config{
+				Transport: fakeTransport,
+			})
+		})
+
+		It("invokes the transport with the remote path", func() {
+			err := uploaderClient.DeleteFile("product_files/some-prefix/file-0")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTransport.DeleteArgsForCall(0)).To(Equal("product_files/some-prefix/file-0"))
+		})
+
+		Context("when the transport exits with error", func() {
+			BeforeEach(func() {
+				fakeTransport.DeleteReturns(errors.New("some error"))
+			})
+
+			It("propagates errors", func() {
+				err := uploaderClient.DeleteFile("product_files/some-prefix/file-0")
+				Expect(err).To(MatchError("some error"))
+			})
+		})
+
+		Context("when the remote path is empty", func() {
+			It("returns an error", func() {
+				err := uploaderClient.DeleteFile("")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
 })
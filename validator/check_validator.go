@@ -2,8 +2,10 @@ package validator
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/versions"
 )
 
 type CheckValidator struct {
@@ -24,5 +26,50 @@ func (v CheckValidator) Validate() error {
 	if v.input.Source.ProductSlug == "" {
 		return fmt.Errorf("%s must be provided", "product_slug")
 	}
+
+	if v.input.Source.ReleaseType != "" && len(v.input.Source.ReleaseTypes) > 0 {
+		return fmt.Errorf("%s cannot be provided with %s", "release_type", "release_types")
+	}
+
+	if v.input.Source.ParentProductSlug != "" {
+		if v.input.Source.DeployedVersion != "" {
+			return fmt.Errorf("%s cannot be provided with %s", "deployed_version", "parent_product_slug")
+		}
+
+		if v.input.Source.InitialVersions != "" {
+			return fmt.Errorf("%s cannot be provided with %s", "initial_versions", "parent_product_slug")
+		}
+	}
+
+	err := validateProductVersionConstraint(v.input.Source.ProductVersionConstraint)
+	if err != nil {
+		return err
+	}
+
+	err = validateFingerprintMode(v.input.Source.FingerprintMode)
+	if err != nil {
+		return err
+	}
+
+	err = validateRetry(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	_, err = versions.Initial(nil, string(v.input.Source.InitialVersions))
+	if err != nil {
+		return err
+	}
+
+	if v.input.Source.ReleasedAfter != "" {
+		_, err := time.Parse(concourse.ReleaseDateFormat, v.input.Source.ReleasedAfter)
+		if err != nil {
+			return fmt.Errorf(
+				"provided released_after: '%s' must be a date in the format: 'YYYY-MM-DD'",
+				v.input.Source.ReleasedAfter,
+			)
+		}
+	}
+
 	return nil
 }
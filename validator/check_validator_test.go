@@ -13,20 +13,26 @@ var _ = Describe("Check Validator", func() {
 		checkRequest concourse.CheckRequest
 		v            *validator.CheckValidator
 
-		apiToken    string
-		productSlug string
+		apiToken                 string
+		productSlug              string
+		productVersionConstraint string
+		releasedAfter            string
 	)
 
 	BeforeEach(func() {
 		apiToken = "some-api-token"
 		productSlug = "some-productSlug"
+		productVersionConstraint = ""
+		releasedAfter = ""
 	})
 
 	JustBeforeEach(func() {
 		checkRequest = concourse.CheckRequest{
 			Source: concourse.Source{
-				APIToken:    apiToken,
-				ProductSlug: productSlug,
+				APIToken:                 apiToken,
+				ProductSlug:              productSlug,
+				ProductVersionConstraint: productVersionConstraint,
+				ReleasedAfter:            releasedAfter,
 			},
 		}
 		v = validator.NewCheckValidator(checkRequest)
@@ -60,4 +66,144 @@ var _ = Describe("Check Validator", func() {
 			Expect(err.Error()).To(MatchRegexp(".*product_slug.*provided"))
 		})
 	})
+
+	Context("when a valid product version constraint is provided", func() {
+		BeforeEach(func() {
+			productVersionConstraint = ">=1.2.0 <2.0.0"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when the product version constraint is invalid", func() {
+		BeforeEach(func() {
+			productVersionConstraint = "not a constraint"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*semver constraint.*not a constraint"))
+		})
+	})
+
+	Context("when both release type and release types are provided", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.ReleaseType = "Major Release"
+			checkRequest.Source.ReleaseTypes = []string{"Minor Release"}
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*release_type.*cannot be provided with.*release_types"))
+		})
+	})
+
+	Context("when deployed_version is provided with parent_product_slug", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.ParentProductSlug = "some-tile"
+			checkRequest.Source.DeployedVersion = "1.2.3"
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*deployed_version.*cannot be provided with.*parent_product_slug"))
+		})
+	})
+
+	Context("when initial_versions is provided with parent_product_slug", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.ParentProductSlug = "some-tile"
+			checkRequest.Source.InitialVersions = concourse.InitialVersionsAll
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*initial_versions.*cannot be provided with.*parent_product_slug"))
+		})
+	})
+
+	Context("when fingerprint mode is not valid", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.FingerprintMode = "some-mode"
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*fingerprint_mode.*some-mode.*must be one of"))
+		})
+	})
+
+	Context("when retry attempts is negative", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.RetryAttempts = -1
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*retry_attempts.*must not be negative"))
+		})
+	})
+
+	Context("when retry backoff is not a duration", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.RetryBackoff = "some-backoff"
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*retry_backoff.*some-backoff.*must be a positive duration"))
+		})
+	})
+
+	Context("when initial versions is not valid", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.InitialVersions = "-1"
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*initial versions.*-1.*must be one of"))
+		})
+	})
+
+	Context("when released after is a valid date", func() {
+		BeforeEach(func() {
+			releasedAfter = "2016-10-04"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when released after is not a valid date", func() {
+		BeforeEach(func() {
+			releasedAfter = "04/10/2016"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*released_after.*04/10/2016.*YYYY-MM-DD"))
+		})
+	})
 })
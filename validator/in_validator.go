@@ -29,5 +29,43 @@ func (v InValidator) Validate() error {
 		return fmt.Errorf("%s must be provided", "product_version")
 	}
 
+	if v.input.Params.ParallelDownloads < 0 {
+		return fmt.Errorf("%s must not be negative", "parallel_downloads")
+	}
+
+	if v.input.Params.DeleteArchives && !v.input.Params.Unpack {
+		return fmt.Errorf("%s requires %s", "delete_archives", "unpack")
+	}
+
+	if v.input.Params.SkipDownloads {
+		if v.input.Params.Globs != nil {
+			return fmt.Errorf("%s cannot be provided with %s", "globs", "skip_downloads")
+		}
+
+		if v.input.Params.Unpack {
+			return fmt.Errorf("%s cannot be provided with %s", "unpack", "skip_downloads")
+		}
+	}
+
+	err := validateChecksum(v.input.Source.Checksum)
+	if err != nil {
+		return err
+	}
+
+	err = validateProductVersionConstraint(v.input.Source.ProductVersionConstraint)
+	if err != nil {
+		return err
+	}
+
+	err = validateFingerprintMode(v.input.Source.FingerprintMode)
+	if err != nil {
+		return err
+	}
+
+	err = validateRetry(v.input.Source)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
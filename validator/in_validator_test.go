@@ -13,15 +13,27 @@ var _ = Describe("In Validator", func() {
 		inRequest concourse.InRequest
 		v         *validator.InValidator
 
-		apiToken    string
-		productSlug string
-		version     string
+		apiToken          string
+		productSlug       string
+		version           string
+		parallelDownloads int
+		unpack            bool
+		deleteArchives    bool
+		skipDownloads     bool
+		globs             []string
+		checksum          concourse.Checksum
 	)
 
 	BeforeEach(func() {
 		apiToken = "some-api-token"
 		productSlug = "some-productSlug"
 		version = "some-product-version"
+		parallelDownloads = 0
+		unpack = false
+		deleteArchives = false
+		skipDownloads = false
+		globs = nil
+		checksum = ""
 	})
 
 	JustBeforeEach(func() {
@@ -29,8 +41,15 @@ var _ = Describe("In Validator", func() {
 			Source: concourse.Source{
 				APIToken:    apiToken,
 				ProductSlug: productSlug,
+				Checksum:    checksum,
+			},
+			Params: concourse.InParams{
+				ParallelDownloads: parallelDownloads,
+				Unpack:            unpack,
+				DeleteArchives:    deleteArchives,
+				SkipDownloads:     skipDownloads,
+				Globs:             globs,
 			},
-			Params: concourse.InParams{},
 			Version: concourse.Version{
 				ProductVersion: version,
 			},
@@ -79,4 +98,97 @@ var _ = Describe("In Validator", func() {
 			Expect(err.Error()).To(MatchRegexp(".*product_version.*provided"))
 		})
 	})
+
+	Context("when parallel downloads is negative", func() {
+		BeforeEach(func() {
+			parallelDownloads = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*parallel_downloads.*negative"))
+		})
+	})
+
+	Context("when delete archives is provided without unpack", func() {
+		BeforeEach(func() {
+			deleteArchives = true
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*delete_archives.*unpack"))
+		})
+
+		Context("when unpack is also provided", func() {
+			BeforeEach(func() {
+				unpack = true
+			})
+
+			It("returns without error", func() {
+				err := v.Validate()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Context("when skip downloads is provided", func() {
+		BeforeEach(func() {
+			skipDownloads = true
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when globs are also provided", func() {
+			BeforeEach(func() {
+				globs = []string{}
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(MatchRegexp(".*globs.*skip_downloads"))
+			})
+		})
+
+		Context("when unpack is also provided", func() {
+			BeforeEach(func() {
+				unpack = true
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(MatchRegexp(".*unpack.*skip_downloads"))
+			})
+		})
+	})
+
+	Context("when checksum is sha256", func() {
+		BeforeEach(func() {
+			checksum = concourse.ChecksumSHA256
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when checksum is not a supported algorithm", func() {
+		BeforeEach(func() {
+			checksum = "sha1"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*checksum.*sha1.*must be one of"))
+		})
+	})
 })
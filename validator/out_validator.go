@@ -25,6 +25,39 @@ func (v OutValidator) Validate() error {
 		return fmt.Errorf("%s must be provided", "product_slug")
 	}
 
+	err := validateChecksum(v.input.Source.Checksum)
+	if err != nil {
+		return err
+	}
+
+	err = validateProductVersionConstraint(v.input.Source.ProductVersionConstraint)
+	if err != nil {
+		return err
+	}
+
+	err = validateFingerprintMode(v.input.Source.FingerprintMode)
+	if err != nil {
+		return err
+	}
+
+	err = validateRetry(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateOnExistingRelease(v.input.Params.OnExistingRelease)
+	if err != nil {
+		return err
+	}
+
+	if v.input.Params.ParallelUploads < 0 {
+		return fmt.Errorf("%s must not be negative", "parallel_uploads")
+	}
+
+	if v.input.Params.RollbackS3Objects && !v.input.Params.RollbackOnFailure {
+		return fmt.Errorf("%s requires %s", "rollback_s3_objects", "rollback_on_failure")
+	}
+
 	if v.input.Params.FileGlob != "" || v.input.Params.FilepathPrefix != "" {
 		if v.input.Source.AccessKeyID == "" {
 			return fmt.Errorf("%s must be provided", "access_key_id")
@@ -16,6 +16,10 @@ var _ = Describe("Out Validator", func() {
 		productSlug      string
 		fileGlob         string
 		s3FilepathPrefix string
+		checksum         concourse.Checksum
+
+		rollbackOnFailure bool
+		rollbackS3Objects bool
 
 		outRequest concourse.OutRequest
 		v          *validator.OutValidator
@@ -29,6 +33,10 @@ var _ = Describe("Out Validator", func() {
 
 		fileGlob = ""
 		s3FilepathPrefix = ""
+		checksum = ""
+
+		rollbackOnFailure = false
+		rollbackS3Objects = false
 	})
 
 	JustBeforeEach(func() {
@@ -38,10 +46,14 @@ var _ = Describe("Out Validator", func() {
 				ProductSlug:     productSlug,
 				AccessKeyID:     accessKeyID,
 				SecretAccessKey: secretAccessKey,
+				Checksum:        checksum,
 			},
 			Params: concourse.OutParams{
 				FileGlob:       fileGlob,
 				FilepathPrefix: s3FilepathPrefix,
+
+				RollbackOnFailure: rollbackOnFailure,
+				RollbackS3Objects: rollbackS3Objects,
 			},
 		}
 
@@ -52,6 +64,47 @@ var _ = Describe("Out Validator", func() {
 		Expect(v.Validate()).NotTo(HaveOccurred())
 	})
 
+	Context("when on_existing_release is not valid", func() {
+		JustBeforeEach(func() {
+			outRequest.Params.OnExistingRelease = "some-value"
+			v = validator.NewOutValidator(outRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(MatchRegexp(".*on_existing_release.*some-value.*must be one of"))
+		})
+	})
+
+	Context("when parallel_uploads is negative", func() {
+		JustBeforeEach(func() {
+			outRequest.Params.ParallelUploads = -1
+			v = validator.NewOutValidator(outRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(MatchRegexp(".*parallel_uploads.*negative"))
+		})
+	})
+
+	Context("when rollback_s3_objects is provided without rollback_on_failure", func() {
+		BeforeEach(func() {
+			rollbackS3Objects = true
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(MatchRegexp(".*rollback_s3_objects.*requires.*rollback_on_failure"))
+		})
+	})
+
 	Context("when no api token is provided", func() {
 		BeforeEach(func() {
 			apiToken = ""
@@ -78,6 +131,19 @@ var _ = Describe("Out Validator", func() {
 		})
 	})
 
+	Context("when checksum is not a supported algorithm", func() {
+		BeforeEach(func() {
+			checksum = "sha1"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(MatchRegexp(".*checksum.*sha1.*must be one of"))
+		})
+	})
+
 	Context("when file glob is not provided", func() {
 		BeforeEach(func() {
 			fileGlob = ""
@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/semver"
+)
+
+func validateChecksum(checksum concourse.Checksum) error {
+	switch checksum {
+	case "", concourse.ChecksumMD5, concourse.ChecksumSHA256, concourse.ChecksumBoth:
+		return nil
+	}
+
+	return fmt.Errorf(
+		"provided checksum: '%s' must be one of: ['%s', '%s', '%s']",
+		checksum,
+		concourse.ChecksumMD5,
+		concourse.ChecksumSHA256,
+		concourse.ChecksumBoth,
+	)
+}
+
+func validateFingerprintMode(mode concourse.FingerprintMode) error {
+	switch mode {
+	case "", concourse.FingerprintModeUpdatedAt, concourse.FingerprintModeNone, concourse.FingerprintModeFiles:
+		return nil
+	}
+
+	return fmt.Errorf(
+		"provided fingerprint_mode: '%s' must be one of: ['%s', '%s', '%s']",
+		mode,
+		concourse.FingerprintModeUpdatedAt,
+		concourse.FingerprintModeNone,
+		concourse.FingerprintModeFiles,
+	)
+}
+
+func validateOnExistingRelease(onExistingRelease concourse.OnExistingRelease) error {
+	switch onExistingRelease {
+	case "", concourse.OnExistingReleaseFail, concourse.OnExistingReleaseReplace, concourse.OnExistingReleaseUpdate:
+		return nil
+	}
+
+	return fmt.Errorf(
+		"provided on_existing_release: '%s' must be one of: ['%s', '%s', '%s']",
+		onExistingRelease,
+		concourse.OnExistingReleaseFail,
+		concourse.OnExistingReleaseReplace,
+		concourse.OnExistingReleaseUpdate,
+	)
+}
+
+func validateRetry(source concourse.Source) error {
+	if source.RetryAttempts < 0 {
+		return fmt.Errorf("%s must not be negative", "retry_attempts")
+	}
+
+	if source.RetryBackoff == "" {
+		return nil
+	}
+
+	backoff, err := time.ParseDuration(source.RetryBackoff)
+	if err != nil || backoff <= 0 {
+		return fmt.Errorf(
+			"provided retry_backoff: '%s' must be a positive duration, e.g. '2s'",
+			source.RetryBackoff,
+		)
+	}
+
+	return nil
+}
+
+func validateProductVersionConstraint(constraint string) error {
+	if constraint == "" {
+		return nil
+	}
+
+	_, err := semver.ParseConstraint(constraint)
+	return err
+}
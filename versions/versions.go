@@ -2,6 +2,7 @@ package versions
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -9,14 +10,33 @@ const (
 	fingerprintDelimiter = "#"
 )
 
-func Since(versions []string, since string) ([]string, error) {
-	for i, v := range versions {
-		if v == since {
-			return versions[:i], nil
+// Initial returns the newest versions to emit when there is no previous
+// version. initialVersions is either "all", "latest" or a positive number of
+// versions; an empty value is treated as "latest".
+func Initial(versions []string, initialVersions string) ([]string, error) {
+	var count int
+
+	switch initialVersions {
+	case "", "latest":
+		count = 1
+	case "all":
+		count = len(versions)
+	default:
+		var err error
+		count, err = strconv.Atoi(initialVersions)
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf(
+				"initial versions: '%s' must be one of: ['all', 'latest'] or a positive number",
+				initialVersions,
+			)
 		}
 	}
 
-	return versions[:1], nil
+	if count > len(versions) {
+		count = len(versions)
+	}
+
+	return versions[:count], nil
 }
 
 func Reverse(versions []string) ([]string, error) {
@@ -7,58 +7,59 @@ import (
 )
 
 var _ = Describe("Versions", func() {
-	Describe("Since", func() {
+	Describe("Initial", func() {
 		var (
 			allVersions []string
-			version     string
 		)
 
 		BeforeEach(func() {
-			allVersions = []string{}
-			version = ""
+			allVersions = []string{"newest version", "middle version", "oldest version"}
 		})
 
-		Context("when the provided version is the newest", func() {
-			var (
-				allVersions []string
-				version     string
-			)
+		It("returns the newest version when not provided", func() {
+			versions, err := versions.Initial(allVersions, "")
+			Expect(err).NotTo(HaveOccurred())
 
-			BeforeEach(func() {
-				allVersions = []string{"1.2.3#abc", "1.3.2#def"}
-				version = "1.2.3#abc"
-			})
+			Expect(versions).To(Equal([]string{"newest version"}))
+		})
 
-			It("returns empty array", func() {
-				versions, _ := versions.Since(allVersions, version)
+		It("returns the newest version for latest", func() {
+			versions, err := versions.Initial(allVersions, "latest")
+			Expect(err).NotTo(HaveOccurred())
 
-				Expect(versions).To(HaveLen(0))
-			})
+			Expect(versions).To(Equal([]string{"newest version"}))
 		})
 
-		Context("when provided version is present but not the newest", func() {
-			BeforeEach(func() {
-				allVersions = []string{"newest version", "middle version", "older version", "last version"}
-				version = "older version"
-			})
+		It("returns every version for all", func() {
+			versions, err := versions.Initial(allVersions, "all")
+			Expect(err).NotTo(HaveOccurred())
 
-			It("returns new versions", func() {
-				versions, _ := versions.Since(allVersions, version)
+			Expect(versions).To(Equal(allVersions))
+		})
 
-				Expect(versions).To(Equal([]string{"newest version", "middle version"}))
-			})
+		It("returns the newest N versions for a number", func() {
+			versions, err := versions.Initial(allVersions, "2")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versions).To(Equal([]string{"newest version", "middle version"}))
 		})
 
-		Context("When the version is not present", func() {
-			BeforeEach(func() {
-				allVersions = []string{"1.2.3#abc", "1.3.2#def"}
-				version = "1.3.2"
+		Context("when the number is greater than the number of versions", func() {
+			It("returns every version", func() {
+				versions, err := versions.Initial(allVersions, "10")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(versions).To(Equal(allVersions))
 			})
+		})
 
-			It("returns the newest version", func() {
-				versions, _ := versions.Since(allVersions, version)
+		Context("when the value is not valid", func() {
+			It("returns an error", func() {
+				_, err := versions.Initial(allVersions, "0")
+				Expect(err).To(HaveOccurred())
 
-				Expect(versions).To(Equal([]string{"1.2.3#abc"}))
+				_, err = versions.Initial(allVersions, "some")
+				Expect(err).To(HaveOccurred())
 			})
 		})
 	})